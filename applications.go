@@ -0,0 +1,622 @@
+package mockokta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// Okta application sign on modes and statuses, and the scopes of app user assignments, see
+// https://developer.okta.com/docs/reference/api/apps/#application-object
+const (
+	SignOnModeBookmark      = "BOOKMARK"
+	SignOnModeSAML2         = "SAML_2_0"
+	SignOnModeOpenIDConnect = "OPENID_CONNECT"
+
+	ApplicationStatusActive   = "ACTIVE"
+	ApplicationStatusInactive = "INACTIVE"
+
+	AppUserScopeUser    = "USER"
+	AppUserScopeGroup   = "GROUP"
+	AppUserStatusActive = "ACTIVE"
+)
+
+// ApplicationResource contains all the information to add fake applications, and maps of App IDs to
+// the users assigned to the app directly and the groups assigned to it
+type ApplicationResource struct {
+	Client       *MockClient
+	Applications []okta.App
+	AppUsers     map[string][]*okta.AppUser
+	AppGroups    map[string][]*okta.ApplicationGroupAssignment
+}
+
+// CreateApplication is a wrapper to call client.Application.CreateApplication to make it easier to match an interface for the okta client
+func (client *MockClient) CreateApplication(ctx context.Context, body okta.App, qp *query.Params) (okta.App, *okta.Response, error) {
+	return intercept(ctx, client, "CreateApplication", []interface{}{body, qp}, func() (okta.App, *okta.Response, error) {
+		return client.Application.CreateApplication(ctx, body, qp)
+	})
+}
+
+// GetApplication is a wrapper to call client.Application.GetApplication to make it easier to match an interface for the okta client
+func (client *MockClient) GetApplication(ctx context.Context, appID string, appInstance okta.App, qp *query.Params) (okta.App, *okta.Response, error) {
+	return intercept(ctx, client, "GetApplication", []interface{}{appID, appInstance, qp}, func() (okta.App, *okta.Response, error) {
+		return client.Application.GetApplication(ctx, appID, appInstance, qp)
+	})
+}
+
+// ListApplications is a wrapper to call client.Application.ListApplications to make it easier to match an interface for the okta client
+func (client *MockClient) ListApplications(ctx context.Context, qp *query.Params) ([]okta.App, *okta.Response, error) {
+	return intercept(ctx, client, "ListApplications", []interface{}{qp}, func() ([]okta.App, *okta.Response, error) {
+		return client.Application.ListApplications(ctx, qp)
+	})
+}
+
+// DeleteApplication is a wrapper to call client.Application.DeleteApplication to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteApplication(ctx context.Context, appID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteApplication", []interface{}{appID}, func() (*okta.Response, error) {
+		return client.Application.DeleteApplication(ctx, appID)
+	})
+}
+
+// ActivateApplication is a wrapper to call client.Application.ActivateApplication to make it easier to match an interface for the okta client
+func (client *MockClient) ActivateApplication(ctx context.Context, appID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "ActivateApplication", []interface{}{appID}, func() (*okta.Response, error) {
+		return client.Application.ActivateApplication(ctx, appID)
+	})
+}
+
+// DeactivateApplication is a wrapper to call client.Application.DeactivateApplication to make it easier to match an interface for the okta client
+func (client *MockClient) DeactivateApplication(ctx context.Context, appID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeactivateApplication", []interface{}{appID}, func() (*okta.Response, error) {
+		return client.Application.DeactivateApplication(ctx, appID)
+	})
+}
+
+// AssignUserToApplication is a wrapper to call client.Application.AssignUserToApplication to make it easier to match an interface for the okta client
+func (client *MockClient) AssignUserToApplication(ctx context.Context, appID string, body okta.AppUser) (*okta.AppUser, *okta.Response, error) {
+	return intercept(ctx, client, "AssignUserToApplication", []interface{}{appID, body}, func() (*okta.AppUser, *okta.Response, error) {
+		return client.Application.AssignUserToApplication(ctx, appID, body)
+	})
+}
+
+// GetApplicationUser is a wrapper to call client.Application.GetApplicationUser to make it easier to match an interface for the okta client
+func (client *MockClient) GetApplicationUser(ctx context.Context, appID string, userID string, qp *query.Params) (*okta.AppUser, *okta.Response, error) {
+	return intercept(ctx, client, "GetApplicationUser", []interface{}{appID, userID, qp}, func() (*okta.AppUser, *okta.Response, error) {
+		return client.Application.GetApplicationUser(ctx, appID, userID, qp)
+	})
+}
+
+// ListApplicationUsers is a wrapper to call client.Application.ListApplicationUsers to make it easier to match an interface for the okta client
+func (client *MockClient) ListApplicationUsers(ctx context.Context, appID string, qp *query.Params) ([]*okta.AppUser, *okta.Response, error) {
+	return intercept(ctx, client, "ListApplicationUsers", []interface{}{appID, qp}, func() ([]*okta.AppUser, *okta.Response, error) {
+		return client.Application.ListApplicationUsers(ctx, appID, qp)
+	})
+}
+
+// DeleteApplicationUser is a wrapper to call client.Application.DeleteApplicationUser to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteApplicationUser(ctx context.Context, appID string, userID string, qp *query.Params) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteApplicationUser", []interface{}{appID, userID, qp}, func() (*okta.Response, error) {
+		return client.Application.DeleteApplicationUser(ctx, appID, userID, qp)
+	})
+}
+
+// CreateApplicationGroupAssignment is a wrapper to call client.Application.CreateApplicationGroupAssignment to make it easier to match an interface for the okta client
+func (client *MockClient) CreateApplicationGroupAssignment(ctx context.Context, appID string, groupID string, body okta.ApplicationGroupAssignment) (*okta.ApplicationGroupAssignment, *okta.Response, error) {
+	return intercept(ctx, client, "CreateApplicationGroupAssignment", []interface{}{appID, groupID, body}, func() (*okta.ApplicationGroupAssignment, *okta.Response, error) {
+		return client.Application.CreateApplicationGroupAssignment(ctx, appID, groupID, body)
+	})
+}
+
+// GetApplicationGroupAssignment is a wrapper to call client.Application.GetApplicationGroupAssignment to make it easier to match an interface for the okta client
+func (client *MockClient) GetApplicationGroupAssignment(ctx context.Context, appID string, groupID string, qp *query.Params) (*okta.ApplicationGroupAssignment, *okta.Response, error) {
+	return intercept(ctx, client, "GetApplicationGroupAssignment", []interface{}{appID, groupID, qp}, func() (*okta.ApplicationGroupAssignment, *okta.Response, error) {
+		return client.Application.GetApplicationGroupAssignment(ctx, appID, groupID, qp)
+	})
+}
+
+// ListApplicationGroupAssignments is a wrapper to call client.Application.ListApplicationGroupAssignments to make it easier to match an interface for the okta client
+func (client *MockClient) ListApplicationGroupAssignments(ctx context.Context, appID string, qp *query.Params) ([]*okta.ApplicationGroupAssignment, *okta.Response, error) {
+	return intercept(ctx, client, "ListApplicationGroupAssignments", []interface{}{appID, qp}, func() ([]*okta.ApplicationGroupAssignment, *okta.Response, error) {
+		return client.Application.ListApplicationGroupAssignments(ctx, appID, qp)
+	})
+}
+
+// DeleteApplicationGroupAssignment is a wrapper to call client.Application.DeleteApplicationGroupAssignment to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteApplicationGroupAssignment(ctx context.Context, appID string, groupID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteApplicationGroupAssignment", []interface{}{appID, groupID}, func() (*okta.Response, error) {
+		return client.Application.DeleteApplicationGroupAssignment(ctx, appID, groupID)
+	})
+}
+
+// NewBookmarkApplication creates a bookmark app linking to the url
+func NewBookmarkApplication(label string, url string) *okta.BookmarkApplication {
+	app := okta.NewBookmarkApplication()
+	app.Label = label
+	app.Settings = &okta.BookmarkApplicationSettings{App: &okta.BookmarkApplicationSettingsApplication{Url: url}}
+	return app
+}
+
+// NewSamlApplication creates a custom SAML 2.0 app
+func NewSamlApplication(label string) *okta.SamlApplication {
+	app := okta.NewSamlApplication()
+	app.Label = label
+	return app
+}
+
+// NewOpenIDConnectApplication creates a custom OIDC app
+func NewOpenIDConnectApplication(label string) *okta.OpenIdConnectApplication {
+	app := okta.NewOpenIdConnectApplication()
+	app.Label = label
+	return app
+}
+
+// appFields holds pointers to the fields every supported application type has, so apps of any
+// type can be read and changed the same way
+type appFields struct {
+	id          *string
+	name        *string
+	label       *string
+	signOnMode  *string
+	status      *string
+	created     **time.Time
+	lastUpdated **time.Time
+}
+
+// fieldsOf returns the common fields of the app, or false if its type isn't supported
+func fieldsOf(app okta.App) (appFields, bool) {
+	switch a := app.(type) {
+	case *okta.BookmarkApplication:
+		return appFields{&a.Id, &a.Name, &a.Label, &a.SignOnMode, &a.Status, &a.Created, &a.LastUpdated}, true
+	case *okta.SamlApplication:
+		return appFields{&a.Id, &a.Name, &a.Label, &a.SignOnMode, &a.Status, &a.Created, &a.LastUpdated}, true
+	case *okta.OpenIdConnectApplication:
+		return appFields{&a.Id, &a.Name, &a.Label, &a.SignOnMode, &a.Status, &a.Created, &a.LastUpdated}, true
+	case *okta.Application:
+		return appFields{&a.Id, &a.Name, &a.Label, &a.SignOnMode, &a.Status, &a.Created, &a.LastUpdated}, true
+	}
+	return appFields{}, false
+}
+
+// decodeApp decodes an app from JSON into the app type for its sign on mode
+func decodeApp(data []byte) (okta.App, *okta.Error) {
+	var header okta.Application
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, NewError(ErrorCodeMalformedRequest)
+	}
+	var app okta.App
+	switch header.SignOnMode {
+	case SignOnModeBookmark:
+		app = okta.NewBookmarkApplication()
+	case SignOnModeSAML2:
+		app = okta.NewSamlApplication()
+	case SignOnModeOpenIDConnect:
+		app = okta.NewOpenIdConnectApplication()
+	default:
+		return nil, newValidationError("signOnMode", fmt.Sprintf("Unsupported sign on mode '%s'", header.SignOnMode))
+	}
+	if err := json.Unmarshal(data, app); err != nil {
+		return nil, NewError(ErrorCodeMalformedRequest)
+	}
+	return app, nil
+}
+
+// copyApp returns a copy of the app with the type for its sign on mode
+func copyApp(app okta.App) (okta.App, *okta.Error) {
+	data, err := json.Marshal(app)
+	if err != nil {
+		return nil, NewError(ErrorCodeMalformedRequest)
+	}
+	return decodeApp(data)
+}
+
+var appNameReplacer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateApplication adds an app, ACTIVE unless the activate query param is false. Bookmark apps are
+// named "bookmark" and need a url, and custom SAML and OIDC apps get a name generated from their label
+func (a *ApplicationResource) CreateApplication(ctx context.Context, body okta.App, qp *query.Params) (okta.App, *okta.Response, error) {
+	a.Client.mu.Lock()
+	defer a.Client.mu.Unlock()
+
+	app, err := copyApp(body)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	fields, ok := fieldsOf(app)
+	if !ok {
+		err := NewError(ErrorCodeInternalError)
+		return nil, errorResponse(err), err
+	}
+	if *fields.label == "" {
+		err := newValidationError("label", "The field cannot be left blank")
+		return nil, errorResponse(err), err
+	}
+	switch *fields.signOnMode {
+	case SignOnModeBookmark:
+		bookmark := app.(*okta.BookmarkApplication)
+		if bookmark.Settings == nil || bookmark.Settings.App == nil || bookmark.Settings.App.Url == "" {
+			err := newValidationError("url", "The field cannot be left blank")
+			return nil, errorResponse(err), err
+		}
+		*fields.name = "bookmark"
+	case SignOnModeOpenIDConnect:
+		*fields.name = "oidc_client"
+	default:
+		*fields.name = a.newApplicationName(*fields.label)
+	}
+	now := time.Now().UTC()
+	*fields.id = a.Client.ids.NewID(ApplicationIDPrefix)
+	*fields.status = ApplicationStatusActive
+	if qp != nil && qp.Activate != nil && !*qp.Activate {
+		*fields.status = ApplicationStatusInactive
+	}
+	*fields.created = &now
+	*fields.lastUpdated = &now
+	a.Applications = append(a.Applications, app)
+	return app, newResponse(http.StatusOK, app), nil
+}
+
+// newApplicationName generates a name for a custom app from its label the way Okta does, numbering
+// it so it is unique
+func (a *ApplicationResource) newApplicationName(label string) string {
+	prefix := "mockokta_" + strings.Trim(appNameReplacer.ReplaceAllString(strings.ToLower(label), "_"), "_")
+	for n := 1; ; n++ {
+		name := fmt.Sprintf("%s_%d", prefix, n)
+		taken := false
+		for _, app := range a.Applications {
+			if fields, ok := fieldsOf(app); ok && *fields.name == name {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return name
+		}
+	}
+}
+
+// GetApplication returns the app with the appID. If appInstance is not nil the app is copied into it,
+// like the okta client decoding the response into it
+func (a *ApplicationResource) GetApplication(ctx context.Context, appID string, appInstance okta.App, qp *query.Params) (okta.App, *okta.Response, error) {
+	a.Client.mu.RLock()
+	defer a.Client.mu.RUnlock()
+
+	app, err := a.getApplication(appID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	if appInstance != nil {
+		data, _ := json.Marshal(app)
+		if err := json.Unmarshal(data, appInstance); err == nil {
+			return appInstance, newResponse(http.StatusOK, app), nil
+		}
+	}
+	return app, newResponse(http.StatusOK, app), nil
+}
+
+func (a *ApplicationResource) getApplication(appID string) (okta.App, *okta.Error) {
+	app, _, err := a.getApplicationFields(appID)
+	return app, err
+}
+
+// getApplicationFields returns the app with the appID and its common fields. Apps of types fieldsOf
+// doesn't support, which can only be added to Applications directly, are never found
+func (a *ApplicationResource) getApplicationFields(appID string) (okta.App, appFields, *okta.Error) {
+	for _, app := range a.Applications {
+		if fields, ok := fieldsOf(app); ok && *fields.id == appID {
+			return app, fields, nil
+		}
+	}
+	return nil, appFields{}, newNotFoundError("AppInstance", appID)
+}
+
+// ListApplications returns the apps matching the q and filter query params, where q matches the start
+// of an app's name or label and filter can compare an app's status or name
+func (a *ApplicationResource) ListApplications(ctx context.Context, qp *query.Params) ([]okta.App, *okta.Response, error) {
+	a.Client.mu.RLock()
+	defer a.Client.mu.RUnlock()
+
+	apps, err := matchApplications(a.Applications, qp)
+	if err != nil {
+		err := NewError(ErrorCodeInvalidSearch, err.Error())
+		return nil, errorResponse(err), err
+	}
+	page, after := paginate(apps, func(app okta.App) string {
+		if fields, ok := fieldsOf(app); ok {
+			return *fields.id
+		}
+		return ""
+	}, qp)
+	return page, a.Client.listResponse(ctx, "/api/v1/apps", qp, after), nil
+}
+
+// DeleteApplication removes an INACTIVE app along with its user and group assignments
+func (a *ApplicationResource) DeleteApplication(ctx context.Context, appID string) (*okta.Response, error) {
+	a.Client.mu.Lock()
+	defer a.Client.mu.Unlock()
+
+	app, fields, err := a.getApplicationFields(appID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if *fields.status != ApplicationStatusInactive {
+		err := NewError(ErrorCodeDeleteAppForbidden, "Application must be deactivated before it can be deleted")
+		return errorResponse(err), err
+	}
+	for idx, x := range a.Applications {
+		if x == app {
+			a.Applications = append(a.Applications[:idx], a.Applications[idx+1:]...)
+			break
+		}
+	}
+	delete(a.AppUsers, appID)
+	delete(a.AppGroups, appID)
+	a.Client.removeApplicationFromRoleTargets(appID)
+	a.Client.IAM.removeResources("apps/" + appID)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// ActivateApplication makes the app with the appID ACTIVE
+func (a *ApplicationResource) ActivateApplication(ctx context.Context, appID string) (*okta.Response, error) {
+	return a.setApplicationStatus(appID, ApplicationStatusActive)
+}
+
+// DeactivateApplication makes the app with the appID INACTIVE, keeping its assignments
+func (a *ApplicationResource) DeactivateApplication(ctx context.Context, appID string) (*okta.Response, error) {
+	return a.setApplicationStatus(appID, ApplicationStatusInactive)
+}
+
+func (a *ApplicationResource) setApplicationStatus(appID string, status string) (*okta.Response, error) {
+	a.Client.mu.Lock()
+	defer a.Client.mu.Unlock()
+
+	_, fields, err := a.getApplicationFields(appID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if *fields.status != status {
+		now := time.Now().UTC()
+		*fields.status = status
+		*fields.lastUpdated = &now
+	}
+	return newResponse(http.StatusOK, struct{}{}), nil
+}
+
+// AssignUserToApplication assigns the user with the body's ID to the app directly, or updates the
+// profile of its direct assignment if it already has one
+func (a *ApplicationResource) AssignUserToApplication(ctx context.Context, appID string, body okta.AppUser) (*okta.AppUser, *okta.Response, error) {
+	a.Client.mu.Lock()
+	defer a.Client.mu.Unlock()
+
+	if _, err := a.getApplication(appID); err != nil {
+		return nil, errorResponse(err), err
+	}
+	user, err := a.Client.User.getUserByID(body.Id)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	for _, appUser := range a.AppUsers[appID] {
+		if appUser.Id == user.Id {
+			appUser.Profile = body.Profile
+			appUser.LastUpdated = &now
+			return appUser, newResponse(http.StatusOK, appUser), nil
+		}
+	}
+	appUser := &okta.AppUser{
+		Id:            user.Id,
+		Created:       &now,
+		LastUpdated:   &now,
+		StatusChanged: &now,
+		Profile:       body.Profile,
+		Scope:         AppUserScopeUser,
+		Status:        AppUserStatusActive,
+		Credentials:   &okta.AppUserCredentials{UserName: userLogin(user)},
+	}
+	if body.Credentials != nil && body.Credentials.UserName != "" {
+		appUser.Credentials.UserName = body.Credentials.UserName
+	}
+	a.AppUsers[appID] = append(a.AppUsers[appID], appUser)
+	return appUser, newResponse(http.StatusOK, appUser), nil
+}
+
+// GetApplicationUser returns the effective assignment of the user with the userID to the app
+func (a *ApplicationResource) GetApplicationUser(ctx context.Context, appID string, userID string, qp *query.Params) (*okta.AppUser, *okta.Response, error) {
+	a.Client.mu.RLock()
+	defer a.Client.mu.RUnlock()
+
+	if _, err := a.getApplication(appID); err != nil {
+		return nil, errorResponse(err), err
+	}
+	for _, appUser := range a.applicationUsers(appID) {
+		if appUser.Id == userID {
+			return appUser, newResponse(http.StatusOK, appUser), nil
+		}
+	}
+	err := newNotFoundError("AppUser", userID)
+	return nil, errorResponse(err), err
+}
+
+// ListApplicationUsers returns the users assigned to the app, directly with the USER scope or through
+// one of the app's groups with the GROUP scope
+func (a *ApplicationResource) ListApplicationUsers(ctx context.Context, appID string, qp *query.Params) ([]*okta.AppUser, *okta.Response, error) {
+	a.Client.mu.RLock()
+	defer a.Client.mu.RUnlock()
+
+	if _, err := a.getApplication(appID); err != nil {
+		return nil, errorResponse(err), err
+	}
+	page, after := paginate(a.applicationUsers(appID), func(appUser *okta.AppUser) string { return appUser.Id }, qp)
+	return page, a.Client.listResponse(ctx, fmt.Sprintf("/api/v1/apps/%s/users", appID), qp, after), nil
+}
+
+// applicationUsers computes the effective assignments of the app: its direct assignments, followed by
+// an assignment for each other member of its groups in the order the groups were assigned
+func (a *ApplicationResource) applicationUsers(appID string) []*okta.AppUser {
+	appUsers := append([]*okta.AppUser{}, a.AppUsers[appID]...)
+	assigned := make(map[string]bool)
+	for _, appUser := range appUsers {
+		assigned[appUser.Id] = true
+	}
+	for _, assignment := range a.AppGroups[appID] {
+		for _, userID := range a.Client.Group.GroupUsers[assignment.Id] {
+			user, err := a.Client.User.getUserByID(userID)
+			if err != nil || assigned[userID] {
+				continue
+			}
+			assigned[userID] = true
+			appUsers = append(appUsers, &okta.AppUser{
+				Id:            user.Id,
+				Created:       assignment.LastUpdated,
+				LastUpdated:   assignment.LastUpdated,
+				StatusChanged: assignment.LastUpdated,
+				Profile:       assignment.Profile,
+				Scope:         AppUserScopeGroup,
+				Status:        AppUserStatusActive,
+				Credentials:   &okta.AppUserCredentials{UserName: userLogin(user)},
+			})
+		}
+	}
+	return appUsers
+}
+
+// DeleteApplicationUser removes the direct assignment of the user with the userID from the app. Users
+// assigned through a group stay assigned until they leave the group or it is unassigned
+func (a *ApplicationResource) DeleteApplicationUser(ctx context.Context, appID string, userID string, qp *query.Params) (*okta.Response, error) {
+	a.Client.mu.Lock()
+	defer a.Client.mu.Unlock()
+
+	if _, err := a.getApplication(appID); err != nil {
+		return errorResponse(err), err
+	}
+	for idx, appUser := range a.AppUsers[appID] {
+		if appUser.Id == userID {
+			a.AppUsers[appID] = append(a.AppUsers[appID][:idx], a.AppUsers[appID][idx+1:]...)
+			return newResponse(http.StatusNoContent, nil), nil
+		}
+	}
+	err := newNotFoundError("AppUser", userID)
+	return errorResponse(err), err
+}
+
+// CreateApplicationGroupAssignment assigns the group with the groupID to the app, replacing its
+// existing assignment if it has one. Assignments without a priority are given the lowest one
+func (a *ApplicationResource) CreateApplicationGroupAssignment(ctx context.Context, appID string, groupID string, body okta.ApplicationGroupAssignment) (*okta.ApplicationGroupAssignment, *okta.Response, error) {
+	a.Client.mu.Lock()
+	defer a.Client.mu.Unlock()
+
+	if _, err := a.getApplication(appID); err != nil {
+		return nil, errorResponse(err), err
+	}
+	group, err := a.Client.Group.getGroupByID(groupID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	assignment := &okta.ApplicationGroupAssignment{
+		Id:          group.Id,
+		LastUpdated: &now,
+		Priority:    body.Priority,
+		Profile:     body.Profile,
+	}
+	for idx, existing := range a.AppGroups[appID] {
+		if existing.Id == group.Id {
+			if assignment.Priority == nil {
+				assignment.Priority = existing.Priority
+			}
+			a.AppGroups[appID][idx] = assignment
+			return assignment, newResponse(http.StatusOK, assignment), nil
+		}
+	}
+	if assignment.Priority == nil {
+		priority := int64(len(a.AppGroups[appID]))
+		assignment.Priority = &priority
+	}
+	a.AppGroups[appID] = append(a.AppGroups[appID], assignment)
+	return assignment, newResponse(http.StatusOK, assignment), nil
+}
+
+// GetApplicationGroupAssignment returns the assignment of the group with the groupID to the app
+func (a *ApplicationResource) GetApplicationGroupAssignment(ctx context.Context, appID string, groupID string, qp *query.Params) (*okta.ApplicationGroupAssignment, *okta.Response, error) {
+	a.Client.mu.RLock()
+	defer a.Client.mu.RUnlock()
+
+	if _, err := a.getApplication(appID); err != nil {
+		return nil, errorResponse(err), err
+	}
+	for _, assignment := range a.AppGroups[appID] {
+		if assignment.Id == groupID {
+			return assignment, newResponse(http.StatusOK, assignment), nil
+		}
+	}
+	err := newNotFoundError("ApplicationGroupAssignment", groupID)
+	return nil, errorResponse(err), err
+}
+
+// ListApplicationGroupAssignments returns the assignments of groups to the app
+func (a *ApplicationResource) ListApplicationGroupAssignments(ctx context.Context, appID string, qp *query.Params) ([]*okta.ApplicationGroupAssignment, *okta.Response, error) {
+	a.Client.mu.RLock()
+	defer a.Client.mu.RUnlock()
+
+	if _, err := a.getApplication(appID); err != nil {
+		return nil, errorResponse(err), err
+	}
+	page, after := paginate(a.AppGroups[appID], func(assignment *okta.ApplicationGroupAssignment) string { return assignment.Id }, qp)
+	return page, a.Client.listResponse(ctx, fmt.Sprintf("/api/v1/apps/%s/groups", appID), qp, after), nil
+}
+
+// DeleteApplicationGroupAssignment removes the assignment of the group with the groupID from the app,
+// which also unassigns the group's members that aren't assigned to the app another way
+func (a *ApplicationResource) DeleteApplicationGroupAssignment(ctx context.Context, appID string, groupID string) (*okta.Response, error) {
+	a.Client.mu.Lock()
+	defer a.Client.mu.Unlock()
+
+	if _, err := a.getApplication(appID); err != nil {
+		return errorResponse(err), err
+	}
+	if !a.removeGroupAssignment(appID, groupID) {
+		err := newNotFoundError("ApplicationGroupAssignment", groupID)
+		return errorResponse(err), err
+	}
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+func (a *ApplicationResource) removeGroupAssignment(appID string, groupID string) bool {
+	for idx, assignment := range a.AppGroups[appID] {
+		if assignment.Id == groupID {
+			a.AppGroups[appID] = append(a.AppGroups[appID][:idx], a.AppGroups[appID][idx+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func userLogin(user *okta.User) string {
+	login, _ := (*user.Profile)["login"].(string)
+	return login
+}
+
+// removeUserFromAllApplications removes the direct app assignments of a deleted user
+func (a *ApplicationResource) removeUserFromAllApplications(userID string) {
+	for appID, appUsers := range a.AppUsers {
+		for idx, appUser := range appUsers {
+			if appUser.Id == userID {
+				a.AppUsers[appID] = append(appUsers[:idx], appUsers[idx+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// removeGroupFromAllApplications removes the app assignments of a deleted group
+func (a *ApplicationResource) removeGroupFromAllApplications(groupID string) {
+	for appID := range a.AppGroups {
+		a.removeGroupAssignment(appID, groupID)
+	}
+}
@@ -0,0 +1,265 @@
+package mockokta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+func appID(app okta.App) string {
+	fields, _ := fieldsOf(app)
+	return *fields.id
+}
+
+func appStatus(app okta.App) string {
+	fields, _ := fieldsOf(app)
+	return *fields.status
+}
+
+func TestApplicationResource_CreateApplication(t *testing.T) {
+	t.Run("should create apps of each type", func(t *testing.T) {
+		client := NewClient()
+
+		bookmark, _, err1 := client.CreateApplication(context.TODO(), NewBookmarkApplication("Wiki", "https://wiki.example.com"), nil)
+		saml, _, err2 := client.CreateApplication(context.TODO(), NewSamlApplication("My SAML App"), nil)
+		oidc, _, err3 := client.CreateApplication(context.TODO(), NewOpenIDConnectApplication("My OIDC App"), nil)
+
+		for _, err := range []error{err1, err2, err3} {
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+		}
+		if _, ok := bookmark.(*okta.BookmarkApplication); !ok || bookmark.(*okta.BookmarkApplication).Name != "bookmark" {
+			t.Errorf("got %v want a bookmark app named bookmark", bookmark)
+		}
+		if got := saml.(*okta.SamlApplication).Name; got != "mockokta_my_saml_app_1" {
+			t.Errorf("got name %v want mockokta_my_saml_app_1", got)
+		}
+		if got := oidc.(*okta.OpenIdConnectApplication).Name; got != "oidc_client" {
+			t.Errorf("got name %v want oidc_client", got)
+		}
+		if appStatus(saml) != ApplicationStatusActive {
+			t.Errorf("got status %v want %v", appStatus(saml), ApplicationStatusActive)
+		}
+	})
+
+	t.Run("should create INACTIVE apps when activate is false", func(t *testing.T) {
+		client := NewClient()
+
+		app, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("App"), query.NewQueryParams(query.WithActivate(false)))
+
+		if appStatus(app) != ApplicationStatusInactive {
+			t.Errorf("got status %v want %v", appStatus(app), ApplicationStatusInactive)
+		}
+	})
+
+	tests := []struct {
+		name string
+		app  okta.App
+	}{
+		{"should err without a label", NewSamlApplication("")},
+		{"should err for a bookmark without a url", NewBookmarkApplication("Wiki", "")},
+		{"should err for an unsupported sign on mode", &okta.Application{Label: "App", SignOnMode: "WS_FEDERATION"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient()
+
+			_, resp, err := client.CreateApplication(context.TODO(), tt.app, nil)
+
+			if err == nil || resp.StatusCode != 400 {
+				t.Errorf("got %v want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestApplicationResource_Lifecycle(t *testing.T) {
+	t.Run("should deactivate, activate and delete apps", func(t *testing.T) {
+		client := NewClient()
+		app, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("App"), nil)
+
+		client.DeactivateApplication(context.TODO(), appID(app))
+		got, _, _ := client.GetApplication(context.TODO(), appID(app), nil, nil)
+		if appStatus(got) != ApplicationStatusInactive {
+			t.Errorf("got status %v want %v", appStatus(got), ApplicationStatusInactive)
+		}
+		client.ActivateApplication(context.TODO(), appID(app))
+		if appStatus(got) != ApplicationStatusActive {
+			t.Errorf("got status %v want %v", appStatus(got), ApplicationStatusActive)
+		}
+		client.DeactivateApplication(context.TODO(), appID(app))
+
+		if _, err := client.DeleteApplication(context.TODO(), appID(app)); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if _, _, err := client.GetApplication(context.TODO(), appID(app), nil, nil); err == nil {
+			t.Errorf("expected deleted app to not be found")
+		}
+	})
+
+	t.Run("should err deleting an ACTIVE app", func(t *testing.T) {
+		client := NewClient()
+		app, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("App"), nil)
+
+		resp, err := client.DeleteApplication(context.TODO(), appID(app))
+
+		if err == nil || resp.StatusCode != 403 {
+			t.Errorf("got %v want a forbidden error", err)
+		}
+	})
+
+	t.Run("should not find apps of unsupported types", func(t *testing.T) {
+		client := NewClient()
+		client.Application.Applications = append(client.Application.Applications, &okta.AutoLoginApplication{Id: "0oaautologin", Label: "Legacy"})
+
+		_, resp, err := client.GetApplication(context.TODO(), "0oaautologin", nil, nil)
+		_, createResp, createErr := client.CreateApplication(context.TODO(), NewBookmarkApplication("Wiki", "https://wiki.example.com"), nil)
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+		if createErr != nil || createResp.StatusCode != 200 {
+			t.Errorf("unexpected error %v", createErr)
+		}
+	})
+
+	t.Run("should copy the app into the app instance", func(t *testing.T) {
+		client := NewClient()
+		app, _, _ := client.CreateApplication(context.TODO(), NewBookmarkApplication("Wiki", "https://wiki.example.com"), nil)
+
+		got, _, _ := client.GetApplication(context.TODO(), appID(app), okta.NewBookmarkApplication(), nil)
+
+		if bookmark, ok := got.(*okta.BookmarkApplication); !ok || bookmark == app || bookmark.Settings.App.Url != "https://wiki.example.com" {
+			t.Errorf("got %v want a copy of %v", got, app)
+		}
+	})
+}
+
+func TestApplicationResource_ListApplications(t *testing.T) {
+	client := NewClient()
+	wiki, _, _ := client.CreateApplication(context.TODO(), NewBookmarkApplication("Wiki", "https://wiki.example.com"), nil)
+	saml, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("Payroll"), query.NewQueryParams(query.WithActivate(false)))
+	// apps of unsupported types seeded directly are left out
+	client.Application.Applications = append(client.Application.Applications, &okta.AutoLoginApplication{Id: "0oaautologin", Name: "payroll_legacy", Label: "Payroll Legacy"})
+
+	tests := []struct {
+		name string
+		qp   *query.Params
+		want []okta.App
+	}{
+		{"should list all apps", nil, []okta.App{wiki, saml}},
+		{"should match the start of the label", query.NewQueryParams(query.WithQ("pay")), []okta.App{saml}},
+		{"should filter by status", query.NewQueryParams(query.WithFilter(`status eq "ACTIVE"`)), []okta.App{wiki}},
+		{"should paginate", query.NewQueryParams(query.WithLimit(1), query.WithAfter(appID(wiki))), []okta.App{saml}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := client.ListApplications(context.TODO(), tt.qp)
+
+			if err != nil || len(got) != len(tt.want) {
+				t.Fatalf("got %v (%v) want %v", got, err, tt.want)
+			}
+			for idx := range got {
+				if got[idx] != tt.want[idx] {
+					t.Errorf("got %v want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplicationResource_Assignments(t *testing.T) {
+	setup := func(t *testing.T) (*MockClient, okta.App, *okta.Group, *okta.User, *okta.User) {
+		client := NewClient()
+		app, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("App"), nil)
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		user1, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser1@test.com"), nil)
+		user2, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser2@test.com"), nil)
+		return client, app, group, user1, user2
+	}
+
+	t.Run("should compute effective assignments from users and groups", func(t *testing.T) {
+		client, app, group, user1, user2 := setup(t)
+		client.AddUserToGroup(context.TODO(), group.Id, user1.Id)
+		client.AddUserToGroup(context.TODO(), group.Id, user2.Id)
+
+		client.AssignUserToApplication(context.TODO(), appID(app), okta.AppUser{Id: user1.Id})
+		client.CreateApplicationGroupAssignment(context.TODO(), appID(app), group.Id, okta.ApplicationGroupAssignment{})
+		got, _, _ := client.ListApplicationUsers(context.TODO(), appID(app), nil)
+
+		if len(got) != 2 || got[0].Id != user1.Id || got[0].Scope != AppUserScopeUser || got[1].Id != user2.Id || got[1].Scope != AppUserScopeGroup {
+			t.Errorf("got %v want %v directly and %v through the group", got, user1.Id, user2.Id)
+		}
+		if got[1].Credentials.UserName != "TestUser2@test.com" {
+			t.Errorf("got user name %v want TestUser2@test.com", got[1].Credentials.UserName)
+		}
+	})
+
+	t.Run("should keep group assignments after removing the direct one", func(t *testing.T) {
+		client, app, group, user1, _ := setup(t)
+		client.AddUserToGroup(context.TODO(), group.Id, user1.Id)
+		client.AssignUserToApplication(context.TODO(), appID(app), okta.AppUser{Id: user1.Id})
+		client.CreateApplicationGroupAssignment(context.TODO(), appID(app), group.Id, okta.ApplicationGroupAssignment{})
+
+		client.DeleteApplicationUser(context.TODO(), appID(app), user1.Id, nil)
+		got, _, err := client.GetApplicationUser(context.TODO(), appID(app), user1.Id, nil)
+
+		if err != nil || got.Scope != AppUserScopeGroup {
+			t.Errorf("got %v (%v) want a GROUP assignment", got, err)
+		}
+	})
+
+	t.Run("should unassign members when the group assignment is removed", func(t *testing.T) {
+		client, app, group, user1, _ := setup(t)
+		client.AddUserToGroup(context.TODO(), group.Id, user1.Id)
+		client.CreateApplicationGroupAssignment(context.TODO(), appID(app), group.Id, okta.ApplicationGroupAssignment{})
+
+		client.DeleteApplicationGroupAssignment(context.TODO(), appID(app), group.Id)
+		got, _, _ := client.ListApplicationUsers(context.TODO(), appID(app), nil)
+		assignments, _, _ := client.ListApplicationGroupAssignments(context.TODO(), appID(app), nil)
+
+		if len(got) != 0 || len(assignments) != 0 {
+			t.Errorf("got users %v and groups %v want none", got, assignments)
+		}
+	})
+
+	t.Run("should give group assignments increasing priorities", func(t *testing.T) {
+		client, app, group, _, _ := setup(t)
+		other, _, _ := client.CreateGroup(context.TODO(), *NewGroup("OtherGroup"))
+
+		client.CreateApplicationGroupAssignment(context.TODO(), appID(app), group.Id, okta.ApplicationGroupAssignment{})
+		got, _, _ := client.CreateApplicationGroupAssignment(context.TODO(), appID(app), other.Id, okta.ApplicationGroupAssignment{})
+
+		if got.Priority == nil || *got.Priority != 1 {
+			t.Errorf("got priority %v want 1", got.Priority)
+		}
+	})
+
+	t.Run("should remove assignments of deleted users and groups", func(t *testing.T) {
+		client, app, group, user1, _ := setup(t)
+		client.AssignUserToApplication(context.TODO(), appID(app), okta.AppUser{Id: user1.Id})
+		client.CreateApplicationGroupAssignment(context.TODO(), appID(app), group.Id, okta.ApplicationGroupAssignment{})
+
+		client.DeactivateOrDeleteUser(context.TODO(), user1.Id, nil)
+		client.DeactivateOrDeleteUser(context.TODO(), user1.Id, nil)
+		client.DeleteGroup(context.TODO(), group.Id)
+		users, _, _ := client.ListApplicationUsers(context.TODO(), appID(app), nil)
+		groups, _, _ := client.ListApplicationGroupAssignments(context.TODO(), appID(app), nil)
+
+		if len(users) != 0 || len(groups) != 0 {
+			t.Errorf("got users %v and groups %v want none", users, groups)
+		}
+	})
+
+	t.Run("should err assigning unknown users and groups", func(t *testing.T) {
+		client, app, _, _, _ := setup(t)
+
+		_, _, userErr := client.AssignUserToApplication(context.TODO(), appID(app), okta.AppUser{Id: "00u_missing"})
+		_, _, groupErr := client.CreateApplicationGroupAssignment(context.TODO(), appID(app), "00g_missing", okta.ApplicationGroupAssignment{})
+
+		if userErr == nil || groupErr == nil {
+			t.Errorf("got %v and %v want not found errors", userErr, groupErr)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package mockokta
+
+import (
+	"strings"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// permissionManageRoles is the permission to assign and unassign admin roles and their targets, which
+// only super admins have. It isn't an Okta permission, so custom roles can't grant it
+const permissionManageRoles = "okta.roles.manage"
+
+// standardRolePermissions are the permissions each standard admin role grants over the users and
+// groups it can manage, see https://help.okta.com/en-us/Content/Topics/Security/administrators-admin-comparison.htm
+var standardRolePermissions = map[string][]string{
+	"SUPER_ADMIN":            {"okta.users.manage", "okta.groups.manage", permissionManageRoles},
+	"ORG_ADMIN":              {"okta.users.manage", "okta.groups.manage"},
+	"USER_ADMIN":             {"okta.users.manage", "okta.groups.members.manage"},
+	"GROUP_ADMIN":            {"okta.users.manage", "okta.groups.members.manage"},
+	"GROUP_MEMBERSHIP_ADMIN": {"okta.groups.members.manage"},
+	"HELP_DESK_ADMIN": {
+		"okta.users.credentials.resetPassword", "okta.users.credentials.expirePassword",
+		"okta.users.credentials.resetFactors", "okta.users.lifecycle.unlock",
+	},
+}
+
+// WithPrincipal creates the client acting as the admin user with the ID or login, like a client
+// using an API token that admin created. See SetPrincipal
+func WithPrincipal(userIDOrLogin string) ClientOption {
+	return func(c *MockClient) {
+		c.principal = userIDOrLogin
+	}
+}
+
+// SetPrincipal makes the client act as the admin user with the ID or login. The mutating methods of
+// GroupResource, UserResource and IAMResource then return a 403 E0000006 error unless the admin roles
+// the user has directly or through its groups allow the call, so an org can be seeded before
+// restricting the client. Only super admins can change custom roles, resource sets and their
+// bindings, like they are the only ones who can assign admin roles. An empty userIDOrLogin allows
+// every call again
+func (client *MockClient) SetPrincipal(userIDOrLogin string) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.principal = userIDOrLogin
+}
+
+// Principal returns the ID or login of the admin user the client acts as, or "" if it is unrestricted
+func (client *MockClient) Principal() string {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	return client.principal
+}
+
+// grant is a permission one of the principal's admin roles gives it, over every user and group or
+// only the groups, the members of the groupUsers groups and the users listed
+type grant struct {
+	permission string
+	allUsers   bool
+	allGroups  bool
+	groups     []string
+	groupUsers []string
+	users      []string
+}
+
+// authorize returns an error unless the principal has the permission over any users or groups, which
+// is enough to create new ones
+func (client *MockClient) authorize(permission string) *okta.Error {
+	return client.authorizeFor(permission, func(g grant) bool { return true })
+}
+
+// authorizeGroup returns an error unless the principal has the permission over the group with the groupID
+func (client *MockClient) authorizeGroup(permission string, groupID string) *okta.Error {
+	return client.authorizeFor(permission, func(g grant) bool {
+		return g.allGroups || SliceContainsString(g.groups, groupID)
+	})
+}
+
+// authorizeUser returns an error unless the principal has the permission over the user with the userID
+func (client *MockClient) authorizeUser(permission string, userID string) *okta.Error {
+	return client.authorizeFor(permission, func(g grant) bool {
+		if g.allUsers || SliceContainsString(g.users, userID) {
+			return true
+		}
+		for _, groupID := range g.groupUsers {
+			if SliceContainsString(client.Group.GroupUsers[groupID], userID) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (client *MockClient) authorizeFor(permission string, covers func(grant) bool) *okta.Error {
+	if client.principal == "" {
+		return nil
+	}
+	for _, g := range client.principalGrants() {
+		if grantsPermission(g.permission, permission) && covers(g) {
+			return nil
+		}
+	}
+	return NewError(ErrorCodeForbidden)
+}
+
+// principalGrants returns the permissions of every admin role the principal has, which is none if it
+// isn't an existing user that can sign in
+func (client *MockClient) principalGrants() []grant {
+	user, err := client.User.getUser(client.principal)
+	if err != nil || user.Status == UserStatusSuspended || user.Status == UserStatusDeprovisioned {
+		return nil
+	}
+	grants := make([]grant, 0)
+	for _, role := range client.User.assignedRoles(user.Id) {
+		if assignment, ok := client.IAM.Assignments[role.Id]; ok {
+			grants = append(grants, client.IAM.customRoleGrants(assignment)...)
+			continue
+		}
+		scope := grant{allUsers: true, allGroups: true}
+		if targets := client.roleTargets(role.Id); targets != nil && len(targets.Groups) > 0 {
+			scope = grant{groups: targets.Groups, groupUsers: targets.Groups}
+		}
+		for _, permission := range standardRolePermissions[role.Type] {
+			scope.permission = permission
+			grants = append(grants, scope)
+		}
+	}
+	return grants
+}
+
+// roleTargets returns the targets of the role assigned to a user or group with the roleID
+func (client *MockClient) roleTargets(roleID string) *RoleTargets {
+	if targets, ok := client.User.RoleTargets[roleID]; ok {
+		return targets
+	}
+	return client.Group.RoleTargets[roleID]
+}
+
+// customRoleGrants returns the permissions of the custom role over the resources of the resource set
+func (i *IAMResource) customRoleGrants(assignment CustomRoleAssignment) []grant {
+	role, err := i.getCustomRole(assignment.Role)
+	if err != nil {
+		return nil
+	}
+	scope := grant{}
+	for _, resource := range i.Resources[assignment.ResourceSet] {
+		segments := strings.Split(strings.TrimPrefix(resource.Href, ResourceHref("")), "/")
+		switch {
+		case len(segments) == 1 && segments[0] == "users":
+			scope.allUsers = true
+		case len(segments) == 1 && segments[0] == "groups":
+			scope.allGroups = true
+		case len(segments) == 2 && segments[0] == "users":
+			scope.users = append(scope.users, segments[1])
+		case len(segments) == 2 && segments[0] == "groups":
+			scope.groups = append(scope.groups, segments[1])
+		case len(segments) == 3 && segments[0] == "groups":
+			scope.groupUsers = append(scope.groupUsers, segments[1])
+		}
+	}
+	grants := make([]grant, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		scope.permission = permission
+		grants = append(grants, scope)
+	}
+	return grants
+}
+
+// grantsPermission returns whether the granted permission includes the required one. Like Okta, a
+// manage permission includes every permission under it, so okta.users.manage includes
+// okta.users.lifecycle.delete
+func grantsPermission(granted string, required string) bool {
+	return granted == required || strings.HasSuffix(granted, ".manage") && strings.HasPrefix(required, strings.TrimSuffix(granted, "manage"))
+}
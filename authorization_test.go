@@ -0,0 +1,231 @@
+package mockokta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// newTestAdmin creates a user with the standard admin role, restricted to the target groups if any
+func newTestAdmin(t *testing.T, client *MockClient, login string, roleType string, targetGroupIDs ...string) *okta.User {
+	t.Helper()
+	user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(login), nil)
+	role, _, err := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest(roleType), nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, groupID := range targetGroupIDs {
+		client.AddGroupTargetToRole(context.TODO(), user.Id, role.Id, groupID)
+	}
+	return user
+}
+
+func assertForbidden(t *testing.T, resp *okta.Response, err error) {
+	t.Helper()
+	oktaErr, ok := err.(*okta.Error)
+	if !ok || oktaErr.ErrorCode != ErrorCodeForbidden || resp.StatusCode != 403 {
+		t.Errorf("got %v want a forbidden error", err)
+	}
+}
+
+func TestMockClient_Principal(t *testing.T) {
+	t.Run("should forbid every mutation for unknown principals", func(t *testing.T) {
+		client := NewClient(WithPrincipal("svc@test.com"))
+
+		_, resp, err := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		assertForbidden(t, resp, err)
+	})
+
+	t.Run("should allow reads and restore unrestricted access", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		client.SetPrincipal("svc@test.com")
+
+		_, _, readErr := client.GetGroup(context.TODO(), group.Id)
+		client.SetPrincipal("")
+		_, _, createErr := client.CreateGroup(context.TODO(), *NewGroup("OtherGroup"))
+
+		if readErr != nil || createErr != nil || client.Principal() != "" {
+			t.Errorf("got %v and %v want no errors", readErr, createErr)
+		}
+	})
+
+	t.Run("should only let super admins manage admin roles", func(t *testing.T) {
+		client := NewClient()
+		newTestAdmin(t, client, "org@test.com", "ORG_ADMIN")
+		newTestAdmin(t, client, "super@test.com", "SUPER_ADMIN")
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		client.SetPrincipal("org@test.com")
+		_, _, createErr := client.CreateGroup(context.TODO(), *NewGroup("OrgGroup"))
+		_, resp, roleErr := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+		if createErr != nil {
+			t.Errorf("unexpected error %v", createErr)
+		}
+		assertForbidden(t, resp, roleErr)
+
+		client.SetPrincipal("super@test.com")
+		if _, _, err := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("USER_ADMIN"), nil); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should get roles through the principal's groups", func(t *testing.T) {
+		client := NewClient()
+		admins, _ := newGroupWithRole(t, client, "Admins", "ORG_ADMIN")
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("svc@test.com"), nil)
+		client.AddUserToGroup(context.TODO(), admins.Id, user.Id)
+		client.SetPrincipal(user.Id)
+
+		_, _, err := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should forbid suspended principals", func(t *testing.T) {
+		client := NewClient()
+		admin := newTestAdmin(t, client, "org@test.com", "ORG_ADMIN")
+		setUserStatus(admin, UserStatusSuspended)
+		client.SetPrincipal(admin.Id)
+
+		_, resp, err := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		assertForbidden(t, resp, err)
+	})
+}
+
+func TestMockClient_PrincipalTargets(t *testing.T) {
+	setup := func(t *testing.T, roleType string) (*MockClient, *okta.Group, *okta.Group, *okta.User, *okta.User) {
+		client := NewClient()
+		target, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target"))
+		other, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Other"))
+		member, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("member@test.com"), nil)
+		outsider, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("outsider@test.com"), nil)
+		setUserStatus(member, UserStatusActive)
+		setUserStatus(outsider, UserStatusActive)
+		client.AddUserToGroup(context.TODO(), target.Id, member.Id)
+		newTestAdmin(t, client, "admin@test.com", roleType, target.Id)
+		client.SetPrincipal("admin@test.com")
+		return client, target, other, member, outsider
+	}
+
+	t.Run("should limit group membership admins to their target groups", func(t *testing.T) {
+		client, target, other, _, outsider := setup(t, "GROUP_MEMBERSHIP_ADMIN")
+
+		_, targetErr := client.AddUserToGroup(context.TODO(), target.Id, outsider.Id)
+		resp, otherErr := client.AddUserToGroup(context.TODO(), other.Id, outsider.Id)
+		_, createResp, createErr := client.User.CreateUser(context.TODO(), NewCreateUserRequest("new@test.com"), nil)
+
+		if targetErr != nil {
+			t.Errorf("unexpected error %v", targetErr)
+		}
+		assertForbidden(t, resp, otherErr)
+		assertForbidden(t, createResp, createErr)
+	})
+
+	t.Run("should not widen roles when their last target group is deleted", func(t *testing.T) {
+		client, target, other, _, outsider := setup(t, "GROUP_ADMIN")
+		client.SetPrincipal("")
+		client.DeleteGroup(context.TODO(), target.Id)
+		client.SetPrincipal("admin@test.com")
+
+		resp, err := client.AddUserToGroup(context.TODO(), other.Id, outsider.Id)
+
+		assertForbidden(t, resp, err)
+	})
+
+	t.Run("should limit help desk admins to the members of their target groups", func(t *testing.T) {
+		client, _, _, member, outsider := setup(t, "HELP_DESK_ADMIN")
+
+		_, _, memberErr := client.ResetPassword(context.TODO(), member.Id, nil)
+		_, resp, outsiderErr := client.ResetPassword(context.TODO(), outsider.Id, nil)
+		deactivateResp, deactivateErr := client.DeactivateUser(context.TODO(), member.Id, nil)
+
+		if memberErr != nil {
+			t.Errorf("unexpected error %v", memberErr)
+		}
+		assertForbidden(t, resp, outsiderErr)
+		assertForbidden(t, deactivateResp, deactivateErr)
+	})
+
+	t.Run("should limit user admins to the members of their target groups", func(t *testing.T) {
+		client, _, _, member, outsider := setup(t, "USER_ADMIN")
+		profile := okta.UserProfile{"firstName": "Changed"}
+
+		_, _, memberErr := client.PartialUpdateUser(context.TODO(), member.Id, okta.User{Profile: &profile}, nil)
+		_, resp, outsiderErr := client.PartialUpdateUser(context.TODO(), outsider.Id, okta.User{Profile: &profile}, nil)
+
+		if memberErr != nil {
+			t.Errorf("unexpected error %v", memberErr)
+		}
+		assertForbidden(t, resp, outsiderErr)
+	})
+}
+
+func TestMockClient_PrincipalCustomRoles(t *testing.T) {
+	t.Run("should grant a custom role's permissions over its resource set", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		member, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("member@test.com"), nil)
+		outsider, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("outsider@test.com"), nil)
+		admin, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("admin@test.com"), nil)
+		client.AddUserToGroup(context.TODO(), group.Id, member.Id)
+		client.AssignCustomRoleToUser(context.TODO(), admin.Id, NewCustomRoleAssignRequest(role.Id, resourceSet.Id), nil)
+		client.SetPrincipal(admin.Id)
+
+		_, memberErr := client.DeactivateUser(context.TODO(), member.Id, nil)
+		resp, outsiderErr := client.DeactivateUser(context.TODO(), outsider.Id, nil)
+		groupResp, groupErr := client.AddUserToGroup(context.TODO(), group.Id, outsider.Id)
+
+		if memberErr != nil {
+			t.Errorf("unexpected error %v", memberErr)
+		}
+		assertForbidden(t, resp, outsiderErr)
+		// the resource set has the group's users, not the group itself
+		assertForbidden(t, groupResp, groupErr)
+	})
+
+	t.Run("should only let super admins manage custom roles and resource sets", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		other, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Other"))
+		admin := newTestAdmin(t, client, "admin@test.com", "GROUP_MEMBERSHIP_ADMIN", group.Id)
+		client.SetPrincipal(admin.Id)
+
+		_, bindResp, bindErr := client.CreateResourceSetBinding(context.TODO(), resourceSet.Id, RoleBindingRequest{Role: role.Id, Members: []string{admin.Id}})
+		permissionResp, permissionErr := client.AddCustomRolePermission(context.TODO(), role.Id, "okta.groups.manage")
+		resourcesResp, resourcesErr := client.AddResourceSetResources(context.TODO(), resourceSet.Id, []string{"groups"})
+		deleteResp, deleteErr := client.DeleteGroup(context.TODO(), other.Id)
+
+		assertForbidden(t, bindResp, bindErr)
+		assertForbidden(t, permissionResp, permissionErr)
+		assertForbidden(t, resourcesResp, resourcesErr)
+		assertForbidden(t, deleteResp, deleteErr)
+	})
+}
+
+func TestGrantsPermission(t *testing.T) {
+	tests := []struct {
+		granted  string
+		required string
+		want     bool
+	}{
+		{"okta.users.manage", "okta.users.manage", true},
+		{"okta.users.manage", "okta.users.lifecycle.delete", true},
+		{"okta.users.lifecycle.manage", "okta.users.lifecycle.delete", true},
+		{"okta.users.lifecycle.manage", "okta.users.credentials.resetPassword", false},
+		{"okta.groups.members.manage", "okta.groups.manage", false},
+		{"okta.users.read", "okta.users.read.all", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.granted+" "+tt.required, func(t *testing.T) {
+			if got := grantsPermission(tt.granted, tt.required); got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
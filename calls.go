@@ -0,0 +1,270 @@
+package mockokta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// Call is a call made through one of the methods of a MockClient that wrap its resources
+type Call struct {
+	Method string
+	// Args are the arguments of the call other than its context
+	Args []interface{}
+	// N is the number of calls of the method made through the client so far, including this one
+	N int
+	// Time is when the call was made by the client's clock
+	Time time.Time
+	// Result, Response and Err are what the call returned, or nil while it has not returned yet.
+	// Result is nil for the methods that only return a response and for the calls that failed, and
+	// otherwise a copy, so later calls don't change what was recorded
+	Result   interface{}
+	Response *okta.Response
+	Err      error
+}
+
+// String formats the call like Method(args) for the messages of failed assertions
+func (call Call) String() string {
+	args := make([]string, len(call.Args))
+	for i, arg := range call.Args {
+		args[i] = fmt.Sprintf("%+v", arg)
+	}
+	return fmt.Sprintf("%v(%v)", call.Method, strings.Join(args, ", "))
+}
+
+// CallMatcher returns whether a fault applies to the call. A nil CallMatcher matches every call
+type CallMatcher func(call Call) bool
+
+// TestingT is the part of testing.TB the assertions of a MockClient use
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Calls returns the calls made through the client's methods in the order they were made, including
+// the ones that rate limits or injected faults failed. Calls made on its resources like client.Group
+// directly are not recorded
+func (client *MockClient) Calls() []Call {
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	calls := make([]Call, len(client.calls))
+	for i, call := range client.calls {
+		calls[i] = *call
+	}
+	return calls
+}
+
+// ResetCalls forgets the calls made through the client so far, so Calls, the assertions and OnCall
+// only consider the calls made after it
+func (client *MockClient) ResetCalls() {
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	client.calls = nil
+	client.callCounts = make(map[string]int)
+}
+
+// AssertCalled asserts that the method was called through the client at least once with the args
+// as its first arguments after the context
+func (client *MockClient) AssertCalled(t TestingT, method string, args ...interface{}) bool {
+	t.Helper()
+	if !client.assertMethod(t, method) {
+		return false
+	}
+	if len(client.callsTo(method, args)) == 0 {
+		t.Errorf("mockokta: %v was not called\n%v", Call{Method: method, Args: args}, client.formatCalls())
+		return false
+	}
+	return true
+}
+
+// AssertCalledTimes asserts that the method was called through the client exactly the given number
+// of times with the args as its first arguments after the context
+func (client *MockClient) AssertCalledTimes(t TestingT, method string, times int, args ...interface{}) bool {
+	t.Helper()
+	if !client.assertMethod(t, method) {
+		return false
+	}
+	if n := len(client.callsTo(method, args)); n != times {
+		t.Errorf("mockokta: %v was called %v times want %v\n%v", Call{Method: method, Args: args}, n, times, client.formatCalls())
+		return false
+	}
+	return true
+}
+
+// AssertNotCalled asserts that the method was never called through the client with the args as its
+// first arguments after the context, or never called at all without args
+func (client *MockClient) AssertNotCalled(t TestingT, method string, args ...interface{}) bool {
+	t.Helper()
+	if !client.assertMethod(t, method) {
+		return false
+	}
+	if calls := client.callsTo(method, args); len(calls) > 0 {
+		t.Errorf("mockokta: %v was called %v times want 0\n%v", Call{Method: method, Args: args}, len(calls), client.formatCalls())
+		return false
+	}
+	return true
+}
+
+// AssertCallOrder asserts that the methods were called through the client in the given order.
+// Other calls may come before, between or after them
+func (client *MockClient) AssertCallOrder(t TestingT, methods ...string) bool {
+	t.Helper()
+	for _, method := range methods {
+		if !client.assertMethod(t, method) {
+			return false
+		}
+	}
+	next := 0
+	for _, call := range client.Calls() {
+		if next < len(methods) && call.Method == methods[next] {
+			next++
+		}
+	}
+	if next < len(methods) {
+		t.Errorf("mockokta: got no call of %v after %v\n%v", methods[next], strings.Join(methods[:next], ", "), client.formatCalls())
+		return false
+	}
+	return true
+}
+
+// assertMethod fails the test if the client has no method with the name, which would make any
+// assertion about its calls meaningless
+func (client *MockClient) assertMethod(t TestingT, method string) bool {
+	t.Helper()
+	if !hasMethod(method) {
+		t.Errorf("mockokta: MockClient has no method %v", method)
+		return false
+	}
+	return true
+}
+
+// callsTo returns the calls of the method whose first arguments after the context are the args
+func (client *MockClient) callsTo(method string, args []interface{}) []Call {
+	matches := WithArgs(args...)
+	var calls []Call
+	for _, call := range client.Calls() {
+		if call.Method == method && matches(call) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// formatCalls lists the calls made through the client for the messages of failed assertions
+func (client *MockClient) formatCalls() string {
+	calls := client.Calls()
+	if len(calls) == 0 {
+		return "no calls were made"
+	}
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		lines[i] = "\t" + call.String()
+	}
+	return "calls made:\n" + strings.Join(lines, "\n")
+}
+
+// hasMethod returns whether MockClient has a method with the name
+func hasMethod(method string) bool {
+	_, ok := reflect.TypeOf(&MockClient{}).MethodByName(method)
+	return ok
+}
+
+// startCall counts and records the call to the method with the args
+func (client *MockClient) startCall(method string, args []interface{}) *Call {
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	client.callCounts[method]++
+	call := &Call{Method: method, Args: args, N: client.callCounts[method], Time: client.clock.Now()}
+	client.calls = append(client.calls, call)
+	return call
+}
+
+// endCall records what the call returned
+func (client *MockClient) endCall(call *Call, result interface{}, resp *okta.Response, err error) {
+	if err != nil {
+		result = nil
+	} else {
+		result = client.copyResult(result)
+	}
+
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	call.Result, call.Response, call.Err = result, resp, err
+}
+
+// copyResult returns a copy of what a call returned with the same type, like deepCopy. The results are
+// often the mock's own groups, users and roles, so they are copied under the client's lock
+func (client *MockClient) copyResult(result interface{}) interface{} {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	switch result := result.(type) {
+	case nil:
+		return nil
+	case okta.App:
+		return copyAppOfType(result)
+	case []okta.App:
+		apps := make([]okta.App, len(result))
+		for i, app := range result {
+			apps[i] = copyAppOfType(app)
+		}
+		return apps
+	}
+	resultCopy := reflect.New(reflect.TypeOf(result))
+	data, err := json.Marshal(result)
+	if err == nil {
+		err = json.Unmarshal(data, resultCopy.Interface())
+	}
+	if err != nil {
+		panic(fmt.Sprintf("mockokta: copying %T: %v", result, err))
+	}
+	return resultCopy.Elem().Interface()
+}
+
+// do records the call to the method with the args and makes it unless the rate limits of the client
+// or a fault injected into it fail it
+func (client *MockClient) do(ctx context.Context, method string, args []interface{}, fn func() (interface{}, *okta.Response, error)) (*okta.Response, error) {
+	call := client.startCall(method, args)
+	var result interface{}
+	header, resp, err := client.rateLimit(method)
+	if err == nil {
+		if fault := client.matchFault(*call); fault != nil {
+			resp, err = fault.apply(ctx)
+		}
+	}
+	if err == nil {
+		result, resp, err = fn()
+	}
+	setHeaders(resp, header)
+	client.endCall(call, result, resp, err)
+	return resp, err
+}
+
+// intercept is do for the methods that return a value and a response
+func intercept[T any](ctx context.Context, client *MockClient, method string, args []interface{}, call func() (T, *okta.Response, error)) (T, *okta.Response, error) {
+	var value T
+	resp, err := client.do(ctx, method, args, func() (interface{}, *okta.Response, error) {
+		var resp *okta.Response
+		var err error
+		value, resp, err = call()
+		return value, resp, err
+	})
+	return value, resp, err
+}
+
+// interceptResponse is do for the methods that only return a response
+func interceptResponse(ctx context.Context, client *MockClient, method string, args []interface{}, call func() (*okta.Response, error)) (*okta.Response, error) {
+	return client.do(ctx, method, args, func() (interface{}, *okta.Response, error) {
+		resp, err := call()
+		return nil, resp, err
+	})
+}
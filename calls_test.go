@@ -0,0 +1,155 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// fakeT records the failures of the assertions instead of failing the test
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockClient_Calls(t *testing.T) {
+	t.Run("should record the calls with what they returned", func(t *testing.T) {
+		clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		client := NewClient(WithClock(clock))
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		clock.Advance(time.Second)
+		_, notFoundErr := client.AddUserToGroup(context.TODO(), group.Id, "00umissing")
+
+		calls := client.Calls()
+
+		if len(calls) != 2 {
+			t.Fatalf("got %v calls want 2", len(calls))
+		}
+		create, add := calls[0], calls[1]
+		if create.Method != "CreateGroup" || create.N != 1 || create.Result.(*okta.Group).Id != group.Id || create.Err != nil || create.Response.StatusCode != 200 {
+			t.Errorf("got %+v want the CreateGroup call", create)
+		}
+		if !create.Time.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) || !add.Time.Equal(clock.Now()) {
+			t.Errorf("got times %v and %v", create.Time, add.Time)
+		}
+		if add.Method != "AddUserToGroup" || add.Result != nil || add.Err != notFoundErr || fmt.Sprint(add.Args) != fmt.Sprintf("[%v 00umissing]", group.Id) {
+			t.Errorf("got %+v want the failed AddUserToGroup call", add)
+		}
+	})
+
+	t.Run("should record copies of what the calls returned", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		client.CreateApplication(context.TODO(), NewBookmarkApplication("Wiki", "https://wiki.example.com"), nil)
+		client.ListApplications(context.TODO(), nil)
+
+		client.UpdateGroup(context.TODO(), group.Id, *NewGroup("Renamed"))
+
+		calls := client.Calls()
+		if result := calls[0].Result.(*okta.Group); result == group || result.Profile.Name != "TestGroup" {
+			t.Errorf("got %+v want a copy of the created group", result)
+		}
+		if apps, ok := calls[2].Result.([]okta.App); !ok || len(apps) != 1 || apps[0].(*okta.BookmarkApplication).Label != "Wiki" {
+			t.Errorf("got %+v want the listed apps", calls[2].Result)
+		}
+	})
+
+	t.Run("should record calls failed by faults and rate limits", func(t *testing.T) {
+		client := NewClient(WithRateLimits(RateLimit{Path: "/api/v1/groups", Limit: 1, Window: time.Minute}))
+		client.InjectFault("ListGroups", OnCall(1), Fault{Status: 500}, 1)
+
+		client.ListGroups(context.TODO(), nil)
+		client.ListGroups(context.TODO(), nil)
+
+		calls := client.Calls()
+		if len(calls) != 2 || calls[0].Response.StatusCode != 500 || calls[1].Response.StatusCode != 429 || calls[1].N != 2 {
+			t.Errorf("got %+v want a failed call and a rate limited one", calls)
+		}
+	})
+
+	t.Run("should forget the calls once reset", func(t *testing.T) {
+		client := NewClient()
+		client.ListGroups(context.TODO(), nil)
+
+		client.ResetCalls()
+		client.ListUsers(context.TODO(), nil)
+
+		if calls := client.Calls(); len(calls) != 1 || calls[0].Method != "ListUsers" || calls[0].N != 1 {
+			t.Errorf("got %+v want only the ListUsers call", calls)
+		}
+	})
+
+	t.Run("should not record calls made on the resources", func(t *testing.T) {
+		client := NewClient()
+		client.Group.ListGroups(context.TODO(), nil)
+
+		if calls := client.Calls(); len(calls) != 0 {
+			t.Errorf("got %+v want no calls", calls)
+		}
+	})
+}
+
+func TestMockClient_Assertions(t *testing.T) {
+	client := NewClient()
+	group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+	user, _, _ := client.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+	client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+	client.ListGroupUsers(context.TODO(), group.Id, &query.Params{})
+
+	tests := []struct {
+		name   string
+		assert func(t TestingT) bool
+		want   bool
+	}{
+		{"called", func(t TestingT) bool { return client.AssertCalled(t, "AddUserToGroup", group.Id, user.Id) }, true},
+		{"called with other args", func(t TestingT) bool { return client.AssertCalled(t, "AddUserToGroup", group.Id, "00uother") }, false},
+		{"called once", func(t TestingT) bool { return client.AssertCalledTimes(t, "AddUserToGroup", 1, group.Id) }, true},
+		{"called twice", func(t TestingT) bool { return client.AssertCalledTimes(t, "CreateGroup", 2) }, false},
+		{"not called", func(t TestingT) bool { return client.AssertNotCalled(t, "DeleteGroup") }, true},
+		{"not called with args", func(t TestingT) bool { return client.AssertNotCalled(t, "ListGroupUsers", "00gother") }, true},
+		{"not called but called", func(t TestingT) bool { return client.AssertNotCalled(t, "CreateUser") }, false},
+		{"in order", func(t TestingT) bool {
+			return client.AssertCallOrder(t, "CreateGroup", "AddUserToGroup", "ListGroupUsers")
+		}, true},
+		{"out of order", func(t TestingT) bool { return client.AssertCallOrder(t, "AddUserToGroup", "CreateUser") }, false},
+		{"unknown methods", func(t TestingT) bool { return client.AssertNotCalled(t, "DeleteGorup") }, false},
+	}
+	for _, tt := range tests {
+		t.Run("should assert "+tt.name, func(t *testing.T) {
+			fake := &fakeT{}
+
+			got := tt.assert(fake)
+
+			if got != tt.want || len(fake.errors) > 0 == tt.want {
+				t.Errorf("got %v with errors %v want %v", got, fake.errors, tt.want)
+			}
+		})
+	}
+
+	t.Run("should list the calls made when failing", func(t *testing.T) {
+		fake := &fakeT{}
+
+		client.AssertCalled(fake, "DeleteGroup", group.Id)
+
+		want := fmt.Sprintf("mockokta: DeleteGroup(%v) was not called\ncalls made:\n\tCreateGroup(", group.Id)
+		if len(fake.errors) != 1 || !strings.HasPrefix(fake.errors[0], want) {
+			t.Errorf("got %v want it to start with %v", fake.errors, want)
+		}
+	})
+
+	t.Run("should accept a testing.T", func(t *testing.T) {
+		client.AssertCalled(t, "CreateGroup")
+		client.AssertCalledTimes(t, "AddUserToGroup", 1, group.Id, user.Id)
+		client.AssertNotCalled(t, "DeleteGroup")
+	})
+}
@@ -0,0 +1,130 @@
+package mockokta
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// Okta error codes returned by the mock, see https://developer.okta.com/docs/reference/error-codes/
+const (
+	ErrorCodeValidation           = "E0000001"
+	ErrorCodeMalformedRequest     = "E0000003"
+	ErrorCodeForbidden            = "E0000006"
+	ErrorCodeNotFound             = "E0000007"
+	ErrorCodePathNotFound         = "E0000008"
+	ErrorCodeInternalError        = "E0000009"
+	ErrorCodeAlreadyActive        = "E0000016"
+	ErrorCodeDeleteAppForbidden   = "E0000056"
+	ErrorCodeMethodNotAllowed     = "E0000022"
+	ErrorCodeInvalidSearch        = "E0000031"
+	ErrorCodeUnlockNotAllowed     = "E0000032"
+	ErrorCodeInvalidStatus        = "E0000038"
+	ErrorCodeRateLimited          = "E0000047"
+	ErrorCodeUnsupportedOperation = "E0000060"
+	ErrorCodeDuplicateRole        = "E0000090"
+)
+
+// ErrorDefinition is the HTTP status and default summary Okta uses for an error code
+type ErrorDefinition struct {
+	Status  int
+	Summary string
+}
+
+// ErrorCatalogue maps each Okta error code used by the mock to its HTTP status and summary
+var ErrorCatalogue = map[string]ErrorDefinition{
+	ErrorCodeValidation:           {http.StatusBadRequest, "Api validation failed"},
+	ErrorCodeMalformedRequest:     {http.StatusBadRequest, "The request body was not well-formed."},
+	ErrorCodeForbidden:            {http.StatusForbidden, "You do not have permission to perform the requested action"},
+	ErrorCodeNotFound:             {http.StatusNotFound, "Not found: Resource not found"},
+	ErrorCodePathNotFound:         {http.StatusNotFound, "The requested path was not found"},
+	ErrorCodeInternalError:        {http.StatusInternalServerError, "Internal Server Error"},
+	ErrorCodeAlreadyActive:        {http.StatusForbidden, "Activation failed because the user is already active"},
+	ErrorCodeDeleteAppForbidden:   {http.StatusForbidden, "Delete application forbidden."},
+	ErrorCodeMethodNotAllowed:     {http.StatusMethodNotAllowed, "The endpoint does not support the provided HTTP method"},
+	ErrorCodeInvalidSearch:        {http.StatusBadRequest, "Invalid search criteria."},
+	ErrorCodeUnlockNotAllowed:     {http.StatusForbidden, "Unlock is not allowed for this user."},
+	ErrorCodeInvalidStatus:        {http.StatusForbidden, "This operation is not allowed in the user's current status."},
+	ErrorCodeRateLimited:          {http.StatusTooManyRequests, "API call exceeded rate limit due to too many requests."},
+	ErrorCodeUnsupportedOperation: {http.StatusBadRequest, "Unsupported operation."},
+	ErrorCodeDuplicateRole:        {http.StatusConflict, "Duplicate administrator role"},
+}
+
+// NewError creates an *okta.Error for the error code with the catalogue summary, adding each
+// cause as an errorCauses entry the way the Okta API does
+func NewError(code string, causes ...string) *okta.Error {
+	e := &okta.Error{
+		ErrorCode:    code,
+		ErrorSummary: ErrorCatalogue[code].Summary,
+		ErrorLink:    code,
+		ErrorId:      fmt.Sprintf("oae%s", randomID(19)),
+		ErrorCauses:  make([]map[string]interface{}, 0),
+	}
+	for _, cause := range causes {
+		e.ErrorCauses = append(e.ErrorCauses, map[string]interface{}{"errorSummary": cause})
+	}
+	return e
+}
+
+// newValidationError creates an E0000001 error for a field, e.g. "Api validation failed: name"
+func newValidationError(field string, cause string) *okta.Error {
+	e := NewError(ErrorCodeValidation, fmt.Sprintf("%s: %s", field, cause))
+	e.ErrorSummary = fmt.Sprintf("%s: %s", e.ErrorSummary, field)
+	return e
+}
+
+// newNotFoundError creates an E0000007 error for the resource, e.g. "Not found: Resource not found: 00g1 (UserGroup)"
+func newNotFoundError(resourceType string, id string) *okta.Error {
+	e := NewError(ErrorCodeNotFound)
+	e.ErrorSummary = fmt.Sprintf("%s: %s (%s)", e.ErrorSummary, id, resourceType)
+	return e
+}
+
+// newResponse creates an *okta.Response with the status code and JSON encoded body
+func newResponse(status int, body interface{}) *okta.Response {
+	header := http.Header{}
+	var b []byte
+	if body != nil {
+		header.Set("Content-Type", "application/json")
+		b, _ = json.Marshal(body)
+	}
+	return &okta.Response{
+		Response: &http.Response{
+			Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode: status,
+			Header:     header,
+			Body:       io.NopCloser(bytes.NewReader(b)),
+		},
+	}
+}
+
+// errorResponse creates the *okta.Response Okta would send along with err, using the status
+// from the error catalogue, or a 500 if err is not an *okta.Error
+func errorResponse(err error) *okta.Response {
+	var oktaErr *okta.Error
+	if !errors.As(err, &oktaErr) {
+		return newResponse(http.StatusInternalServerError, NewError(ErrorCodeInternalError))
+	}
+	status, ok := ErrorCatalogue[oktaErr.ErrorCode]
+	if !ok {
+		return newResponse(http.StatusInternalServerError, oktaErr)
+	}
+	return newResponse(status.Status, oktaErr)
+}
+
+var idRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+
+// randomID generates a random alphanumeric string of length n
+func randomID(n int) string {
+	b := make([]rune, n)
+	for i := range b {
+		b[i] = idRunes[rand.Intn(len(idRunes))]
+	}
+	return string(b)
+}
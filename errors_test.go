@@ -0,0 +1,123 @@
+package mockokta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// assertOktaError checks err is an *okta.Error with the code, and that resp carries the matching status
+func assertOktaError(t *testing.T, resp *okta.Response, err error, code string) {
+	t.Helper()
+	var oktaErr *okta.Error
+	if !errors.As(err, &oktaErr) {
+		t.Fatalf("expected *okta.Error but got %#v", err)
+	}
+	if oktaErr.ErrorCode != code {
+		t.Errorf("got error code %v want %v", oktaErr.ErrorCode, code)
+	}
+	if resp == nil || resp.Response == nil {
+		t.Fatalf("expected a response for error %v", code)
+	}
+	if want := ErrorCatalogue[code].Status; resp.StatusCode != want {
+		t.Errorf("got status %d want %d", resp.StatusCode, want)
+	}
+}
+
+func TestOktaErrors(t *testing.T) {
+	t.Run("should return E0000007 for missing group", func(t *testing.T) {
+		client := NewClient()
+
+		resp, err := client.DeleteGroup(context.TODO(), "NonExistentId")
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+		if err.Error() != "the API returned an error: Not found: Resource not found: NonExistentId (UserGroup)" {
+			t.Errorf("unexpected error message %q", err.Error())
+		}
+	})
+
+	t.Run("should return E0000007 listing users of missing group", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.ListGroupUsers(context.TODO(), "NonExistentId", nil)
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
+
+	t.Run("should return E0000007 adding missing user to group", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		resp, err := client.AddUserToGroup(context.TODO(), group.Id, "NonExistentId")
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
+
+	t.Run("should return E0000001 creating duplicate group", func(t *testing.T) {
+		client := NewClient()
+		client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		_, resp, err := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+	})
+
+	t.Run("should return E0000001 creating user with existing login", func(t *testing.T) {
+		client := NewClient()
+		client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		_, resp, err := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+	})
+
+	t.Run("should return E0000001 assigning invalid role", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		_, resp, err := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("Invalid_Role"), nil)
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+	})
+
+	t.Run("should return E0000090 with a 409 assigning duplicate role", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("SUPER_ADMIN"), nil)
+
+		_, resp, err := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("SUPER_ADMIN"), nil)
+
+		assertOktaError(t, resp, err, ErrorCodeDuplicateRole)
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("got status %d want %d", resp.StatusCode, http.StatusConflict)
+		}
+	})
+
+	t.Run("should return E0000031 for invalid search", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.ListGroups(context.TODO(), query.NewQueryParams(query.WithFilter(`type is "OKTA_GROUP"`)))
+
+		assertOktaError(t, resp, err, ErrorCodeInvalidSearch)
+	})
+
+	t.Run("should return error through the next page", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		for _, email := range []string{"TestUser1@test.com", "TestUser2@test.com"} {
+			user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(email), nil)
+			client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		}
+		_, resp, _ := client.ListGroupUsers(context.TODO(), group.Id, query.NewQueryParams(query.WithLimit(1)))
+		client.DeleteGroup(context.TODO(), group.Id)
+
+		var next []*okta.User
+		resp, err := resp.Next(context.TODO(), &next)
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
+}
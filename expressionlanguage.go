@@ -0,0 +1,422 @@
+package mockokta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// elContext is what an Okta Expression Language expression is evaluated against: a user and the
+// groups it is currently a member of
+type elContext struct {
+	user   *okta.User
+	groups []*okta.Group
+}
+
+// elNode is a parsed Okta Expression Language expression that evaluates to a value for a user
+type elNode interface {
+	value(ctx elContext) interface{}
+}
+
+type elLiteral struct {
+	v interface{}
+}
+
+func (n elLiteral) value(ctx elContext) interface{} {
+	return n.v
+}
+
+// elAttribute is a user.<attribute> reference to an attribute of the user's profile
+type elAttribute struct {
+	name string
+}
+
+func (n elAttribute) value(ctx elContext) interface{} {
+	if ctx.user == nil || ctx.user.Profile == nil {
+		return nil
+	}
+	return (*ctx.user.Profile)[n.name]
+}
+
+type elLogical struct {
+	operator string
+	left     elNode
+	right    elNode
+}
+
+func (n elLogical) value(ctx elContext) interface{} {
+	if n.operator == "&&" {
+		return isTrue(n.left.value(ctx)) && isTrue(n.right.value(ctx))
+	}
+	return isTrue(n.left.value(ctx)) || isTrue(n.right.value(ctx))
+}
+
+type elNot struct {
+	inner elNode
+}
+
+func (n elNot) value(ctx elContext) interface{} {
+	return !isTrue(n.inner.value(ctx))
+}
+
+type elComparison struct {
+	operator string
+	left     elNode
+	right    elNode
+}
+
+// elOperators maps Expression Language comparison operators to the operators used by compareValues
+var elOperators = map[string]string{"==": "eq", "!=": "ne", ">": "gt", ">=": "ge", "<": "lt", "<=": "le"}
+
+func (n elComparison) value(ctx elContext) interface{} {
+	left, right := n.left.value(ctx), n.right.value(ctx)
+	if l, ok := left.(string); ok {
+		// unlike SCIM filters, Expression Language string comparisons are case sensitive
+		r, ok := right.(string)
+		if !ok {
+			return n.operator == "!="
+		}
+		return compareOrdered(strings.Compare(l, r), elOperators[n.operator])
+	}
+	if l, ok := toFloat(left); ok {
+		left = l
+	}
+	if r, ok := toFloat(right); ok {
+		right = r
+	}
+	return compareValues(left, elOperators[n.operator], right)
+}
+
+type elCall struct {
+	function string
+	args     []elNode
+}
+
+// elFunction implements an Expression Language function taking a fixed number of arguments
+type elFunction struct {
+	arity    int // -1 for one or more arguments
+	evaluate func(ctx elContext, args []interface{}) interface{}
+}
+
+// elFunctions is the subset of Okta Expression Language functions that can be used in group rules, see
+// https://developer.okta.com/docs/reference/okta-expression-language/
+var elFunctions = map[string]elFunction{
+	"String.stringContains": {2, func(ctx elContext, args []interface{}) interface{} {
+		return strings.Contains(toString(args[0]), toString(args[1]))
+	}},
+	"String.startsWith": {2, func(ctx elContext, args []interface{}) interface{} {
+		return strings.HasPrefix(toString(args[0]), toString(args[1]))
+	}},
+	"String.endsWith": {2, func(ctx elContext, args []interface{}) interface{} {
+		return strings.HasSuffix(toString(args[0]), toString(args[1]))
+	}},
+	"String.toLowerCase": {1, func(ctx elContext, args []interface{}) interface{} {
+		return strings.ToLower(toString(args[0]))
+	}},
+	"String.toUpperCase": {1, func(ctx elContext, args []interface{}) interface{} {
+		return strings.ToUpper(toString(args[0]))
+	}},
+	"String.len": {1, func(ctx elContext, args []interface{}) interface{} {
+		return float64(len([]rune(toString(args[0]))))
+	}},
+	"Arrays.contains": {2, func(ctx elContext, args []interface{}) interface{} {
+		for _, v := range toSlice(args[0]) {
+			if compareValues(v, "eq", args[1]) {
+				return true
+			}
+		}
+		return false
+	}},
+	"Arrays.isEmpty": {1, func(ctx elContext, args []interface{}) interface{} {
+		return len(toSlice(args[0])) == 0
+	}},
+	"Arrays.size": {1, func(ctx elContext, args []interface{}) interface{} {
+		return float64(len(toSlice(args[0])))
+	}},
+	"isMemberOfGroup": {1, func(ctx elContext, args []interface{}) interface{} {
+		return memberOfGroupMatching(ctx, func(g *okta.Group) bool { return g.Id == toString(args[0]) })
+	}},
+	"isMemberOfAnyGroup": {-1, func(ctx elContext, args []interface{}) interface{} {
+		return memberOfGroupMatching(ctx, func(g *okta.Group) bool {
+			for _, id := range args {
+				if g.Id == toString(id) {
+					return true
+				}
+			}
+			return false
+		})
+	}},
+	"isMemberOfGroupName": {1, func(ctx elContext, args []interface{}) interface{} {
+		return memberOfGroupMatching(ctx, func(g *okta.Group) bool { return g.Profile.Name == toString(args[0]) })
+	}},
+	"isMemberOfGroupNameStartsWith": {1, func(ctx elContext, args []interface{}) interface{} {
+		return memberOfGroupMatching(ctx, func(g *okta.Group) bool { return strings.HasPrefix(g.Profile.Name, toString(args[0])) })
+	}},
+	"isMemberOfGroupNameContains": {1, func(ctx elContext, args []interface{}) interface{} {
+		return memberOfGroupMatching(ctx, func(g *okta.Group) bool { return strings.Contains(g.Profile.Name, toString(args[0])) })
+	}},
+}
+
+func (n elCall) value(ctx elContext) interface{} {
+	args := make([]interface{}, len(n.args))
+	for idx, arg := range n.args {
+		args[idx] = arg.value(ctx)
+	}
+	return elFunctions[n.function].evaluate(ctx, args)
+}
+
+func memberOfGroupMatching(ctx elContext, match func(*okta.Group) bool) bool {
+	for _, group := range ctx.groups {
+		if match(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTrue reports whether an expression value is the boolean true
+func isTrue(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+func toString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func toSlice(v interface{}) []interface{} {
+	switch s := v.(type) {
+	case []interface{}:
+		return s
+	case []string:
+		values := make([]interface{}, len(s))
+		for idx, x := range s {
+			values[idx] = x
+		}
+		return values
+	}
+	return nil
+}
+
+// tokenizeEL splits an Expression Language expression into words, quoted strings, numbers,
+// operators, commas and parentheses
+func tokenizeEL(expr string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpenParen, value: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenCloseParen, value: ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma, value: ","})
+			i++
+		case r == '"' || r == '\'':
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == r {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string in expression %q", expr)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String()})
+		case strings.ContainsRune("=!<>&|", r):
+			op := string(r)
+			if i+1 < len(runes) && SliceContainsString([]string{"==", "!=", "<=", ">=", "&&", "||"}, string(runes[i:i+2])) {
+				op = string(runes[i : i+2])
+			}
+			if op == "=" || op == "&" || op == "|" {
+				return nil, fmt.Errorf("unexpected character %q in expression %q", r, expr)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: op})
+			i += len(op)
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("unexpected character %q in expression %q", r, expr)
+		}
+	}
+	return append(tokens, token{kind: tokenEnd}), nil
+}
+
+// parseEL parses an Okta Expression Language expression such as
+// `user.department == "Engineering" && !isMemberOfGroupName("Contractors")`
+func parseEL(expr string) (elNode, error) {
+	tokens, err := tokenizeEL(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &expressionParser{tokens: tokens}
+	n, err := p.parseELOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %q in expression", p.peek().value)
+	}
+	return n, nil
+}
+
+func (p *expressionParser) peekOperator(operators ...string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && SliceContainsString(operators, t.value)
+}
+
+func (p *expressionParser) parseELOr() (elNode, error) {
+	left, err := p.parseELAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("||") || p.peekKeyword("or") {
+		p.next()
+		right, err := p.parseELAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = elLogical{operator: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseELAnd() (elNode, error) {
+	left, err := p.parseELUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("&&") || p.peekKeyword("and") {
+		p.next()
+		right, err := p.parseELUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = elLogical{operator: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseELUnary() (elNode, error) {
+	if p.peekOperator("!") {
+		p.next()
+		inner, err := p.parseELUnary()
+		if err != nil {
+			return nil, err
+		}
+		return elNot{inner: inner}, nil
+	}
+	left, err := p.parseELPrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind == tokenOperator && elOperators[p.peek().value] != "" {
+		operator := p.next().value
+		right, err := p.parseELPrimary()
+		if err != nil {
+			return nil, err
+		}
+		return elComparison{operator: operator, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseELPrimary() (elNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenOpenParen:
+		inner, err := p.parseELOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenCloseParen {
+			return nil, fmt.Errorf("missing closing parenthesis in expression")
+		}
+		return inner, nil
+	case tokenString:
+		return elLiteral{v: t.value}, nil
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.value)
+		}
+		return elLiteral{v: f}, nil
+	case tokenWord:
+		if p.peek().kind == tokenOpenParen {
+			return p.parseELCall(t.value)
+		}
+		switch t.value {
+		case "true":
+			return elLiteral{v: true}, nil
+		case "false":
+			return elLiteral{v: false}, nil
+		case "null":
+			return elLiteral{v: nil}, nil
+		}
+		if name := strings.TrimPrefix(t.value, "user."); name != t.value && name != "" && !strings.Contains(name, ".") {
+			return elAttribute{name: name}, nil
+		}
+		return nil, fmt.Errorf("unknown attribute %q", t.value)
+	}
+	return nil, fmt.Errorf("expected a value but found %q", t.value)
+}
+
+func (p *expressionParser) parseELCall(name string) (elNode, error) {
+	function, ok := elFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+	p.next()
+	args := make([]elNode, 0)
+	for p.peek().kind != tokenCloseParen {
+		if len(args) > 0 && p.next().kind != tokenComma {
+			return nil, fmt.Errorf("expected ',' between arguments to %v", name)
+		}
+		arg, err := p.parseELOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+	if (function.arity >= 0 && len(args) != function.arity) || len(args) == 0 {
+		return nil, fmt.Errorf("wrong number of arguments to %v", name)
+	}
+	return elCall{function: name, args: args}, nil
+}
@@ -0,0 +1,93 @@
+package mockokta
+
+import (
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func TestParseEL(t *testing.T) {
+	user := &okta.User{
+		Id: "00u1",
+		Profile: &okta.UserProfile{
+			"login":      "jane@test.com",
+			"department": "Engineering",
+			"title":      "Staff Engineer",
+			"level":      float64(5),
+			"manager":    true,
+			"skills":     []interface{}{"go", "okta"},
+		},
+	}
+	groups := []*okta.Group{{Id: "00g1", Profile: &okta.GroupProfile{Name: "eng-backend"}}}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`user.department == "Engineering"`, true},
+		{`user.department == 'Engineering'`, true},
+		{`user.department == "engineering"`, false},
+		{`user.department != "Sales"`, true},
+		{`user.level >= 5 && user.level < 6`, true},
+		{`user.level > 5`, false},
+		{`user.manager == true`, true},
+		{`user.missing == null`, true},
+		{`user.missing == "x"`, false},
+		{`user.department == "Sales" || user.manager`, true},
+		{`user.department == "Sales" OR user.department == "Engineering"`, true},
+		{`user.department == "Engineering" AND !user.manager`, false},
+		{`!(user.department == "Sales")`, true},
+		{`String.startsWith(user.title, "Staff")`, true},
+		{`String.stringContains(user.title, "Eng")`, true},
+		{`String.endsWith(user.login, "@test.com")`, true},
+		{`String.toLowerCase(user.department) == "engineering"`, true},
+		{`String.len(user.department) == 11`, true},
+		{`Arrays.contains(user.skills, "okta")`, true},
+		{`Arrays.contains(user.skills, "java")`, false},
+		{`Arrays.size(user.skills) == 2 && !Arrays.isEmpty(user.skills)`, true},
+		{`isMemberOfGroup("00g1")`, true},
+		{`isMemberOfAnyGroup("00g2", "00g1")`, true},
+		{`isMemberOfGroupName("eng-backend")`, true},
+		{`isMemberOfGroupNameStartsWith("eng-")`, true},
+		{`isMemberOfGroupNameContains("sales")`, false},
+		{`user.department`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			n, err := parseEL(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			got := isTrue(n.value(elContext{user: user, groups: groups}))
+
+			if got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEL_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		`user.department = "Engineering"`,
+		`user.department == "Engineering`,
+		`department == "Engineering"`,
+		`user.department ==`,
+		`(user.manager`,
+		`user.manager && `,
+		`String.unknown(user.title)`,
+		`String.startsWith(user.title)`,
+		`String.startsWith(user.title "x")`,
+		`isMemberOfAnyGroup()`,
+		`user.manager user.manager`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := parseEL(expr)
+
+			if err == nil {
+				t.Errorf("expected error but didn't get one")
+			}
+		})
+	}
+}
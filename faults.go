@@ -0,0 +1,138 @@
+package mockokta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// OnCall matches the nth call of the method, counting from 1
+func OnCall(n int) CallMatcher {
+	return func(call Call) bool {
+		return call.N == n
+	}
+}
+
+// WithArgs matches the calls whose first arguments after the context are equal to the args
+func WithArgs(args ...interface{}) CallMatcher {
+	return func(call Call) bool {
+		if len(call.Args) < len(args) {
+			return false
+		}
+		for i, arg := range args {
+			if !reflect.DeepEqual(call.Args[i], arg) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Fault is what an injected fault does to the calls it matches: it waits for the Latency, then
+// fails the call instead of making it if Err, Status or Cancel are set
+type Fault struct {
+	// Latency delays the call, which fails with the context's error if the context ends first
+	Latency time.Duration
+	// Err is the error the call returns. An *okta.Error comes with a response with the status of its
+	// code in ErrorCatalogue, and other errors come without a response like the SDK's transport errors
+	Err error
+	// Status overrides the status of the response. Without Err the call returns an E0000009 error
+	// with the status's text as its summary
+	Status int
+	// Cancel makes the call return context.Canceled, as if its context was canceled during the request
+	Cancel bool
+}
+
+// injectedFault is a fault with the method and matcher of the calls it applies to, and the number of
+// times it still applies or -1 if it always does
+type injectedFault struct {
+	method  string
+	matcher CallMatcher
+	fault   Fault
+	times   int
+}
+
+// InjectFault makes the next calls of the method through the client that the matcher matches fail
+// or slow down with the fault, the given number of times or on every matching call if times is 0.
+// Faults apply to the MockClient's methods and so to the Server, but not to calls made on its
+// resources like client.Group directly. Panics if the client has no method with the name
+func (client *MockClient) InjectFault(method string, matcher CallMatcher, fault Fault, times int) {
+	if !hasMethod(method) {
+		panic(fmt.Sprintf("mockokta: InjectFault: MockClient has no method %v", method))
+	}
+	if times <= 0 {
+		times = -1
+	}
+
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	client.faults = append(client.faults, &injectedFault{method: method, matcher: matcher, fault: fault, times: times})
+}
+
+// ClearFaults removes every fault injected into the client
+func (client *MockClient) ClearFaults() {
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	client.faults = nil
+}
+
+// matchFault returns the first injected fault that applies to the call, if any
+func (client *MockClient) matchFault(call Call) *Fault {
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	for _, injected := range client.faults {
+		if injected.method != call.Method || injected.times == 0 || injected.matcher != nil && !injected.matcher(call) {
+			continue
+		}
+		if injected.times > 0 {
+			injected.times--
+		}
+		fault := injected.fault
+		return &fault
+	}
+	return nil
+}
+
+// apply waits for the latency of the fault and returns the response and error it fails the call
+// with, or a nil error if the call should go ahead
+func (f *Fault) apply(ctx context.Context) (*okta.Response, error) {
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	err := f.Err
+	switch {
+	case f.Cancel:
+		return nil, context.Canceled
+	case err == nil && f.Status == 0:
+		return nil, nil
+	case err == nil:
+		oktaErr := NewError(ErrorCodeInternalError)
+		oktaErr.ErrorSummary = http.StatusText(f.Status)
+		err = oktaErr
+	}
+	var oktaErr *okta.Error
+	isOktaErr := errors.As(err, &oktaErr)
+	switch {
+	case f.Status != 0 && isOktaErr:
+		return newResponse(f.Status, oktaErr), err
+	case f.Status != 0:
+		return newResponse(f.Status, nil), err
+	case isOktaErr:
+		return errorResponse(err), err
+	}
+	return nil, err
+}
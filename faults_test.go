@@ -0,0 +1,143 @@
+package mockokta
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func TestMockClient_InjectFault(t *testing.T) {
+	t.Run("should fail the call with the okta error the given number of times", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.InjectFault("AddUserToGroup", nil, Fault{Err: NewError(ErrorCodeInternalError)}, 1)
+
+		resp, err := client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		_, retryErr := client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+
+		assertOktaError(t, resp, err, ErrorCodeInternalError)
+		if retryErr != nil || !client.Group.GroupContainsUser(*group, "TestUser@test.com") {
+			t.Errorf("got %v want the retry to add the user", retryErr)
+		}
+	})
+
+	t.Run("should fail the nth call", func(t *testing.T) {
+		client := NewClient()
+		client.InjectFault("CreateGroup", OnCall(2), Fault{Status: 503}, 0)
+
+		_, _, firstErr := client.CreateGroup(context.TODO(), *NewGroup("First"))
+		group, resp, secondErr := client.CreateGroup(context.TODO(), *NewGroup("Second"))
+		_, _, thirdErr := client.CreateGroup(context.TODO(), *NewGroup("Third"))
+
+		if firstErr != nil || thirdErr != nil {
+			t.Errorf("got %v and %v want no errors", firstErr, thirdErr)
+		}
+		if group != nil || secondErr == nil || resp.StatusCode != 503 {
+			t.Errorf("got %v (%v) want a 503", group, secondErr)
+		}
+	})
+
+	t.Run("should only fail calls with matching arguments", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		client.InjectFault("GetGroup", WithArgs(group.Id), Fault{Err: newNotFoundError("UserGroup", group.Id)}, 0)
+
+		_, resp, err := client.GetGroup(context.TODO(), group.Id)
+		other, _, otherErr := client.GetGroup(context.TODO(), client.Group.EveryoneGroup().Id)
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+		if otherErr != nil || other.Profile.Name != EveryoneGroupName {
+			t.Errorf("got %v (%v) want the Everyone group", other, otherErr)
+		}
+	})
+
+	t.Run("should return other errors without a response", func(t *testing.T) {
+		client := NewClient()
+		client.InjectFault("ListUsers", nil, Fault{Err: io.ErrUnexpectedEOF}, 1)
+
+		_, resp, err := client.ListUsers(context.TODO(), nil)
+
+		if resp != nil || !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("got %v (%v) want the transport error", resp, err)
+		}
+	})
+
+	t.Run("should cancel the call", func(t *testing.T) {
+		client := NewClient()
+		client.InjectFault("ListGroups", nil, Fault{Cancel: true}, 1)
+
+		_, _, err := client.ListGroups(context.TODO(), nil)
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("should delay the call until the context ends", func(t *testing.T) {
+		client := NewClient()
+		client.InjectFault("ListGroups", nil, Fault{Latency: time.Millisecond}, 1)
+		client.InjectFault("ListGroups", nil, Fault{Latency: time.Hour}, 1)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		groups, _, delayedErr := client.ListGroups(ctx, nil)
+		_, _, err := client.ListGroups(ctx, nil)
+
+		if delayedErr != nil || len(groups) != 1 {
+			t.Errorf("got %v (%v) want the Everyone group", groups, delayedErr)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("should stop failing calls once cleared", func(t *testing.T) {
+		client := NewClient()
+		client.InjectFault("ListGroups", nil, Fault{Status: 500}, 0)
+
+		client.ClearFaults()
+		_, _, err := client.ListGroups(context.TODO(), nil)
+
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should panic on unknown methods", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("got no panic want a panic")
+			}
+		}()
+
+		NewClient().InjectFault("AddUserToGorup", nil, Fault{Status: 500}, 0)
+	})
+}
+
+func TestFault_apply(t *testing.T) {
+	tests := []struct {
+		name       string
+		fault      Fault
+		wantStatus int
+		wantCode   string
+	}{
+		{"okta errors", Fault{Err: NewError(ErrorCodeForbidden)}, 403, ErrorCodeForbidden},
+		{"okta errors with a status", Fault{Err: NewError(ErrorCodeForbidden), Status: 429}, 429, ErrorCodeForbidden},
+		{"statuses", Fault{Status: 502}, 502, ErrorCodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run("should fail with "+tt.name, func(t *testing.T) {
+			resp, err := tt.fault.apply(context.TODO())
+
+			oktaErr, ok := err.(*okta.Error)
+			if !ok || oktaErr.ErrorCode != tt.wantCode || resp.StatusCode != tt.wantStatus {
+				t.Errorf("got %v (%v) want %v with a %v", err, resp, tt.wantCode, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package mockokta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is a declarative description of an org's groups, users, memberships and admin roles that
+// can be checked in as a YAML or JSON file. Groups and users refer to each other by group name and
+// user login, so fixtures don't depend on the IDs the mock generates. For example
+//
+//	groups:
+//	  - name: Engineering
+//	    members: [alice@test.com]
+//	    roles:
+//	      - type: USER_ADMIN
+//	users:
+//	  - login: alice@test.com
+//	    profile:
+//	      firstName: Alice
+//	    roles:
+//	      - type: GROUP_ADMIN
+//	        groups: [Engineering]
+type Fixture struct {
+	Groups []FixtureGroup `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Users  []FixtureUser  `json:"users,omitempty" yaml:"users,omitempty"`
+}
+
+// FixtureGroup is an OKTA_GROUP group of a Fixture with the logins of its members
+type FixtureGroup struct {
+	Name        string        `json:"name" yaml:"name"`
+	Description string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Members     []string      `json:"members,omitempty" yaml:"members,omitempty"`
+	Roles       []FixtureRole `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+// FixtureUser is a user of a Fixture. Its status defaults to ACTIVE, its email to its login, and the
+// rest of its profile is validated against the user schema like CreateUser does
+type FixtureUser struct {
+	Login    string                 `json:"login" yaml:"login"`
+	Status   string                 `json:"status,omitempty" yaml:"status,omitempty"`
+	Password string                 `json:"password,omitempty" yaml:"password,omitempty"`
+	Profile  map[string]interface{} `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Roles    []FixtureRole          `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+// FixtureRole is a standard admin role assigned to a group or user of a Fixture, restricted to the
+// groups with the names if any
+type FixtureRole struct {
+	Type   string   `json:"type" yaml:"type"`
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// fixtureStatuses are the statuses a FixtureUser can have
+var fixtureStatuses = []string{
+	UserStatusStaged, UserStatusProvisioned, UserStatusActive, UserStatusRecovery, UserStatusPasswordExpired,
+	UserStatusLockedOut, UserStatusSuspended, UserStatusDeprovisioned,
+}
+
+// LoadFixture reads a Fixture from a .yaml, .yml or .json file. Unknown fields are errors, so typos
+// in hand-written fixtures don't go unnoticed
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fixture := &Fixture{}
+	switch filepath.Ext(path) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(fixture)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(fixture)
+	default:
+		return nil, fmt.Errorf("unsupported fixture format %q, want .yaml, .yml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+// NewClientFromFixture creates a client with the options and the org of the fixture file at the
+// path. The fixture is loaded before the principal of WithPrincipal applies, so it can seed the
+// admins the principal relies on
+func NewClientFromFixture(path string, opts ...ClientOption) (*MockClient, error) {
+	fixture, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient(opts...)
+	principal := client.principal
+	client.principal = ""
+	if err := client.applyFixture(fixture); err != nil {
+		return nil, err
+	}
+	client.principal = principal
+	return client, nil
+}
+
+// applyFixture creates the groups and users of the fixture, then adds the memberships, assigns the
+// roles and finally moves the users to their statuses
+func (client *MockClient) applyFixture(fixture *Fixture) error {
+	ctx := context.TODO()
+	groupIDs := make(map[string]string)
+	for _, fixtureGroup := range fixture.Groups {
+		if _, ok := groupIDs[fixtureGroup.Name]; ok {
+			return fmt.Errorf("group %q: duplicate group name", fixtureGroup.Name)
+		}
+		group := NewGroup(fixtureGroup.Name)
+		group.Profile.Description = fixtureGroup.Description
+		created, _, err := client.Group.CreateGroup(ctx, *group)
+		if err != nil {
+			return fmt.Errorf("group %q: %w", fixtureGroup.Name, err)
+		}
+		groupIDs[fixtureGroup.Name] = created.Id
+	}
+	users := make(map[string]*okta.User)
+	for _, fixtureUser := range fixture.Users {
+		if fixtureUser.Status != "" && !SliceContainsString(fixtureStatuses, fixtureUser.Status) {
+			return fmt.Errorf("user %q: unknown status %q", fixtureUser.Login, fixtureUser.Status)
+		}
+		user, _, err := client.User.CreateUser(ctx, fixtureUser.createUserRequest(), query.NewQueryParams(query.WithActivate(false)))
+		if err != nil {
+			return fmt.Errorf("user %q: %w", fixtureUser.Login, err)
+		}
+		users[fixtureUser.Login] = user
+	}
+	for _, fixtureGroup := range fixture.Groups {
+		groupID := groupIDs[fixtureGroup.Name]
+		for _, login := range fixtureGroup.Members {
+			user, ok := users[login]
+			if !ok {
+				return fmt.Errorf("group %q: unknown member %q", fixtureGroup.Name, login)
+			}
+			if _, err := client.Group.AddUserToGroup(ctx, groupID, user.Id); err != nil {
+				return fmt.Errorf("group %q: adding %q: %w", fixtureGroup.Name, login, err)
+			}
+		}
+		for _, fixtureRole := range fixtureGroup.Roles {
+			err := client.applyFixtureRole(fixtureRole, groupIDs, func() (*okta.Role, error) {
+				role, _, err := client.Group.AssignRoleToGroup(ctx, groupID, NewAssignRoleRequest(fixtureRole.Type), nil)
+				return role, err
+			}, func(roleID string, targetGroupID string) error {
+				_, err := client.Group.AddGroupTargetToGroupAdministratorRoleForGroup(ctx, groupID, roleID, targetGroupID)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("group %q: %w", fixtureGroup.Name, err)
+			}
+		}
+	}
+	for _, fixtureUser := range fixture.Users {
+		userID := users[fixtureUser.Login].Id
+		for _, fixtureRole := range fixtureUser.Roles {
+			err := client.applyFixtureRole(fixtureRole, groupIDs, func() (*okta.Role, error) {
+				role, _, err := client.User.AssignRoleToUser(ctx, userID, NewAssignRoleRequest(fixtureRole.Type), nil)
+				return role, err
+			}, func(roleID string, targetGroupID string) error {
+				_, err := client.User.AddGroupTargetToRole(ctx, userID, roleID, targetGroupID)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("user %q: %w", fixtureUser.Login, err)
+			}
+		}
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	for _, fixtureUser := range fixture.Users {
+		user := users[fixtureUser.Login]
+		status := fixtureUser.Status
+		if status == "" {
+			status = UserStatusActive
+		}
+		if status != UserStatusStaged {
+			setUserStatus(user, status)
+			user.Activated = user.StatusChanged
+		}
+	}
+	return nil
+}
+
+// applyFixtureRole assigns the role with assign, then restricts it to its target groups with addTarget.
+// Target groups that aren't in the fixture are looked up in the org, so roles can target Everyone
+func (client *MockClient) applyFixtureRole(fixtureRole FixtureRole, groupIDs map[string]string, assign func() (*okta.Role, error), addTarget func(roleID string, targetGroupID string) error) error {
+	role, err := assign()
+	if err != nil {
+		return fmt.Errorf("role %s: %w", fixtureRole.Type, err)
+	}
+	for _, name := range fixtureRole.Groups {
+		targetGroupID, ok := groupIDs[name]
+		if !ok {
+			group, err := client.Group.GetGroupByName(name)
+			if err != nil {
+				return fmt.Errorf("role %s: unknown target group %q", fixtureRole.Type, name)
+			}
+			targetGroupID = group.Id
+		}
+		if err := addTarget(role.Id, targetGroupID); err != nil {
+			return fmt.Errorf("role %s: targeting %q: %w", fixtureRole.Type, name, err)
+		}
+	}
+	return nil
+}
+
+// createUserRequest returns the request to create the user with its profile, login and password
+func (fixtureUser FixtureUser) createUserRequest() okta.CreateUserRequest {
+	profile := okta.UserProfile{"email": fixtureUser.Login}
+	for attribute, value := range fixtureUser.Profile {
+		profile[attribute] = value
+	}
+	profile["login"] = fixtureUser.Login
+	body := okta.CreateUserRequest{Profile: &profile}
+	if fixtureUser.Password != "" {
+		body.Credentials = &okta.UserCredentials{Password: &okta.PasswordCredential{Value: fixtureUser.Password}}
+	}
+	return body
+}
+
+// Fixture returns the current org of the client as a Fixture. Only OKTA_GROUP groups and standard
+// admin roles are included, and passwords, app targets and custom roles are left out. Roles targeting
+// groups other than OKTA_GROUP groups and Everyone are left out too, rather than loading unrestricted
+func (client *MockClient) Fixture() *Fixture {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	groupNames := make(map[string]string)
+	for _, group := range client.Group.Groups {
+		if group.Type == GroupTypeOkta || group.Type == GroupTypeBuiltIn {
+			groupNames[group.Id] = group.Profile.Name
+		}
+	}
+	logins := make(map[string]string)
+	for _, user := range client.User.Users {
+		logins[user.Id] = userLogin(user)
+	}
+	fixtureRoles := func(roles []*okta.Role, targets map[string]*RoleTargets) []FixtureRole {
+		var fixtureRoles []FixtureRole
+		for _, role := range roles {
+			if role.Type == CustomRoleType {
+				continue
+			}
+			fixtureRole := FixtureRole{Type: role.Type}
+			if roleTargets, ok := targets[role.Id]; ok {
+				for _, groupID := range roleTargets.Groups {
+					name, ok := groupNames[groupID]
+					if !ok {
+						break
+					}
+					fixtureRole.Groups = append(fixtureRole.Groups, name)
+				}
+				if len(fixtureRole.Groups) < len(roleTargets.Groups) {
+					continue
+				}
+			}
+			fixtureRoles = append(fixtureRoles, fixtureRole)
+		}
+		return fixtureRoles
+	}
+
+	fixture := &Fixture{}
+	for _, group := range client.Group.Groups {
+		if group.Type != GroupTypeOkta {
+			continue
+		}
+		fixtureGroup := FixtureGroup{
+			Name:        group.Profile.Name,
+			Description: group.Profile.Description,
+			Roles:       fixtureRoles(client.Group.GroupRoles[group.Id], client.Group.RoleTargets),
+		}
+		for _, userID := range client.Group.GroupUsers[group.Id] {
+			fixtureGroup.Members = append(fixtureGroup.Members, logins[userID])
+		}
+		fixture.Groups = append(fixture.Groups, fixtureGroup)
+	}
+	for _, user := range client.User.Users {
+		fixtureUser := FixtureUser{
+			Login:  userLogin(user),
+			Status: user.Status,
+			Roles:  fixtureRoles(client.User.UserRoles[user.Id], client.User.RoleTargets),
+		}
+		if user.Profile != nil {
+			for attribute, value := range *user.Profile {
+				if attribute == "login" || attribute == "email" && value == fixtureUser.Login {
+					continue
+				}
+				if fixtureUser.Profile == nil {
+					fixtureUser.Profile = make(map[string]interface{})
+				}
+				fixtureUser.Profile[attribute] = value
+			}
+		}
+		if fixtureUser.Status == UserStatusActive {
+			fixtureUser.Status = ""
+		}
+		fixture.Users = append(fixture.Users, fixtureUser)
+	}
+	return fixture
+}
+
+// Export writes the current org of the client to a .yaml, .yml or .json fixture file that
+// NewClientFromFixture can load. See Fixture for what is left out
+func (client *MockClient) Export(path string) error {
+	fixture := client.Fixture()
+	var data []byte
+	var err error
+	switch filepath.Ext(path) {
+	case ".json":
+		data, err = json.MarshalIndent(fixture, "", "  ")
+		data = append(data, '\n')
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(fixture)
+	default:
+		return fmt.Errorf("unsupported fixture format %q, want .yaml, .yml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,195 @@
+package mockokta
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+const testFixtureYAML = `groups:
+  - name: Engineering
+    description: Builds things
+    members: [alice@test.com, bob@test.com]
+    roles:
+      - type: USER_ADMIN
+  - name: Support
+    members: [carol@test.com]
+users:
+  - login: alice@test.com
+    password: Passw0rd!
+    profile:
+      firstName: Alice
+    roles:
+      - type: GROUP_ADMIN
+        groups: [Support]
+  - login: bob@test.com
+    status: SUSPENDED
+  - login: carol@test.com
+    status: STAGED
+`
+
+// writeTestFixture writes the fixture to a file with the name in a temporary directory
+func writeTestFixture(t *testing.T, name string, fixture string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return path
+}
+
+func mustGetGroupByName(t *testing.T, client *MockClient, name string) *okta.Group {
+	t.Helper()
+	group, err := client.Group.GetGroupByName(name)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return group
+}
+
+func TestNewClientFromFixture(t *testing.T) {
+	t.Run("should create the groups, users, memberships and roles", func(t *testing.T) {
+		client, err := NewClientFromFixture(writeTestFixture(t, "org.yaml", testFixtureYAML))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		engineering := mustGetGroupByName(t, client, "Engineering")
+		support := mustGetGroupByName(t, client, "Support")
+		alice, _, _ := client.GetUser(context.TODO(), "alice@test.com")
+		bob, _, _ := client.GetUser(context.TODO(), "bob@test.com")
+		carol, _, _ := client.GetUser(context.TODO(), "carol@test.com")
+		members, _, _ := client.ListGroupUsers(context.TODO(), engineering.Id, nil)
+		aliceRoles, _, _ := client.ListAssignedRolesForUser(context.TODO(), alice.Id, nil)
+		directRoles := client.User.UserRoles[alice.Id]
+		targets, _, _ := client.ListGroupTargetsForRole(context.TODO(), alice.Id, directRoles[0].Id, nil)
+
+		if engineering.Profile.Description != "Builds things" || len(members) != 2 {
+			t.Errorf("got group %v with members %v", engineering, members)
+		}
+		if alice.Status != UserStatusActive || (*alice.Profile)["firstName"] != "Alice" || (*alice.Profile)["email"] != "alice@test.com" {
+			t.Errorf("got user %v", alice)
+		}
+		if bob.Status != UserStatusSuspended || carol.Status != UserStatusStaged {
+			t.Errorf("got statuses %v and %v want SUSPENDED and STAGED", bob.Status, carol.Status)
+		}
+		if len(aliceRoles) != 2 || len(directRoles) != 1 || directRoles[0].Type != "GROUP_ADMIN" {
+			t.Errorf("got roles %v want USER_ADMIN through Engineering and GROUP_ADMIN", aliceRoles)
+		}
+		if len(targets) != 1 || targets[0].Id != support.Id {
+			t.Errorf("got targets %v want %v", targets, support)
+		}
+	})
+
+	t.Run("should load JSON fixtures", func(t *testing.T) {
+		path := writeTestFixture(t, "org.json", `{"groups": [{"name": "Engineering", "members": ["alice@test.com"]}], "users": [{"login": "alice@test.com"}]}`)
+
+		client, err := NewClientFromFixture(path)
+
+		if err != nil || !client.Group.GroupContainsUser(*mustGetGroupByName(t, client, "Engineering"), "alice@test.com") {
+			t.Errorf("got %v want alice to be in Engineering", err)
+		}
+	})
+
+	t.Run("should load the fixture before restricting the client", func(t *testing.T) {
+		path := writeTestFixture(t, "org.yaml", testFixtureYAML)
+
+		client, err := NewClientFromFixture(path, WithPrincipal("bob@test.com"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		_, resp, err := client.CreateGroup(context.TODO(), *NewGroup("Other"))
+
+		assertForbidden(t, resp, err)
+	})
+
+	tests := []struct {
+		name    string
+		file    string
+		fixture string
+		want    string
+	}{
+		{"unknown fields", "org.yaml", "groups:\n  - nmae: Engineering\n", "field nmae not found"},
+		{"unknown formats", "org.toml", "", "unsupported fixture format"},
+		{"unknown members", "org.yaml", "groups:\n  - name: Engineering\n    members: [alice@test.com]\n", `unknown member "alice@test.com"`},
+		{"unknown statuses", "org.yaml", "users:\n  - login: alice@test.com\n    status: HAPPY\n", `unknown status "HAPPY"`},
+		{"unknown target groups", "org.yaml", "users:\n  - login: alice@test.com\n    roles:\n      - type: GROUP_ADMIN\n        groups: [Support]\n", `unknown target group "Support"`},
+		{"duplicate groups", "org.yaml", "groups:\n  - name: Engineering\n  - name: Engineering\n", "duplicate group name"},
+		{"invalid profiles", "org.yaml", "users:\n  - login: alice@test.com\n    profile:\n      nickName: 1\n", "nickName"},
+	}
+	for _, tt := range tests {
+		t.Run("should err on "+tt.name, func(t *testing.T) {
+			_, err := NewClientFromFixture(writeTestFixture(t, tt.file, tt.fixture))
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v want an error containing %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMockClient_Export(t *testing.T) {
+	for _, file := range []string{"org.yaml", "org.json"} {
+		t.Run("should export a fixture that loads the same org as "+file, func(t *testing.T) {
+			client, err := NewClientFromFixture(writeTestFixture(t, "org.yaml", testFixtureYAML))
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			path := filepath.Join(t.TempDir(), file)
+
+			if err := client.Export(path); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			exported, err := NewClientFromFixture(path)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if got, want := exported.Fixture(), client.Fixture(); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v want %+v", got, want)
+			}
+		})
+	}
+
+	t.Run("should export roles targeting Everyone and leave out the ones targeting app groups", func(t *testing.T) {
+		client := NewClient()
+		everyone := client.Group.everyoneGroup()
+		appGroup, _, _ := client.Group.CreateAppGroup(context.TODO(), *NewGroup("AD Users"))
+		newTestAdmin(t, client, "everyone@test.com", "HELP_DESK_ADMIN", everyone.Id)
+		newTestAdmin(t, client, "app@test.com", "GROUP_ADMIN", appGroup.Id)
+		path := filepath.Join(t.TempDir(), "org.yaml")
+
+		if err := client.Export(path); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		exported, err := NewClientFromFixture(path)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		everyoneAdmin, _ := exported.User.getUser("everyone@test.com")
+		appAdmin, _ := exported.User.getUser("app@test.com")
+		everyoneRoles := exported.User.UserRoles[everyoneAdmin.Id]
+		if len(everyoneRoles) != 1 || !reflect.DeepEqual(exported.User.RoleTargets[everyoneRoles[0].Id].Groups, []string{exported.Group.everyoneGroup().Id}) {
+			t.Errorf("got roles %+v want the help desk admin role targeting Everyone", everyoneRoles)
+		}
+		if roles := exported.User.UserRoles[appAdmin.Id]; len(roles) != 0 {
+			t.Errorf("got roles %+v want none", roles)
+		}
+	})
+
+	t.Run("should leave out passwords and built-in groups", func(t *testing.T) {
+		client, _ := NewClientFromFixture(writeTestFixture(t, "org.yaml", testFixtureYAML))
+
+		fixture := client.Fixture()
+
+		if len(fixture.Groups) != 2 || fixture.Users[0].Password != "" || fixture.Users[0].Status != "" {
+			t.Errorf("got %+v", fixture)
+		}
+	})
+}
@@ -0,0 +1,383 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// Okta group rule statuses and types, see https://developer.okta.com/docs/reference/api/groups/#group-rule-object
+const (
+	GroupRuleStatusActive   = "ACTIVE"
+	GroupRuleStatusInactive = "INACTIVE"
+	GroupRuleStatusInvalid  = "INVALID"
+	GroupRuleType           = "group_rule"
+	GroupRuleExpressionType = "urn:okta:expression:1.0"
+)
+
+// CreateGroupRule is a wrapper to call client.Group.CreateGroupRule to make it easier to match an interface for the okta client
+func (client *MockClient) CreateGroupRule(ctx context.Context, body okta.GroupRule) (*okta.GroupRule, *okta.Response, error) {
+	return intercept(ctx, client, "CreateGroupRule", []interface{}{body}, func() (*okta.GroupRule, *okta.Response, error) {
+		return client.Group.CreateGroupRule(ctx, body)
+	})
+}
+
+// GetGroupRule is a wrapper to call client.Group.GetGroupRule to make it easier to match an interface for the okta client
+func (client *MockClient) GetGroupRule(ctx context.Context, ruleID string, qp *query.Params) (*okta.GroupRule, *okta.Response, error) {
+	return intercept(ctx, client, "GetGroupRule", []interface{}{ruleID, qp}, func() (*okta.GroupRule, *okta.Response, error) {
+		return client.Group.GetGroupRule(ctx, ruleID, qp)
+	})
+}
+
+// ListGroupRules is a wrapper to call client.Group.ListGroupRules to make it easier to match an interface for the okta client
+func (client *MockClient) ListGroupRules(ctx context.Context, qp *query.Params) ([]*okta.GroupRule, *okta.Response, error) {
+	return intercept(ctx, client, "ListGroupRules", []interface{}{qp}, func() ([]*okta.GroupRule, *okta.Response, error) {
+		return client.Group.ListGroupRules(ctx, qp)
+	})
+}
+
+// ActivateGroupRule is a wrapper to call client.Group.ActivateGroupRule to make it easier to match an interface for the okta client
+func (client *MockClient) ActivateGroupRule(ctx context.Context, ruleID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "ActivateGroupRule", []interface{}{ruleID}, func() (*okta.Response, error) {
+		return client.Group.ActivateGroupRule(ctx, ruleID)
+	})
+}
+
+// DeactivateGroupRule is a wrapper to call client.Group.DeactivateGroupRule to make it easier to match an interface for the okta client
+func (client *MockClient) DeactivateGroupRule(ctx context.Context, ruleID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeactivateGroupRule", []interface{}{ruleID}, func() (*okta.Response, error) {
+		return client.Group.DeactivateGroupRule(ctx, ruleID)
+	})
+}
+
+// DeleteGroupRule is a wrapper to call client.Group.DeleteGroupRule to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteGroupRule(ctx context.Context, ruleID string, qp *query.Params) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteGroupRule", []interface{}{ruleID, qp}, func() (*okta.Response, error) {
+		return client.Group.DeleteGroupRule(ctx, ruleID, qp)
+	})
+}
+
+// NewGroupRule creates a group rule assigning users matching the Okta Expression Language expression
+// to the groups
+func NewGroupRule(name string, expression string, groupIDs ...string) okta.GroupRule {
+	return okta.GroupRule{
+		Name: name,
+		Type: GroupRuleType,
+		Conditions: &okta.GroupRuleConditions{
+			Expression: &okta.GroupRuleExpression{Type: GroupRuleExpressionType, Value: expression},
+		},
+		Actions: &okta.GroupRuleAction{
+			AssignUserToGroups: &okta.GroupRuleGroupAssignment{GroupIds: append([]string{}, groupIDs...)},
+		},
+	}
+}
+
+// CreateGroupRule validates the rule and adds it INACTIVE, like Okta, so it assigns no users until it is activated
+func (g *GroupResource) CreateGroupRule(ctx context.Context, body okta.GroupRule) (*okta.GroupRule, *okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.validateGroupRule(body); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if err := g.authorizeGroupRule(body); err != nil {
+		return nil, errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	rule := NewGroupRule(body.Name, body.Conditions.Expression.Value, body.Actions.AssignUserToGroups.GroupIds...)
+	rule.Conditions.People = body.Conditions.People
+	rule.Id = g.Client.ids.NewID(GroupRuleIDPrefix)
+	rule.Status = GroupRuleStatusInactive
+	rule.Created = &now
+	rule.LastUpdated = &now
+	g.GroupRules = append(g.GroupRules, &rule)
+	return &rule, newResponse(http.StatusOK, &rule), nil
+}
+
+// validateGroupRule checks the rule has a name, a valid expression and only targets existing OKTA_GROUP groups
+func (g *GroupResource) validateGroupRule(rule okta.GroupRule) *okta.Error {
+	if len(rule.Name) < 1 || len(rule.Name) > 50 {
+		return newValidationError("name", "size must be between 1 and 50")
+	}
+	if rule.Conditions == nil || rule.Conditions.Expression == nil || rule.Conditions.Expression.Value == "" {
+		return newValidationError("conditions.expression.value", "The field cannot be left blank")
+	}
+	if _, err := parseEL(rule.Conditions.Expression.Value); err != nil {
+		return newValidationError("conditions.expression.value", fmt.Sprintf("Invalid expression: %v", err))
+	}
+	if rule.Actions == nil || rule.Actions.AssignUserToGroups == nil || len(rule.Actions.AssignUserToGroups.GroupIds) == 0 {
+		return newValidationError("actions.assignUserToGroups.groupIds", "The field cannot be left blank")
+	}
+	for _, groupID := range rule.Actions.AssignUserToGroups.GroupIds {
+		group, err := g.getGroupByID(groupID)
+		if err != nil {
+			return newValidationError("actions.assignUserToGroups.groupIds", fmt.Sprintf("Group %v does not exist", groupID))
+		}
+		if group.Type != GroupTypeOkta {
+			return newValidationError("actions.assignUserToGroups.groupIds", fmt.Sprintf("Cannot assign users to a group of type %v", group.Type))
+		}
+	}
+	return nil
+}
+
+// authorizeGroupRule returns an error unless the principal can manage every group the rule assigns users to
+func (g *GroupResource) authorizeGroupRule(rule okta.GroupRule) *okta.Error {
+	for _, groupID := range ruleGroupIDs(&rule) {
+		if err := g.Client.authorizeGroup("okta.groups.manage", groupID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetGroupRule returns the group rule with the ruleID
+func (g *GroupResource) GetGroupRule(ctx context.Context, ruleID string, qp *query.Params) (*okta.GroupRule, *okta.Response, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	rule, err := g.getGroupRule(ruleID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return rule, newResponse(http.StatusOK, rule), nil
+}
+
+func (g *GroupResource) getGroupRule(ruleID string) (*okta.GroupRule, error) {
+	for _, rule := range g.GroupRules {
+		if rule.Id == ruleID {
+			return rule, nil
+		}
+	}
+	return nil, newNotFoundError("GroupRule", ruleID)
+}
+
+// ListGroupRules returns a page of the group rules
+func (g *GroupResource) ListGroupRules(ctx context.Context, qp *query.Params) ([]*okta.GroupRule, *okta.Response, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	rules, after := paginate(g.GroupRules, func(rule *okta.GroupRule) string { return rule.Id }, qp)
+	return rules, g.Client.listResponse(ctx, "/api/v1/groups/rules", qp, after), nil
+}
+
+// ActivateGroupRule activates the rule and adds every user matching it to its groups
+func (g *GroupResource) ActivateGroupRule(ctx context.Context, ruleID string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	rule, err := g.getGroupRule(ruleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if err := g.authorizeGroupRule(*rule); err != nil {
+		return errorResponse(err), err
+	}
+	if rule.Status == GroupRuleStatusInvalid {
+		err := newValidationError("status", "Cannot activate an invalid rule")
+		return errorResponse(err), err
+	}
+	setGroupRuleStatus(rule, GroupRuleStatusActive)
+	g.applyGroupRules()
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// DeactivateGroupRule deactivates the rule. Like Okta, the users it assigned stay in its groups, but
+// they are no longer added or removed as users change
+func (g *GroupResource) DeactivateGroupRule(ctx context.Context, ruleID string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	rule, err := g.getGroupRule(ruleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if err := g.authorizeGroupRule(*rule); err != nil {
+		return errorResponse(err), err
+	}
+	if rule.Status != GroupRuleStatusInvalid {
+		setGroupRuleStatus(rule, GroupRuleStatusInactive)
+	}
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// DeleteGroupRule deletes the rule, removing the users it assigned from its groups if the removeUsers
+// query param is true
+func (g *GroupResource) DeleteGroupRule(ctx context.Context, ruleID string, qp *query.Params) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	rule, err := g.getGroupRule(ruleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if err := g.authorizeGroupRule(*rule); err != nil {
+		return errorResponse(err), err
+	}
+	if qp != nil && qp.RemoveUsers != nil && *qp.RemoveUsers {
+		for groupID, userIDs := range g.GroupRuleUsers[rule.Id] {
+			g.unassignRuleUsers(rule, groupID, userIDs)
+		}
+	}
+	delete(g.GroupRuleUsers, rule.Id)
+	for idx, x := range g.GroupRules {
+		if x.Id == ruleID {
+			g.GroupRules = append(g.GroupRules[:idx], g.GroupRules[idx+1:]...)
+			break
+		}
+	}
+	g.applyGroupRules()
+	return newResponse(http.StatusAccepted, nil), nil
+}
+
+func setGroupRuleStatus(rule *okta.GroupRule, status string) {
+	now := time.Now().UTC()
+	rule.Status = status
+	rule.LastUpdated = &now
+}
+
+// invalidateGroupRules marks the rules assigning users to a deleted group as INVALID, like Okta
+func (g *GroupResource) invalidateGroupRules(groupID string) {
+	for _, rule := range g.GroupRules {
+		if SliceContainsString(ruleGroupIDs(rule), groupID) {
+			setGroupRuleStatus(rule, GroupRuleStatusInvalid)
+		}
+	}
+}
+
+// applyGroupRules recomputes the memberships assigned by active group rules after users, groups or
+// rules change. Rules can depend on the memberships assigned by other rules with isMemberOfGroup,
+// so they are applied until memberships stop changing. Users added to a rule's groups by hand stay
+// in them when they stop matching it
+func (g *GroupResource) applyGroupRules() {
+	for pass := 0; pass <= len(g.GroupRules); pass++ {
+		changed := false
+		for _, rule := range g.GroupRules {
+			if rule.Status != GroupRuleStatusActive {
+				continue
+			}
+			node, err := parseEL(rule.Conditions.Expression.Value)
+			if err != nil {
+				continue
+			}
+			matched := make([]string, 0)
+			for _, user := range g.Client.User.Users {
+				if g.ruleMatchesUser(rule, node, user) {
+					matched = append(matched, user.Id)
+				}
+			}
+			for _, groupID := range ruleGroupIDs(rule) {
+				unmatched := make([]string, 0)
+				for _, userID := range g.GroupRuleUsers[rule.Id][groupID] {
+					if !SliceContainsString(matched, userID) {
+						unmatched = append(unmatched, userID)
+					}
+				}
+				if g.unassignRuleUsers(rule, groupID, unmatched) {
+					changed = true
+				}
+				if g.assignRuleUsers(rule, groupID, matched) {
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// ruleMatchesUser evaluates the rule's expression and excluded users for a user
+func (g *GroupResource) ruleMatchesUser(rule *okta.GroupRule, node elNode, user *okta.User) bool {
+	if people := rule.Conditions.People; people != nil && people.Users != nil && SliceContainsString(people.Users.Exclude, user.Id) {
+		return false
+	}
+	groups := make([]*okta.Group, 0)
+	for _, group := range g.Groups {
+		if SliceContainsString(g.GroupUsers[group.Id], user.Id) {
+			groups = append(groups, group)
+		}
+	}
+	return isTrue(node.value(elContext{user: user, groups: groups}))
+}
+
+// assignRuleUsers adds the users to the group, recording the memberships as assigned by the rule, and
+// reports whether any memberships changed. Users added to the group by hand rather than by a rule
+// are left alone, so their memberships outlive the rule
+func (g *GroupResource) assignRuleUsers(rule *okta.GroupRule, groupID string, userIDs []string) bool {
+	group, err := g.getGroupByID(groupID)
+	if err != nil {
+		return false
+	}
+	changed := false
+	for _, userID := range userIDs {
+		isMember := SliceContainsString(g.GroupUsers[groupID], userID)
+		if isMember && !g.assignedByRule(groupID, userID) || SliceContainsString(g.GroupRuleUsers[rule.Id][groupID], userID) {
+			continue
+		}
+		if g.GroupRuleUsers[rule.Id] == nil {
+			g.GroupRuleUsers[rule.Id] = make(map[string][]string)
+		}
+		g.GroupRuleUsers[rule.Id][groupID] = append(g.GroupRuleUsers[rule.Id][groupID], userID)
+		if !isMember {
+			g.GroupUsers[groupID] = append(g.GroupUsers[groupID], userID)
+			membershipUpdated(group)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// unassignRuleUsers forgets the rule's memberships of the users in the group, removing the users
+// that no other rule assigned to it, and reports whether any memberships changed
+func (g *GroupResource) unassignRuleUsers(rule *okta.GroupRule, groupID string, userIDs []string) bool {
+	changed := false
+	for _, userID := range userIDs {
+		if !SliceContainsString(g.GroupRuleUsers[rule.Id][groupID], userID) {
+			continue
+		}
+		g.GroupRuleUsers[rule.Id][groupID] = removeString(g.GroupRuleUsers[rule.Id][groupID], userID)
+		if g.assignedByRule(groupID, userID) {
+			continue
+		}
+		if group, err := g.getGroupByID(groupID); err == nil && SliceContainsString(g.GroupUsers[groupID], userID) {
+			g.GroupUsers[groupID] = removeString(g.GroupUsers[groupID], userID)
+			membershipUpdated(group)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// assignedByRule returns whether any rule recorded the membership of the user in the group
+func (g *GroupResource) assignedByRule(groupID string, userID string) bool {
+	for _, assigned := range g.GroupRuleUsers {
+		if SliceContainsString(assigned[groupID], userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleAssigningUser returns the active rule that assigns the user to the group, if any
+func (g *GroupResource) ruleAssigningUser(groupID string, user *okta.User) *okta.GroupRule {
+	for _, rule := range g.GroupRules {
+		if rule.Status != GroupRuleStatusActive || !SliceContainsString(ruleGroupIDs(rule), groupID) {
+			continue
+		}
+		if node, err := parseEL(rule.Conditions.Expression.Value); err == nil && g.ruleMatchesUser(rule, node, user) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func ruleGroupIDs(rule *okta.GroupRule) []string {
+	if rule.Actions == nil || rule.Actions.AssignUserToGroups == nil {
+		return nil
+	}
+	return rule.Actions.AssignUserToGroups.GroupIds
+}
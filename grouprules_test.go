@@ -0,0 +1,286 @@
+package mockokta
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+func newUserInDepartment(t *testing.T, client *MockClient, email string, department string) *okta.User {
+	t.Helper()
+	request := NewCreateUserRequest(email)
+	(*request.Profile)["department"] = department
+	user, _, err := client.User.CreateUser(context.TODO(), request, nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return user
+}
+
+func newActiveGroupRule(t *testing.T, client *MockClient, expression string, groupIDs ...string) *okta.GroupRule {
+	t.Helper()
+	rule, _, err := client.CreateGroupRule(context.TODO(), NewGroupRule("TestRule", expression, groupIDs...))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := client.ActivateGroupRule(context.TODO(), rule.Id); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return rule
+}
+
+func TestGroupResource_CreateGroupRule(t *testing.T) {
+	t.Run("should create an inactive rule", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		user := newUserInDepartment(t, client, "TestUser@test.com", "Engineering")
+
+		rule, _, err := client.CreateGroupRule(context.TODO(), NewGroupRule("TestRule", `user.department == "Engineering"`, group.Id))
+
+		if err != nil || rule.Status != GroupRuleStatusInactive || !strings.HasPrefix(rule.Id, GroupRuleIDPrefix) {
+			t.Fatalf("got %v (%v) want an inactive rule", rule, err)
+		}
+		if client.Group.GroupContainsUser(*group, (*user.Profile)["email"].(string)) {
+			t.Errorf("expected inactive rule not to assign users")
+		}
+	})
+
+	t.Run("should validate the rule", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		appGroup, _, _ := client.Group.CreateAppGroup(context.TODO(), *NewGroup("AppGroup"))
+
+		for _, rule := range []okta.GroupRule{
+			NewGroupRule("", `user.department == "Engineering"`, group.Id),
+			NewGroupRule("TestRule", ``, group.Id),
+			NewGroupRule("TestRule", `user.department = "Engineering"`, group.Id),
+			NewGroupRule("TestRule", `user.department == "Engineering"`),
+			NewGroupRule("TestRule", `user.department == "Engineering"`, "NonExistentId"),
+			NewGroupRule("TestRule", `user.department == "Engineering"`, appGroup.Id),
+			{Name: "TestRule"},
+		} {
+			_, resp, err := client.CreateGroupRule(context.TODO(), rule)
+
+			assertOktaError(t, resp, err, ErrorCodeValidation)
+		}
+		if len(client.Group.GroupRules) != 0 {
+			t.Errorf("got %v want no rules", client.Group.GroupRules)
+		}
+	})
+}
+
+func TestGroupResource_GetGroupRule(t *testing.T) {
+	t.Run("should get and list rules", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		rule, _, _ := client.CreateGroupRule(context.TODO(), NewGroupRule("TestRule", `user.department == "Engineering"`, group.Id))
+
+		got, _, err := client.GetGroupRule(context.TODO(), rule.Id, nil)
+		rules, _, _ := client.ListGroupRules(context.TODO(), nil)
+
+		if err != nil || got != rule || len(rules) != 1 || rules[0] != rule {
+			t.Errorf("got %v and %v (%v) want %v", got, rules, err, rule)
+		}
+	})
+
+	t.Run("should err if rule doesn't exist", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.GetGroupRule(context.TODO(), "NonExistentId", nil)
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
+}
+
+func TestGroupResource_GroupRuleMemberships(t *testing.T) {
+	t.Run("should assign matching users when activated", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		engineer := newUserInDepartment(t, client, "Engineer@test.com", "Engineering")
+		newUserInDepartment(t, client, "Seller@test.com", "Sales")
+
+		newActiveGroupRule(t, client, `user.department == "Engineering"`, group.Id)
+		got, _, _ := client.ListGroupUsers(context.TODO(), group.Id, nil)
+
+		if len(got) != 1 || got[0] != engineer {
+			t.Errorf("got %v want %v", got, engineer)
+		}
+	})
+
+	t.Run("should assign matching users when they are created", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		rule := newActiveGroupRule(t, client, `user.department == "Engineering"`, group.Id)
+
+		engineer := newUserInDepartment(t, client, "Engineer@test.com", "Engineering")
+		got, _, _ := client.ListGroupUsers(context.TODO(), group.Id, nil)
+
+		if len(got) != 1 || got[0] != engineer || client.Group.GroupRuleUsers[rule.Id][group.Id][0] != engineer.Id {
+			t.Errorf("got %v want %v", got, engineer)
+		}
+	})
+
+	t.Run("should recompute memberships when group memberships change", func(t *testing.T) {
+		client := NewClient()
+		contractors, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Contractors"))
+		restricted, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Restricted"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		newActiveGroupRule(t, client, `isMemberOfGroupName("Contractors")`, restricted.Id)
+
+		client.AddUserToGroup(context.TODO(), contractors.Id, user.Id)
+		if !client.Group.GroupContainsUser(*restricted, "TestUser@test.com") {
+			t.Errorf("expected user to be assigned to %v", restricted.Profile.Name)
+		}
+		client.RemoveUserFromGroup(context.TODO(), contractors.Id, user.Id)
+		if client.Group.GroupContainsUser(*restricted, "TestUser@test.com") {
+			t.Errorf("expected user to be unassigned from %v", restricted.Profile.Name)
+		}
+	})
+
+	t.Run("should apply rules that depend on other rules", func(t *testing.T) {
+		client := NewClient()
+		engineering, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		vpn, _, _ := client.CreateGroup(context.TODO(), *NewGroup("VPN"))
+		newActiveGroupRule(t, client, `isMemberOfGroupName("Engineering")`, vpn.Id)
+		newActiveGroupRule(t, client, `user.department == "Engineering"`, engineering.Id)
+
+		newUserInDepartment(t, client, "Engineer@test.com", "Engineering")
+
+		if !client.Group.GroupContainsUser(*vpn, "Engineer@test.com") {
+			t.Errorf("expected user to be assigned to %v", vpn.Profile.Name)
+		}
+	})
+
+	t.Run("should keep users assigned by another rule", func(t *testing.T) {
+		client := NewClient()
+		contractors, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Contractors"))
+		restricted, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Restricted"))
+		user := newUserInDepartment(t, client, "TestUser@test.com", "Security")
+		newActiveGroupRule(t, client, `isMemberOfGroupName("Contractors")`, restricted.Id)
+		newActiveGroupRule(t, client, `user.department == "Security"`, restricted.Id)
+
+		client.AddUserToGroup(context.TODO(), contractors.Id, user.Id)
+		client.RemoveUserFromGroup(context.TODO(), contractors.Id, user.Id)
+
+		if !client.Group.GroupContainsUser(*restricted, "TestUser@test.com") {
+			t.Errorf("expected user to stay in %v", restricted.Profile.Name)
+		}
+	})
+
+	t.Run("should keep users added by hand when they stop matching", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		manual := newUserInDepartment(t, client, "Manual@test.com", "Engineering")
+		newUserInDepartment(t, client, "Engineer@test.com", "Engineering")
+		client.AddUserToGroup(context.TODO(), group.Id, manual.Id)
+		newActiveGroupRule(t, client, `user.department == "Engineering"`, group.Id)
+		profile := okta.UserProfile{"department": "Sales"}
+
+		for _, login := range []string{"Manual@test.com", "Engineer@test.com"} {
+			user, _ := client.User.getUser(login)
+			client.PartialUpdateUser(context.TODO(), user.Id, okta.User{Profile: &profile}, nil)
+		}
+
+		if !client.Group.GroupContainsUser(*group, "Manual@test.com") || client.Group.GroupContainsUser(*group, "Engineer@test.com") {
+			t.Errorf("got users %v want only the one added by hand", client.Group.GroupUsers[group.Id])
+		}
+	})
+
+	t.Run("should err removing users a rule assigns", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		engineer := newUserInDepartment(t, client, "Engineer@test.com", "Engineering")
+		rule := newActiveGroupRule(t, client, `user.department == "Engineering"`, group.Id)
+
+		resp, err := client.RemoveUserFromGroup(context.TODO(), group.Id, engineer.Id)
+		client.DeactivateGroupRule(context.TODO(), rule.Id)
+		_, inactiveErr := client.RemoveUserFromGroup(context.TODO(), group.Id, engineer.Id)
+
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+		if inactiveErr != nil || client.Group.GroupContainsUser(*group, "Engineer@test.com") {
+			t.Errorf("got %v want the user removed once the rule is inactive", inactiveErr)
+		}
+	})
+
+	t.Run("should not assign excluded users", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		user := newUserInDepartment(t, client, "Engineer@test.com", "Engineering")
+		rule := NewGroupRule("TestRule", `user.department == "Engineering"`, group.Id)
+		rule.Conditions.People = &okta.GroupRulePeopleCondition{Users: &okta.GroupRuleUserCondition{Exclude: []string{user.Id}}}
+
+		created, _, _ := client.CreateGroupRule(context.TODO(), rule)
+		client.ActivateGroupRule(context.TODO(), created.Id)
+
+		if client.Group.GroupContainsUser(*group, "Engineer@test.com") {
+			t.Errorf("expected excluded user not to be assigned")
+		}
+	})
+
+	t.Run("should stop applying deactivated rules but keep their users", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		newUserInDepartment(t, client, "Engineer1@test.com", "Engineering")
+		rule := newActiveGroupRule(t, client, `user.department == "Engineering"`, group.Id)
+
+		client.DeactivateGroupRule(context.TODO(), rule.Id)
+		newUserInDepartment(t, client, "Engineer2@test.com", "Engineering")
+
+		if rule.Status != GroupRuleStatusInactive || !client.Group.GroupContainsUser(*group, "Engineer1@test.com") || client.Group.GroupContainsUser(*group, "Engineer2@test.com") {
+			t.Errorf("got %v with users %v", rule.Status, client.Group.GroupUsers[group.Id])
+		}
+	})
+
+	t.Run("should remove the users of a deleted rule when asked to", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		newUserInDepartment(t, client, "Engineer@test.com", "Engineering")
+		keep := newActiveGroupRule(t, client, `user.department == "Engineering"`, group.Id)
+		remove := newActiveGroupRule(t, client, `user.department == "Engineering"`, group.Id)
+		client.DeactivateGroupRule(context.TODO(), keep.Id)
+		client.DeactivateGroupRule(context.TODO(), remove.Id)
+
+		client.DeleteGroupRule(context.TODO(), keep.Id, nil)
+		if !client.Group.GroupContainsUser(*group, "Engineer@test.com") {
+			t.Errorf("expected user to stay in the group")
+		}
+		resp, err := client.DeleteGroupRule(context.TODO(), remove.Id, query.NewQueryParams(query.WithRemoveUsers(true)))
+		if err != nil || resp.StatusCode != 202 || client.Group.GroupContainsUser(*group, "Engineer@test.com") {
+			t.Errorf("expected user to be removed from the group but got %v", err)
+		}
+		if len(client.Group.GroupRules) != 0 || len(client.Group.GroupRuleUsers) != 0 {
+			t.Errorf("got %v want no rules", client.Group.GroupRules)
+		}
+	})
+
+	t.Run("should invalidate rules for a deleted group", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		rule, _, _ := client.CreateGroupRule(context.TODO(), NewGroupRule("TestRule", `user.department == "Engineering"`, group.Id))
+
+		client.DeleteGroup(context.TODO(), group.Id)
+		resp, err := client.ActivateGroupRule(context.TODO(), rule.Id)
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+		if rule.Status != GroupRuleStatusInvalid {
+			t.Errorf("got %v want %v", rule.Status, GroupRuleStatusInvalid)
+		}
+	})
+
+	t.Run("should forget deleted users", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		user := newUserInDepartment(t, client, "Engineer@test.com", "Engineering")
+		rule := newActiveGroupRule(t, client, `user.department == "Engineering"`, group.Id)
+
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+
+		if len(client.Group.GroupRuleUsers[rule.Id][group.Id]) != 0 || len(client.Group.GroupUsers[group.Id]) != 0 {
+			t.Errorf("got %v want no users", client.Group.GroupRuleUsers[rule.Id])
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// Okta group types, see https://developer.okta.com/docs/reference/api/groups/#group-type
+const (
+	GroupTypeOkta    = "OKTA_GROUP"
+	GroupTypeApp     = "APP_GROUP"
+	GroupTypeBuiltIn = "BUILT_IN"
+)
+
+// EveryoneGroupName is the name of the built-in group every user in the org belongs to
+const EveryoneGroupName = "Everyone"
+
+// CreateAppGroup will add an APP_GROUP to the list of groups, like one imported from an app such as
+// Active Directory. Its members can only be changed by the app, so use SetAppGroupUsers to set them
+func (g *GroupResource) CreateAppGroup(ctx context.Context, group okta.Group) (*okta.Group, *okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.validateGroupProfile("", group.Profile); err != nil {
+		return nil, errorResponse(err), err
+	}
+	created := g.addGroup(group, GroupTypeApp)
+	return created, newResponse(http.StatusOK, created), nil
+}
+
+// SetAppGroupUsers replaces the members of an APP_GROUP, simulating the app pushing its memberships to Okta
+func (g *GroupResource) SetAppGroupUsers(groupID string, userIDs []string) error {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	group, err := g.getGroupByID(groupID)
+	if err != nil {
+		return err
+	}
+	if group.Type != GroupTypeApp {
+		return NewError(ErrorCodeUnsupportedOperation, fmt.Sprintf("Group %v is not an %v", groupID, GroupTypeApp))
+	}
+	for _, userID := range userIDs {
+		if _, err := g.Client.User.getUserByID(userID); err != nil {
+			return err
+		}
+	}
+	g.GroupUsers[group.Id] = append([]string{}, userIDs...)
+	membershipUpdated(group)
+	g.applyGroupRules()
+	return nil
+}
+
+// EveryoneGroup returns the built-in group every user belongs to
+func (g *GroupResource) EveryoneGroup() *okta.Group {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	return g.everyoneGroup()
+}
+
+func (g *GroupResource) everyoneGroup() *okta.Group {
+	for _, group := range g.Groups {
+		if group.Type == GroupTypeBuiltIn && group.Profile.Name == EveryoneGroupName {
+			return group
+		}
+	}
+	return nil
+}
+
+// addGroup stores the group with a new ID, the type and its timestamps set
+func (g *GroupResource) addGroup(group okta.Group, groupType string) *okta.Group {
+	now := time.Now().UTC()
+	group.Id = g.Client.ids.NewID(GroupIDPrefix)
+	group.Type = groupType
+	group.Created = &now
+	group.LastUpdated = &now
+	group.LastMembershipUpdated = &now
+	g.Groups = append(g.Groups, &group)
+	return &group
+}
+
+// seedEveryoneGroup adds the built-in Everyone group that every Okta org has
+func (g *GroupResource) seedEveryoneGroup() {
+	group := NewGroup(EveryoneGroupName)
+	group.Profile.Description = "All users in your organization"
+	g.addGroup(*group, GroupTypeBuiltIn)
+}
+
+// checkGroupModifiable returns an error if the group's type means the action can't be done through
+// the API, as only OKTA_GROUP groups can be changed
+func checkGroupModifiable(group *okta.Group, action string) *okta.Error {
+	if group.Type == GroupTypeApp || group.Type == GroupTypeBuiltIn {
+		return NewError(ErrorCodeUnsupportedOperation, fmt.Sprintf("Cannot %v a group of type %v", action, group.Type))
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package mockokta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+func TestGroupResource_GroupTypes(t *testing.T) {
+	t.Run("should seed the built in Everyone group", func(t *testing.T) {
+		client := NewClient()
+
+		got, _, _ := client.ListGroups(context.TODO(), query.NewQueryParams(query.WithFilter(`type eq "BUILT_IN"`)))
+
+		if len(got) != 1 || got[0].Profile.Name != EveryoneGroupName || got[0] != client.Group.EveryoneGroup() {
+			t.Errorf("got %v want the Everyone group", got)
+		}
+	})
+
+	t.Run("should create OKTA_GROUP groups", func(t *testing.T) {
+		client := NewClient()
+		group := NewGroup("TestGroup")
+		group.Type = GroupTypeApp
+
+		got, _, _ := client.CreateGroup(context.TODO(), *group)
+
+		if got.Type != GroupTypeOkta {
+			t.Errorf("got type %v want %v", got.Type, GroupTypeOkta)
+		}
+	})
+
+	t.Run("should add every user to the Everyone group", func(t *testing.T) {
+		client := NewClient()
+		user1, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser1@test.com"), nil)
+		user2, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser2@test.com"), nil)
+
+		got, _, _ := client.ListGroupUsers(context.TODO(), client.Group.EveryoneGroup().Id, nil)
+
+		if len(got) != 2 || got[0] != user1 || got[1] != user2 {
+			t.Errorf("got %v want %v", got, []*okta.User{user1, user2})
+		}
+	})
+
+	t.Run("should remove deleted users from the Everyone group", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+
+		got, _, _ := client.ListGroupUsers(context.TODO(), client.Group.EveryoneGroup().Id, nil)
+
+		if len(got) != 0 {
+			t.Errorf("got %v want no users", got)
+		}
+	})
+
+	t.Run("should not modify the Everyone group", func(t *testing.T) {
+		client := NewClient()
+		everyone := client.Group.EveryoneGroup()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		resp, err := client.RemoveUserFromGroup(context.TODO(), everyone.Id, user.Id)
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+		resp, err = client.AddUserToGroup(context.TODO(), everyone.Id, user.Id)
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+		_, resp, err = client.UpdateGroup(context.TODO(), everyone.Id, *NewGroup("Renamed"))
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+		resp, err = client.DeleteGroup(context.TODO(), everyone.Id)
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+
+		if !client.Group.GroupContainsUser(*everyone, "TestUser@test.com") || everyone.Profile.Name != EveryoneGroupName {
+			t.Errorf("expected Everyone group to be unchanged")
+		}
+	})
+
+	t.Run("should not modify APP_GROUP memberships", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateAppGroup(context.TODO(), *NewGroup("AppGroup"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		resp, err := client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+		resp, err = client.RemoveUserFromGroup(context.TODO(), group.Id, user.Id)
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+		resp, err = client.DeleteGroup(context.TODO(), group.Id)
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+
+		if group.Type != GroupTypeApp || client.Group.GroupContainsUser(*group, "TestUser@test.com") {
+			t.Errorf("got %v want an APP_GROUP without members", group)
+		}
+	})
+
+	t.Run("should not create users in an APP_GROUP", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateAppGroup(context.TODO(), *NewGroup("AppGroup"))
+		request := NewCreateUserRequest("TestUser@test.com")
+		request.GroupIds = []string{group.Id}
+
+		_, resp, err := client.User.CreateUser(context.TODO(), request, nil)
+
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+		if len(client.User.Users) != 0 {
+			t.Errorf("got %v want no users", client.User.Users)
+		}
+	})
+
+	t.Run("should set APP_GROUP members on behalf of the app", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateAppGroup(context.TODO(), *NewGroup("AppGroup"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		if err := client.Group.SetAppGroupUsers(group.Id, []string{user.Id}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		got, _, _ := client.ListGroupUsers(context.TODO(), group.Id, nil)
+
+		if len(got) != 1 || got[0] != user {
+			t.Errorf("got %v want %v", got, user)
+		}
+	})
+
+	t.Run("should only set members of an APP_GROUP", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		if err := client.Group.SetAppGroupUsers(group.Id, nil); err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+}
@@ -0,0 +1,596 @@
+package mockokta
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// route maps a method and /api/v1 path pattern to a handler, where a "*" segment in the pattern
+// matches any value and is passed to the handler in params
+type route struct {
+	method  string
+	pattern string
+	handle  func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string)
+}
+
+// match reports whether the route matches the request and returns the values of its "*" segments
+func (rt route) match(method string, segments []string) ([]string, bool) {
+	pattern := strings.Split(rt.pattern, "/")
+	if method != rt.method || len(pattern) != len(segments) {
+		return nil, false
+	}
+	params := make([]string, 0)
+	for idx, p := range pattern {
+		if p == "*" {
+			params = append(params, segments[idx])
+			continue
+		}
+		if p != segments[idx] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+var routes = []route{
+	{http.MethodGet, "meta/schemas/user/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		schema, resp, err := client.GetUserSchema(r.Context(), params[0])
+		writeJSON(w, schema, resp, err)
+	}},
+	{http.MethodPost, "meta/schemas/user/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body okta.UserSchema
+		if !readJSON(w, r, &body) {
+			return
+		}
+		schema, resp, err := client.UpdateUserProfile(r.Context(), params[0], body)
+		writeJSON(w, schema, resp, err)
+	}},
+	{http.MethodGet, "meta/schemas/group/default", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		schema, resp, err := client.GetGroupSchema(r.Context())
+		writeJSON(w, schema, resp, err)
+	}},
+	{http.MethodPost, "meta/schemas/group/default", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body okta.GroupSchema
+		if !readJSON(w, r, &body) {
+			return
+		}
+		schema, resp, err := client.UpdateGroupSchema(r.Context(), body)
+		writeJSON(w, schema, resp, err)
+	}},
+	{http.MethodGet, "groups/rules", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		rules, resp, err := client.ListGroupRules(r.Context(), qp)
+		writeJSON(w, rules, resp, err)
+	}},
+	{http.MethodPost, "groups/rules", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body okta.GroupRule
+		if !readJSON(w, r, &body) {
+			return
+		}
+		rule, resp, err := client.CreateGroupRule(r.Context(), body)
+		writeJSON(w, rule, resp, err)
+	}},
+	{http.MethodGet, "groups/rules/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		rule, resp, err := client.GetGroupRule(r.Context(), params[0], qp)
+		writeJSON(w, rule, resp, err)
+	}},
+	{http.MethodDelete, "groups/rules/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteGroupRule(r.Context(), params[0], qp)
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "groups/rules/*/lifecycle/activate", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.ActivateGroupRule(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "groups/rules/*/lifecycle/deactivate", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeactivateGroupRule(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "groups", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		groups, resp, err := client.ListGroups(r.Context(), qp)
+		writeJSON(w, groups, resp, err)
+	}},
+	{http.MethodPost, "groups", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var group okta.Group
+		if !readJSON(w, r, &group) {
+			return
+		}
+		created, resp, err := client.CreateGroup(r.Context(), group)
+		writeJSON(w, created, resp, err)
+	}},
+	{http.MethodGet, "groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		group, resp, err := client.GetGroup(r.Context(), params[0])
+		writeJSON(w, group, resp, err)
+	}},
+	{http.MethodPut, "groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body okta.Group
+		if !readJSON(w, r, &body) {
+			return
+		}
+		group, resp, err := client.UpdateGroup(r.Context(), params[0], body)
+		writeJSON(w, group, resp, err)
+	}},
+	{http.MethodDelete, "groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteGroup(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "groups/*/users", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		users, resp, err := client.ListGroupUsers(r.Context(), params[0], qp)
+		writeJSON(w, users, resp, err)
+	}},
+	{http.MethodPut, "groups/*/users/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddUserToGroup(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "groups/*/users/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveUserFromGroup(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "groups/*/roles", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		roles, resp, err := client.ListGroupAssignedRoles(r.Context(), params[0], qp)
+		writeJSON(w, roles, resp, err)
+	}},
+	{http.MethodPost, "groups/*/roles", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var request CustomRoleAssignRequest
+		if !readJSON(w, r, &request) {
+			return
+		}
+		if request.Type == CustomRoleType {
+			role, resp, err := client.AssignCustomRoleToGroup(r.Context(), params[0], request, qp)
+			writeJSON(w, role, resp, err)
+			return
+		}
+		role, resp, err := client.AssignRoleToGroup(r.Context(), params[0], okta.AssignRoleRequest{Type: request.Type}, qp)
+		writeJSON(w, role, resp, err)
+	}},
+	{http.MethodGet, "groups/*/roles/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		role, resp, err := client.GetRole(r.Context(), params[0], params[1])
+		writeJSON(w, role, resp, err)
+	}},
+	{http.MethodDelete, "groups/*/roles/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveRoleFromGroup(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "groups/*/roles/*/targets/groups", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		groups, resp, err := client.ListGroupTargetsForGroupRole(r.Context(), params[0], params[1], qp)
+		writeJSON(w, groups, resp, err)
+	}},
+	{http.MethodPut, "groups/*/roles/*/targets/groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddGroupTargetToGroupAdministratorRoleForGroup(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "groups/*/roles/*/targets/groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "groups/*/roles/*/targets/catalog/apps", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		apps, resp, err := client.ListApplicationTargetsForApplicationAdministratorRoleForGroup(r.Context(), params[0], params[1], qp)
+		writeJSON(w, apps, resp, err)
+	}},
+	{http.MethodPut, "groups/*/roles/*/targets/catalog/apps/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddApplicationTargetToAdminRoleGivenToGroup(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "groups/*/roles/*/targets/catalog/apps/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPut, "groups/*/roles/*/targets/catalog/apps/*/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddApplicationInstanceTargetToAppAdminRoleGivenToGroup(r.Context(), params[0], params[1], params[2], params[3])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "groups/*/roles/*/targets/catalog/apps/*/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveApplicationTargetFromAdministratorRoleGivenToGroup(r.Context(), params[0], params[1], params[2], params[3])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "users", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		users, resp, err := client.ListUsers(r.Context(), qp)
+		writeJSON(w, users, resp, err)
+	}},
+	{http.MethodPost, "users", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var request okta.CreateUserRequest
+		if !readJSON(w, r, &request) {
+			return
+		}
+		user, resp, err := client.CreateUser(r.Context(), request, qp)
+		writeJSON(w, user, resp, err)
+	}},
+	{http.MethodGet, "users/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		user, resp, err := client.GetUser(r.Context(), params[0])
+		writeJSON(w, user, resp, err)
+	}},
+	{http.MethodPut, "users/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body okta.User
+		if !readJSON(w, r, &body) {
+			return
+		}
+		user, resp, err := client.UpdateUser(r.Context(), params[0], body, qp)
+		writeJSON(w, user, resp, err)
+	}},
+	{http.MethodPost, "users/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body okta.User
+		if !readJSON(w, r, &body) {
+			return
+		}
+		user, resp, err := client.PartialUpdateUser(r.Context(), params[0], body, qp)
+		writeJSON(w, user, resp, err)
+	}},
+	{http.MethodDelete, "users/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeactivateOrDeleteUser(r.Context(), params[0], qp)
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "users/*/lifecycle/activate", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		token, resp, err := client.ActivateUser(r.Context(), params[0], qp)
+		writeJSON(w, token, resp, err)
+	}},
+	{http.MethodPost, "users/*/lifecycle/deactivate", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeactivateUser(r.Context(), params[0], qp)
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "users/*/lifecycle/suspend", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.SuspendUser(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "users/*/lifecycle/unsuspend", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.UnsuspendUser(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "users/*/lifecycle/unlock", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.UnlockUser(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "users/*/lifecycle/reset_password", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		token, resp, err := client.ResetPassword(r.Context(), params[0], qp)
+		writeJSON(w, token, resp, err)
+	}},
+	{http.MethodPost, "users/*/lifecycle/expire_password", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		user, resp, err := client.ExpirePassword(r.Context(), params[0])
+		writeJSON(w, user, resp, err)
+	}},
+	{http.MethodGet, "users/*/roles", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		roles, resp, err := client.ListAssignedRolesForUser(r.Context(), params[0], qp)
+		writeJSON(w, roles, resp, err)
+	}},
+	{http.MethodPost, "users/*/roles", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var request CustomRoleAssignRequest
+		if !readJSON(w, r, &request) {
+			return
+		}
+		if request.Type == CustomRoleType {
+			role, resp, err := client.AssignCustomRoleToUser(r.Context(), params[0], request, qp)
+			writeJSON(w, role, resp, err)
+			return
+		}
+		role, resp, err := client.AssignRoleToUser(r.Context(), params[0], okta.AssignRoleRequest{Type: request.Type}, qp)
+		writeJSON(w, role, resp, err)
+	}},
+	{http.MethodGet, "users/*/roles/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		role, resp, err := client.GetUserRole(r.Context(), params[0], params[1])
+		writeJSON(w, role, resp, err)
+	}},
+	{http.MethodDelete, "users/*/roles/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveRoleFromUser(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "users/*/roles/*/targets/groups", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		groups, resp, err := client.ListGroupTargetsForRole(r.Context(), params[0], params[1], qp)
+		writeJSON(w, groups, resp, err)
+	}},
+	{http.MethodPut, "users/*/roles/*/targets/groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddGroupTargetToRole(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "users/*/roles/*/targets/groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveGroupTargetFromRole(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "users/*/roles/*/targets/catalog/apps", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		apps, resp, err := client.ListApplicationTargetsForApplicationAdministratorRoleForUser(r.Context(), params[0], params[1], qp)
+		writeJSON(w, apps, resp, err)
+	}},
+	{http.MethodPut, "users/*/roles/*/targets/catalog/apps", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddAllAppsAsTargetToRole(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPut, "users/*/roles/*/targets/catalog/apps/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddApplicationTargetToAdminRoleForUser(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "users/*/roles/*/targets/catalog/apps/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveApplicationTargetFromApplicationAdministratorRoleForUser(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPut, "users/*/roles/*/targets/catalog/apps/*/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddApplicationTargetToAppAdminRoleForUser(r.Context(), params[0], params[1], params[2], params[3])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "users/*/roles/*/targets/catalog/apps/*/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveApplicationTargetFromAdministratorRoleForUser(r.Context(), params[0], params[1], params[2], params[3])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "apps", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		apps, resp, err := client.ListApplications(r.Context(), qp)
+		writeJSON(w, apps, resp, err)
+	}},
+	{http.MethodPost, "apps", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body json.RawMessage
+		if !readJSON(w, r, &body) {
+			return
+		}
+		app, decodeErr := decodeApp(body)
+		if decodeErr != nil {
+			writeJSON(w, nil, errorResponse(decodeErr), decodeErr)
+			return
+		}
+		created, resp, err := client.CreateApplication(r.Context(), app, qp)
+		writeJSON(w, created, resp, err)
+	}},
+	{http.MethodGet, "apps/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		app, resp, err := client.GetApplication(r.Context(), params[0], nil, qp)
+		writeJSON(w, app, resp, err)
+	}},
+	{http.MethodDelete, "apps/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteApplication(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "apps/*/lifecycle/activate", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.ActivateApplication(r.Context(), params[0])
+		writeJSON(w, struct{}{}, resp, err)
+	}},
+	{http.MethodPost, "apps/*/lifecycle/deactivate", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeactivateApplication(r.Context(), params[0])
+		writeJSON(w, struct{}{}, resp, err)
+	}},
+	{http.MethodGet, "apps/*/users", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		appUsers, resp, err := client.ListApplicationUsers(r.Context(), params[0], qp)
+		writeJSON(w, appUsers, resp, err)
+	}},
+	{http.MethodPost, "apps/*/users", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body okta.AppUser
+		if !readJSON(w, r, &body) {
+			return
+		}
+		appUser, resp, err := client.AssignUserToApplication(r.Context(), params[0], body)
+		writeJSON(w, appUser, resp, err)
+	}},
+	{http.MethodGet, "apps/*/users/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		appUser, resp, err := client.GetApplicationUser(r.Context(), params[0], params[1], qp)
+		writeJSON(w, appUser, resp, err)
+	}},
+	{http.MethodDelete, "apps/*/users/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteApplicationUser(r.Context(), params[0], params[1], qp)
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "apps/*/groups", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		assignments, resp, err := client.ListApplicationGroupAssignments(r.Context(), params[0], qp)
+		writeJSON(w, assignments, resp, err)
+	}},
+	{http.MethodGet, "apps/*/groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		assignment, resp, err := client.GetApplicationGroupAssignment(r.Context(), params[0], params[1], qp)
+		writeJSON(w, assignment, resp, err)
+	}},
+	{http.MethodPut, "apps/*/groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body okta.ApplicationGroupAssignment
+		if !readJSON(w, r, &body) {
+			return
+		}
+		assignment, resp, err := client.CreateApplicationGroupAssignment(r.Context(), params[0], params[1], body)
+		writeJSON(w, assignment, resp, err)
+	}},
+	{http.MethodDelete, "apps/*/groups/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteApplicationGroupAssignment(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "iam/roles", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		roles, resp, err := client.ListCustomRoles(r.Context(), qp)
+		writeJSON(w, roles, resp, err)
+	}},
+	{http.MethodPost, "iam/roles", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body CustomRole
+		if !readJSON(w, r, &body) {
+			return
+		}
+		role, resp, err := client.CreateCustomRole(r.Context(), body)
+		writeJSON(w, role, resp, err)
+	}},
+	{http.MethodGet, "iam/roles/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		role, resp, err := client.GetCustomRole(r.Context(), params[0])
+		writeJSON(w, role, resp, err)
+	}},
+	{http.MethodPut, "iam/roles/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body CustomRole
+		if !readJSON(w, r, &body) {
+			return
+		}
+		role, resp, err := client.UpdateCustomRole(r.Context(), params[0], body)
+		writeJSON(w, role, resp, err)
+	}},
+	{http.MethodDelete, "iam/roles/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteCustomRole(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodPost, "iam/roles/*/permissions/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.AddCustomRolePermission(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "iam/roles/*/permissions/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.RemoveCustomRolePermission(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "iam/resource-sets", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resourceSets, resp, err := client.ListResourceSets(r.Context(), qp)
+		writeJSON(w, resourceSets, resp, err)
+	}},
+	{http.MethodPost, "iam/resource-sets", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body CreateResourceSetRequest
+		if !readJSON(w, r, &body) {
+			return
+		}
+		resourceSet, resp, err := client.CreateResourceSet(r.Context(), body)
+		writeJSON(w, resourceSet, resp, err)
+	}},
+	{http.MethodGet, "iam/resource-sets/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resourceSet, resp, err := client.GetResourceSet(r.Context(), params[0])
+		writeJSON(w, resourceSet, resp, err)
+	}},
+	{http.MethodPut, "iam/resource-sets/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body ResourceSet
+		if !readJSON(w, r, &body) {
+			return
+		}
+		resourceSet, resp, err := client.UpdateResourceSet(r.Context(), params[0], body)
+		writeJSON(w, resourceSet, resp, err)
+	}},
+	{http.MethodDelete, "iam/resource-sets/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteResourceSet(r.Context(), params[0])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "iam/resource-sets/*/resources", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resources, resp, err := client.ListResourceSetResources(r.Context(), params[0], qp)
+		writeJSON(w, resources, resp, err)
+	}},
+	{http.MethodPatch, "iam/resource-sets/*/resources", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body struct {
+			Additions []string `json:"additions"`
+		}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		resp, err := client.AddResourceSetResources(r.Context(), params[0], body.Additions)
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "iam/resource-sets/*/resources/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteResourceSetResource(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "iam/resource-sets/*/bindings", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		bindings, resp, err := client.ListResourceSetBindings(r.Context(), params[0], qp)
+		writeJSON(w, bindings, resp, err)
+	}},
+	{http.MethodPost, "iam/resource-sets/*/bindings", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body RoleBindingRequest
+		if !readJSON(w, r, &body) {
+			return
+		}
+		binding, resp, err := client.CreateResourceSetBinding(r.Context(), params[0], body)
+		writeJSON(w, binding, resp, err)
+	}},
+	{http.MethodDelete, "iam/resource-sets/*/bindings/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteResourceSetBinding(r.Context(), params[0], params[1])
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodGet, "iam/resource-sets/*/bindings/*/members", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		members, resp, err := client.ListResourceSetBindingMembers(r.Context(), params[0], params[1], qp)
+		writeJSON(w, members, resp, err)
+	}},
+	{http.MethodPatch, "iam/resource-sets/*/bindings/*/members", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		var body struct {
+			Additions []string `json:"additions"`
+		}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		resp, err := client.AddResourceSetBindingMembers(r.Context(), params[0], params[1], body.Additions)
+		writeJSON(w, nil, resp, err)
+	}},
+	{http.MethodDelete, "iam/resource-sets/*/bindings/*/members/*", func(client *MockClient, w http.ResponseWriter, r *http.Request, qp *query.Params, params []string) {
+		resp, err := client.DeleteResourceSetBindingMember(r.Context(), params[0], params[1], params[2])
+		writeJSON(w, nil, resp, err)
+	}},
+}
+
+// handler returns an http.Handler serving the Okta API from the mock's state
+func (client *MockClient) handler() http.Handler {
+	return http.HandlerFunc(client.serveHTTP)
+}
+
+func (client *MockClient) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Host != "" && !strings.HasSuffix(orgURL, "//"+r.Host) {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		w = &linkRewriter{ResponseWriter: w, baseURL: scheme + "://" + r.Host}
+	}
+	qp := queryParamsFromURL(r.URL.Query())
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/"), "/")
+
+	pathMatched := false
+	for _, rt := range routes {
+		params, ok := rt.match(r.Method, segments)
+		if ok {
+			rt.handle(client, w, r, qp, params)
+			return
+		}
+		if _, ok := rt.match(rt.method, segments); ok {
+			pathMatched = true
+		}
+	}
+	if pathMatched {
+		err := NewError(ErrorCodeMethodNotAllowed)
+		writeJSON(w, nil, errorResponse(err), err)
+		return
+	}
+	err := NewError(ErrorCodePathNotFound)
+	writeJSON(w, nil, errorResponse(err), err)
+}
+
+// linkRewriter points the Link headers of a response at the server the request was sent to
+// rather than the mock's org URL
+type linkRewriter struct {
+	http.ResponseWriter
+	baseURL string
+}
+
+func (lr *linkRewriter) WriteHeader(status int) {
+	links := lr.Header().Values("Link")
+	lr.Header().Del("Link")
+	for _, link := range links {
+		lr.Header().Add("Link", strings.Replace(link, orgURL, lr.baseURL, 1))
+	}
+	lr.ResponseWriter.WriteHeader(status)
+}
+
+// readJSON decodes the request body into v, writing an E0000003 error and returning false if it is malformed
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		err := NewError(ErrorCodeMalformedRequest, err.Error())
+		writeJSON(w, nil, errorResponse(err), err)
+		return false
+	}
+	return true
+}
+
+// writeJSON writes the result of a mock call as an Okta API response, copying the status and
+// Link headers from the *okta.Response so clients can keep paginating, and writing err as the
+// body in place of v if the call failed
+func writeJSON(w http.ResponseWriter, v interface{}, resp *okta.Response, err error) {
+	if err != nil {
+		var oktaErr *okta.Error
+		if !errors.As(err, &oktaErr) {
+			oktaErr = NewError(ErrorCodeInternalError)
+		}
+		if resp == nil || resp.Response == nil {
+			resp = errorResponse(oktaErr)
+		}
+		v = oktaErr
+	}
+	status := http.StatusOK
+	if resp != nil && resp.Response != nil {
+		status = resp.StatusCode
+		for _, name := range append([]string{"Link", "Date"}, rateLimitHeaders...) {
+			for _, value := range resp.Header.Values(name) {
+				w.Header().Add(name, value)
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil && status != http.StatusNoContent {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
@@ -0,0 +1,1055 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// CustomRoleType is the role type of assignments of custom roles
+const CustomRoleType = "CUSTOM"
+
+// customRolePermissions are the permissions a custom role can grant, see
+// https://developer.okta.com/docs/concepts/role-assignment/#permissions
+var customRolePermissions = []string{
+	"okta.users.manage", "okta.users.create", "okta.users.read", "okta.users.credentials.manage",
+	"okta.users.credentials.resetFactors", "okta.users.credentials.resetPassword", "okta.users.credentials.expirePassword",
+	"okta.users.userprofile.manage", "okta.users.lifecycle.manage", "okta.users.lifecycle.activate",
+	"okta.users.lifecycle.deactivate", "okta.users.lifecycle.suspend", "okta.users.lifecycle.unsuspend",
+	"okta.users.lifecycle.delete", "okta.users.lifecycle.unlock", "okta.users.lifecycle.clearSessions",
+	"okta.users.groupMembership.manage", "okta.users.appAssignment.manage",
+	"okta.groups.manage", "okta.groups.create", "okta.groups.read", "okta.groups.members.manage", "okta.groups.appAssignment.manage",
+	"okta.apps.read", "okta.apps.manage", "okta.apps.assignment.manage",
+}
+
+// IAMResource contains the custom admin roles and resource sets of the org. Assignments maps the IDs
+// of CUSTOM role assignments to the custom role and resource set they bind, and Resources maps
+// Resource Set IDs to the resources in them
+type IAMResource struct {
+	Client       *MockClient
+	Roles        []*CustomRole
+	ResourceSets []*ResourceSet
+	Resources    map[string][]*ResourceSetResource
+	Assignments  map[string]CustomRoleAssignment
+}
+
+// CustomRole is an admin role made of the permissions it grants. The okta sdk doesn't have the
+// IAM APIs, so the mock defines its own types for them matching the Okta API's JSON
+type CustomRole struct {
+	Id          string     `json:"id,omitempty"`
+	Label       string     `json:"label,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Permissions []string   `json:"permissions,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+}
+
+// ResourceSet is a set of users, groups and apps a custom role can be granted over
+type ResourceSet struct {
+	Id          string     `json:"id,omitempty"`
+	Label       string     `json:"label,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+}
+
+// CreateResourceSetRequest creates a resource set with its initial resources
+type CreateResourceSetRequest struct {
+	Label       string   `json:"label,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Resources   []string `json:"resources,omitempty"`
+}
+
+// ResourceSetResource is a resource in a resource set. Href is the REST URL of a user, group or app,
+// of the users in a group, or of all users, groups or apps, e.g. https://.../api/v1/groups/00g1/users
+type ResourceSetResource struct {
+	Id      string     `json:"id,omitempty"`
+	Href    string     `json:"href,omitempty"`
+	Created *time.Time `json:"created,omitempty"`
+}
+
+// RoleBinding is the binding of the custom role with the Id to a resource set
+type RoleBinding struct {
+	Id string `json:"id,omitempty"`
+}
+
+// RoleBindingRequest binds the custom role Role to a resource set for the Members, which are IDs
+// or hrefs of users and groups
+type RoleBindingRequest struct {
+	Role    string   `json:"role,omitempty"`
+	Members []string `json:"members,omitempty"`
+}
+
+// RoleBindingMember is a user or group a role binding is assigned to. Its Id is the ID of the role
+// assignment and Href the REST URL of the user or group
+type RoleBindingMember struct {
+	Id   string `json:"id,omitempty"`
+	Href string `json:"href,omitempty"`
+}
+
+// CustomRoleAssignRequest assigns the custom role Role over the ResourceSet. It has the role and
+// resource-set fields Okta's role assignment API takes with type CUSTOM, which okta.AssignRoleRequest lacks
+type CustomRoleAssignRequest struct {
+	Type        string `json:"type,omitempty"`
+	Role        string `json:"role,omitempty"`
+	ResourceSet string `json:"resource-set,omitempty"`
+}
+
+// CustomRoleAssignment is the custom role and resource set a CUSTOM role assignment binds
+type CustomRoleAssignment struct {
+	Role        string
+	ResourceSet string
+}
+
+// NewCustomRole creates a CustomRole with the label granting the permissions
+func NewCustomRole(label string, permissions ...string) CustomRole {
+	return CustomRole{
+		Label:       label,
+		Description: label,
+		Permissions: permissions,
+	}
+}
+
+// NewCreateResourceSetRequest creates a CreateResourceSetRequest with the label and resources
+func NewCreateResourceSetRequest(label string, resources ...string) CreateResourceSetRequest {
+	return CreateResourceSetRequest{
+		Label:       label,
+		Description: label,
+		Resources:   resources,
+	}
+}
+
+// customRoleAssignmentKey is the context key of the CustomRoleAssignRequest set by WithCustomRoleAssignment
+type customRoleAssignmentKey struct{}
+
+// WithCustomRoleAssignment returns a copy of the ctx carrying the custom role and resource set that
+// AssignRoleToGroup and AssignRoleToUser assign when the type of their okta.AssignRoleRequest is CUSTOM
+func WithCustomRoleAssignment(ctx context.Context, roleIDOrLabel string, resourceSetID string) context.Context {
+	return context.WithValue(ctx, customRoleAssignmentKey{}, NewCustomRoleAssignRequest(roleIDOrLabel, resourceSetID))
+}
+
+// customRoleAssignment returns the CustomRoleAssignRequest set on the ctx by WithCustomRoleAssignment
+func customRoleAssignment(ctx context.Context) (CustomRoleAssignRequest, bool) {
+	if ctx == nil {
+		return CustomRoleAssignRequest{}, false
+	}
+	request, ok := ctx.Value(customRoleAssignmentKey{}).(CustomRoleAssignRequest)
+	return request, ok
+}
+
+// NewCustomRoleAssignRequest creates a CustomRoleAssignRequest for the custom role over the resource set
+func NewCustomRoleAssignRequest(roleID string, resourceSetID string) CustomRoleAssignRequest {
+	return CustomRoleAssignRequest{
+		Type:        CustomRoleType,
+		Role:        roleID,
+		ResourceSet: resourceSetID,
+	}
+}
+
+// ResourceHref returns the REST URL of the resource at the path relative to /api/v1, e.g. groups/00g1/users
+func ResourceHref(path string) string {
+	return fmt.Sprintf("%s/api/v1/%s", orgURL, strings.Trim(path, "/"))
+}
+
+// CreateCustomRole is a wrapper to call client.IAM.CreateCustomRole to make it easier to match an interface for the okta client
+func (client *MockClient) CreateCustomRole(ctx context.Context, role CustomRole) (*CustomRole, *okta.Response, error) {
+	return intercept(ctx, client, "CreateCustomRole", []interface{}{role}, func() (*CustomRole, *okta.Response, error) {
+		return client.IAM.CreateCustomRole(ctx, role)
+	})
+}
+
+// GetCustomRole is a wrapper to call client.IAM.GetCustomRole to make it easier to match an interface for the okta client
+func (client *MockClient) GetCustomRole(ctx context.Context, roleIDOrLabel string) (*CustomRole, *okta.Response, error) {
+	return intercept(ctx, client, "GetCustomRole", []interface{}{roleIDOrLabel}, func() (*CustomRole, *okta.Response, error) {
+		return client.IAM.GetCustomRole(ctx, roleIDOrLabel)
+	})
+}
+
+// UpdateCustomRole is a wrapper to call client.IAM.UpdateCustomRole to make it easier to match an interface for the okta client
+func (client *MockClient) UpdateCustomRole(ctx context.Context, roleIDOrLabel string, role CustomRole) (*CustomRole, *okta.Response, error) {
+	return intercept(ctx, client, "UpdateCustomRole", []interface{}{roleIDOrLabel, role}, func() (*CustomRole, *okta.Response, error) {
+		return client.IAM.UpdateCustomRole(ctx, roleIDOrLabel, role)
+	})
+}
+
+// DeleteCustomRole is a wrapper to call client.IAM.DeleteCustomRole to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteCustomRole(ctx context.Context, roleIDOrLabel string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteCustomRole", []interface{}{roleIDOrLabel}, func() (*okta.Response, error) {
+		return client.IAM.DeleteCustomRole(ctx, roleIDOrLabel)
+	})
+}
+
+// ListCustomRoles is a wrapper to call client.IAM.ListCustomRoles to make it easier to match an interface for the okta client
+func (client *MockClient) ListCustomRoles(ctx context.Context, qp *query.Params) ([]*CustomRole, *okta.Response, error) {
+	return intercept(ctx, client, "ListCustomRoles", []interface{}{qp}, func() ([]*CustomRole, *okta.Response, error) {
+		return client.IAM.ListCustomRoles(ctx, qp)
+	})
+}
+
+// AddCustomRolePermission is a wrapper to call client.IAM.AddCustomRolePermission to make it easier to match an interface for the okta client
+func (client *MockClient) AddCustomRolePermission(ctx context.Context, roleIDOrLabel string, permission string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddCustomRolePermission", []interface{}{roleIDOrLabel, permission}, func() (*okta.Response, error) {
+		return client.IAM.AddCustomRolePermission(ctx, roleIDOrLabel, permission)
+	})
+}
+
+// RemoveCustomRolePermission is a wrapper to call client.IAM.RemoveCustomRolePermission to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveCustomRolePermission(ctx context.Context, roleIDOrLabel string, permission string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveCustomRolePermission", []interface{}{roleIDOrLabel, permission}, func() (*okta.Response, error) {
+		return client.IAM.RemoveCustomRolePermission(ctx, roleIDOrLabel, permission)
+	})
+}
+
+// CreateResourceSet is a wrapper to call client.IAM.CreateResourceSet to make it easier to match an interface for the okta client
+func (client *MockClient) CreateResourceSet(ctx context.Context, request CreateResourceSetRequest) (*ResourceSet, *okta.Response, error) {
+	return intercept(ctx, client, "CreateResourceSet", []interface{}{request}, func() (*ResourceSet, *okta.Response, error) {
+		return client.IAM.CreateResourceSet(ctx, request)
+	})
+}
+
+// GetResourceSet is a wrapper to call client.IAM.GetResourceSet to make it easier to match an interface for the okta client
+func (client *MockClient) GetResourceSet(ctx context.Context, resourceSetID string) (*ResourceSet, *okta.Response, error) {
+	return intercept(ctx, client, "GetResourceSet", []interface{}{resourceSetID}, func() (*ResourceSet, *okta.Response, error) {
+		return client.IAM.GetResourceSet(ctx, resourceSetID)
+	})
+}
+
+// UpdateResourceSet is a wrapper to call client.IAM.UpdateResourceSet to make it easier to match an interface for the okta client
+func (client *MockClient) UpdateResourceSet(ctx context.Context, resourceSetID string, resourceSet ResourceSet) (*ResourceSet, *okta.Response, error) {
+	return intercept(ctx, client, "UpdateResourceSet", []interface{}{resourceSetID, resourceSet}, func() (*ResourceSet, *okta.Response, error) {
+		return client.IAM.UpdateResourceSet(ctx, resourceSetID, resourceSet)
+	})
+}
+
+// DeleteResourceSet is a wrapper to call client.IAM.DeleteResourceSet to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteResourceSet(ctx context.Context, resourceSetID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteResourceSet", []interface{}{resourceSetID}, func() (*okta.Response, error) {
+		return client.IAM.DeleteResourceSet(ctx, resourceSetID)
+	})
+}
+
+// ListResourceSets is a wrapper to call client.IAM.ListResourceSets to make it easier to match an interface for the okta client
+func (client *MockClient) ListResourceSets(ctx context.Context, qp *query.Params) ([]*ResourceSet, *okta.Response, error) {
+	return intercept(ctx, client, "ListResourceSets", []interface{}{qp}, func() ([]*ResourceSet, *okta.Response, error) {
+		return client.IAM.ListResourceSets(ctx, qp)
+	})
+}
+
+// ListResourceSetResources is a wrapper to call client.IAM.ListResourceSetResources to make it easier to match an interface for the okta client
+func (client *MockClient) ListResourceSetResources(ctx context.Context, resourceSetID string, qp *query.Params) ([]*ResourceSetResource, *okta.Response, error) {
+	return intercept(ctx, client, "ListResourceSetResources", []interface{}{resourceSetID, qp}, func() ([]*ResourceSetResource, *okta.Response, error) {
+		return client.IAM.ListResourceSetResources(ctx, resourceSetID, qp)
+	})
+}
+
+// AddResourceSetResources is a wrapper to call client.IAM.AddResourceSetResources to make it easier to match an interface for the okta client
+func (client *MockClient) AddResourceSetResources(ctx context.Context, resourceSetID string, resources []string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddResourceSetResources", []interface{}{resourceSetID, resources}, func() (*okta.Response, error) {
+		return client.IAM.AddResourceSetResources(ctx, resourceSetID, resources)
+	})
+}
+
+// DeleteResourceSetResource is a wrapper to call client.IAM.DeleteResourceSetResource to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteResourceSetResource(ctx context.Context, resourceSetID string, resourceID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteResourceSetResource", []interface{}{resourceSetID, resourceID}, func() (*okta.Response, error) {
+		return client.IAM.DeleteResourceSetResource(ctx, resourceSetID, resourceID)
+	})
+}
+
+// CreateResourceSetBinding is a wrapper to call client.IAM.CreateResourceSetBinding to make it easier to match an interface for the okta client
+func (client *MockClient) CreateResourceSetBinding(ctx context.Context, resourceSetID string, request RoleBindingRequest) (*RoleBinding, *okta.Response, error) {
+	return intercept(ctx, client, "CreateResourceSetBinding", []interface{}{resourceSetID, request}, func() (*RoleBinding, *okta.Response, error) {
+		return client.IAM.CreateResourceSetBinding(ctx, resourceSetID, request)
+	})
+}
+
+// ListResourceSetBindings is a wrapper to call client.IAM.ListResourceSetBindings to make it easier to match an interface for the okta client
+func (client *MockClient) ListResourceSetBindings(ctx context.Context, resourceSetID string, qp *query.Params) ([]*RoleBinding, *okta.Response, error) {
+	return intercept(ctx, client, "ListResourceSetBindings", []interface{}{resourceSetID, qp}, func() ([]*RoleBinding, *okta.Response, error) {
+		return client.IAM.ListResourceSetBindings(ctx, resourceSetID, qp)
+	})
+}
+
+// DeleteResourceSetBinding is a wrapper to call client.IAM.DeleteResourceSetBinding to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteResourceSetBinding(ctx context.Context, resourceSetID string, roleIDOrLabel string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteResourceSetBinding", []interface{}{resourceSetID, roleIDOrLabel}, func() (*okta.Response, error) {
+		return client.IAM.DeleteResourceSetBinding(ctx, resourceSetID, roleIDOrLabel)
+	})
+}
+
+// ListResourceSetBindingMembers is a wrapper to call client.IAM.ListResourceSetBindingMembers to make it easier to match an interface for the okta client
+func (client *MockClient) ListResourceSetBindingMembers(ctx context.Context, resourceSetID string, roleIDOrLabel string, qp *query.Params) ([]*RoleBindingMember, *okta.Response, error) {
+	return intercept(ctx, client, "ListResourceSetBindingMembers", []interface{}{resourceSetID, roleIDOrLabel, qp}, func() ([]*RoleBindingMember, *okta.Response, error) {
+		return client.IAM.ListResourceSetBindingMembers(ctx, resourceSetID, roleIDOrLabel, qp)
+	})
+}
+
+// AddResourceSetBindingMembers is a wrapper to call client.IAM.AddResourceSetBindingMembers to make it easier to match an interface for the okta client
+func (client *MockClient) AddResourceSetBindingMembers(ctx context.Context, resourceSetID string, roleIDOrLabel string, members []string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddResourceSetBindingMembers", []interface{}{resourceSetID, roleIDOrLabel, members}, func() (*okta.Response, error) {
+		return client.IAM.AddResourceSetBindingMembers(ctx, resourceSetID, roleIDOrLabel, members)
+	})
+}
+
+// DeleteResourceSetBindingMember is a wrapper to call client.IAM.DeleteResourceSetBindingMember to make it easier to match an interface for the okta client
+func (client *MockClient) DeleteResourceSetBindingMember(ctx context.Context, resourceSetID string, roleIDOrLabel string, memberID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeleteResourceSetBindingMember", []interface{}{resourceSetID, roleIDOrLabel, memberID}, func() (*okta.Response, error) {
+		return client.IAM.DeleteResourceSetBindingMember(ctx, resourceSetID, roleIDOrLabel, memberID)
+	})
+}
+
+// AssignCustomRoleToGroup is a wrapper to call client.Group.AssignCustomRoleToGroup to make it easier to match an interface for the okta client
+func (client *MockClient) AssignCustomRoleToGroup(ctx context.Context, groupID string, assignRoleRequest CustomRoleAssignRequest, qp *query.Params) (*okta.Role, *okta.Response, error) {
+	return intercept(ctx, client, "AssignCustomRoleToGroup", []interface{}{groupID, assignRoleRequest, qp}, func() (*okta.Role, *okta.Response, error) {
+		return client.Group.AssignCustomRoleToGroup(ctx, groupID, assignRoleRequest, qp)
+	})
+}
+
+// AssignCustomRoleToUser is a wrapper to call client.User.AssignCustomRoleToUser to make it easier to match an interface for the okta client
+func (client *MockClient) AssignCustomRoleToUser(ctx context.Context, userID string, assignRoleRequest CustomRoleAssignRequest, qp *query.Params) (*okta.Role, *okta.Response, error) {
+	return intercept(ctx, client, "AssignCustomRoleToUser", []interface{}{userID, assignRoleRequest, qp}, func() (*okta.Role, *okta.Response, error) {
+		return client.User.AssignCustomRoleToUser(ctx, userID, assignRoleRequest, qp)
+	})
+}
+
+// CreateCustomRole creates a custom role granting its permissions, which must be valid Okta permissions
+func (i *IAMResource) CreateCustomRole(ctx context.Context, role CustomRole) (*CustomRole, *okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if role.Label == "" {
+		err := newValidationError("label", "The field cannot be left blank")
+		return nil, errorResponse(err), err
+	}
+	if _, err := i.getCustomRole(role.Label); err == nil {
+		err := newValidationError("label", "A custom role with this label already exists")
+		return nil, errorResponse(err), err
+	}
+	if len(role.Permissions) == 0 {
+		err := newValidationError("permissions", "A custom role needs at least one permission")
+		return nil, errorResponse(err), err
+	}
+	for _, permission := range role.Permissions {
+		if err := checkPermission(permission); err != nil {
+			return nil, errorResponse(err), err
+		}
+	}
+	now := time.Now().UTC()
+	created := &CustomRole{
+		Id:          i.Client.ids.NewID(CustomRoleIDPrefix),
+		Label:       role.Label,
+		Description: role.Description,
+		Permissions: append([]string{}, role.Permissions...),
+		Created:     &now,
+		LastUpdated: &now,
+	}
+	i.Roles = append(i.Roles, created)
+	return created, newResponse(http.StatusOK, created), nil
+}
+
+// GetCustomRole returns the custom role with the ID or label
+func (i *IAMResource) GetCustomRole(ctx context.Context, roleIDOrLabel string) (*CustomRole, *okta.Response, error) {
+	i.Client.mu.RLock()
+	defer i.Client.mu.RUnlock()
+
+	role, err := i.getCustomRole(roleIDOrLabel)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return role, newResponse(http.StatusOK, role), nil
+}
+
+func (i *IAMResource) getCustomRole(roleIDOrLabel string) (*CustomRole, *okta.Error) {
+	for _, role := range i.Roles {
+		if role.Id == roleIDOrLabel || role.Label == roleIDOrLabel {
+			return role, nil
+		}
+	}
+	return nil, newNotFoundError("CustomRole", roleIDOrLabel)
+}
+
+// UpdateCustomRole replaces the label and description of the custom role. Its permissions are changed
+// with AddCustomRolePermission and RemoveCustomRolePermission
+func (i *IAMResource) UpdateCustomRole(ctx context.Context, roleIDOrLabel string, role CustomRole) (*CustomRole, *okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	existing, err := i.getCustomRole(roleIDOrLabel)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	if role.Label == "" {
+		err := newValidationError("label", "The field cannot be left blank")
+		return nil, errorResponse(err), err
+	}
+	if other, err := i.getCustomRole(role.Label); err == nil && other != existing {
+		err := newValidationError("label", "A custom role with this label already exists")
+		return nil, errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	existing.Label = role.Label
+	existing.Description = role.Description
+	existing.LastUpdated = &now
+	return existing, newResponse(http.StatusOK, existing), nil
+}
+
+// DeleteCustomRole deletes a custom role that isn't bound to any resource set
+func (i *IAMResource) DeleteCustomRole(ctx context.Context, roleIDOrLabel string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := i.getCustomRole(roleIDOrLabel)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	for _, assignment := range i.activeAssignments() {
+		if assignment.Role == role.Id {
+			err := NewError(ErrorCodeUnsupportedOperation, "The custom role is still bound to a resource set.")
+			return errorResponse(err), err
+		}
+	}
+	for idx, r := range i.Roles {
+		if r == role {
+			i.Roles = append(i.Roles[:idx], i.Roles[idx+1:]...)
+			break
+		}
+	}
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// ListCustomRoles returns a page of the custom roles
+func (i *IAMResource) ListCustomRoles(ctx context.Context, qp *query.Params) ([]*CustomRole, *okta.Response, error) {
+	i.Client.mu.RLock()
+	defer i.Client.mu.RUnlock()
+
+	roles, after := paginate(i.Roles, func(role *CustomRole) string { return role.Id }, qp)
+	return roles, i.Client.listResponse(ctx, "/api/v1/iam/roles", qp, after), nil
+}
+
+// AddCustomRolePermission grants the permission with the custom role, doing nothing if it already does
+func (i *IAMResource) AddCustomRolePermission(ctx context.Context, roleIDOrLabel string, permission string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := i.getCustomRole(roleIDOrLabel)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if err := checkPermission(permission); err != nil {
+		return errorResponse(err), err
+	}
+	if !SliceContainsString(role.Permissions, permission) {
+		now := time.Now().UTC()
+		role.Permissions = append(role.Permissions, permission)
+		role.LastUpdated = &now
+	}
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// RemoveCustomRolePermission stops the custom role granting the permission. A custom role needs at
+// least one permission, so its last one can't be removed
+func (i *IAMResource) RemoveCustomRolePermission(ctx context.Context, roleIDOrLabel string, permission string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := i.getCustomRole(roleIDOrLabel)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if !SliceContainsString(role.Permissions, permission) {
+		err := newNotFoundError("Permission", permission)
+		return errorResponse(err), err
+	}
+	if len(role.Permissions) == 1 {
+		err := NewError(ErrorCodeUnsupportedOperation, "A custom role needs at least one permission.")
+		return errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	role.Permissions = removeString(role.Permissions, permission)
+	role.LastUpdated = &now
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// checkPermission returns a validation error if the permission isn't one custom roles can grant
+func checkPermission(permission string) *okta.Error {
+	if !SliceContainsString(customRolePermissions, permission) {
+		return newValidationError("permissions", fmt.Sprintf("Invalid permission %v", permission))
+	}
+	return nil
+}
+
+// CreateResourceSet creates a resource set of the resources in the request, which needs at least one
+func (i *IAMResource) CreateResourceSet(ctx context.Context, request CreateResourceSetRequest) (*ResourceSet, *okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if request.Label == "" {
+		err := newValidationError("label", "The field cannot be left blank")
+		return nil, errorResponse(err), err
+	}
+	if _, err := i.getResourceSet(request.Label); err == nil {
+		err := newValidationError("label", "A resource set with this label already exists")
+		return nil, errorResponse(err), err
+	}
+	if len(request.Resources) == 0 {
+		err := newValidationError("resources", "A resource set needs at least one resource")
+		return nil, errorResponse(err), err
+	}
+	hrefs, err := i.resourceHrefs(request.Resources)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	resourceSet := &ResourceSet{
+		Id:          i.Client.ids.NewID(ResourceSetIDPrefix),
+		Label:       request.Label,
+		Description: request.Description,
+		Created:     &now,
+		LastUpdated: &now,
+	}
+	i.ResourceSets = append(i.ResourceSets, resourceSet)
+	i.addResources(resourceSet.Id, hrefs)
+	return resourceSet, newResponse(http.StatusOK, resourceSet), nil
+}
+
+// GetResourceSet returns the resource set with the ID or label
+func (i *IAMResource) GetResourceSet(ctx context.Context, resourceSetID string) (*ResourceSet, *okta.Response, error) {
+	i.Client.mu.RLock()
+	defer i.Client.mu.RUnlock()
+
+	resourceSet, err := i.getResourceSet(resourceSetID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return resourceSet, newResponse(http.StatusOK, resourceSet), nil
+}
+
+func (i *IAMResource) getResourceSet(resourceSetIDOrLabel string) (*ResourceSet, *okta.Error) {
+	for _, resourceSet := range i.ResourceSets {
+		if resourceSet.Id == resourceSetIDOrLabel || resourceSet.Label == resourceSetIDOrLabel {
+			return resourceSet, nil
+		}
+	}
+	return nil, newNotFoundError("ResourceSet", resourceSetIDOrLabel)
+}
+
+// UpdateResourceSet replaces the label and description of the resource set
+func (i *IAMResource) UpdateResourceSet(ctx context.Context, resourceSetID string, resourceSet ResourceSet) (*ResourceSet, *okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	existing, err := i.getResourceSet(resourceSetID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	if resourceSet.Label == "" {
+		err := newValidationError("label", "The field cannot be left blank")
+		return nil, errorResponse(err), err
+	}
+	if other, err := i.getResourceSet(resourceSet.Label); err == nil && other != existing {
+		err := newValidationError("label", "A resource set with this label already exists")
+		return nil, errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	existing.Label = resourceSet.Label
+	existing.Description = resourceSet.Description
+	existing.LastUpdated = &now
+	return existing, newResponse(http.StatusOK, existing), nil
+}
+
+// DeleteResourceSet deletes a resource set that no custom role is bound to
+func (i *IAMResource) DeleteResourceSet(ctx context.Context, resourceSetID string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	resourceSet, err := i.getResourceSet(resourceSetID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	for _, assignment := range i.activeAssignments() {
+		if assignment.ResourceSet == resourceSet.Id {
+			err := NewError(ErrorCodeUnsupportedOperation, "A custom role is still bound to the resource set.")
+			return errorResponse(err), err
+		}
+	}
+	for idx, r := range i.ResourceSets {
+		if r == resourceSet {
+			i.ResourceSets = append(i.ResourceSets[:idx], i.ResourceSets[idx+1:]...)
+			break
+		}
+	}
+	delete(i.Resources, resourceSet.Id)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// ListResourceSets returns a page of the resource sets
+func (i *IAMResource) ListResourceSets(ctx context.Context, qp *query.Params) ([]*ResourceSet, *okta.Response, error) {
+	i.Client.mu.RLock()
+	defer i.Client.mu.RUnlock()
+
+	resourceSets, after := paginate(i.ResourceSets, func(resourceSet *ResourceSet) string { return resourceSet.Id }, qp)
+	return resourceSets, i.Client.listResponse(ctx, "/api/v1/iam/resource-sets", qp, after), nil
+}
+
+// ListResourceSetResources returns a page of the resources in the resource set
+func (i *IAMResource) ListResourceSetResources(ctx context.Context, resourceSetID string, qp *query.Params) ([]*ResourceSetResource, *okta.Response, error) {
+	i.Client.mu.RLock()
+	defer i.Client.mu.RUnlock()
+
+	resourceSet, err := i.getResourceSet(resourceSetID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	resources, after := paginate(i.Resources[resourceSet.Id], func(resource *ResourceSetResource) string { return resource.Id }, qp)
+	return resources, i.Client.listResponse(ctx, fmt.Sprintf("/api/v1/iam/resource-sets/%v/resources", resourceSetID), qp, after), nil
+}
+
+// AddResourceSetResources adds the resources to the resource set, skipping the ones already in it
+func (i *IAMResource) AddResourceSetResources(ctx context.Context, resourceSetID string, resources []string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	resourceSet, err := i.getResourceSet(resourceSetID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	hrefs, err := i.resourceHrefs(resources)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	i.addResources(resourceSet.Id, hrefs)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// DeleteResourceSetResource removes the resource with the resourceID from the resource set. A resource
+// set needs at least one resource, so its last one can't be removed
+func (i *IAMResource) DeleteResourceSetResource(ctx context.Context, resourceSetID string, resourceID string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	resourceSet, err := i.getResourceSet(resourceSetID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	resources := i.Resources[resourceSet.Id]
+	for idx, resource := range resources {
+		if resource.Id == resourceID {
+			if len(resources) == 1 {
+				err := NewError(ErrorCodeUnsupportedOperation, "A resource set needs at least one resource.")
+				return errorResponse(err), err
+			}
+			i.Resources[resourceSet.Id] = append(resources[:idx:idx], resources[idx+1:]...)
+			return newResponse(http.StatusNoContent, nil), nil
+		}
+	}
+	err = newNotFoundError("Resource", resourceID)
+	return errorResponse(err), err
+}
+
+// resourceHrefs validates the resources and returns their hrefs. A resource is the href or the path
+// relative to /api/v1 of users, groups or apps, of a user, group or app by ID, or of a group's users
+func (i *IAMResource) resourceHrefs(resources []string) ([]string, *okta.Error) {
+	hrefs := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		path := strings.Trim(strings.TrimPrefix(strings.TrimPrefix(resource, orgURL), "/api/v1"), "/")
+		segments := strings.Split(path, "/")
+		valid := false
+		switch {
+		case len(segments) == 1:
+			valid = SliceContainsString([]string{"users", "groups", "apps"}, segments[0])
+		case segments[0] == "users" && len(segments) == 2:
+			_, err := i.Client.User.getUserByID(segments[1])
+			valid = err == nil
+		case segments[0] == "groups" && (len(segments) == 2 || len(segments) == 3 && segments[2] == "users"):
+			_, err := i.Client.Group.getGroupByID(segments[1])
+			valid = err == nil
+		case segments[0] == "apps" && len(segments) == 2:
+			_, err := i.Client.Application.getApplication(segments[1])
+			valid = err == nil
+		}
+		if !valid {
+			return nil, newValidationError("resources", fmt.Sprintf("Invalid resource %v", resource))
+		}
+		hrefs = append(hrefs, ResourceHref(path))
+	}
+	return hrefs, nil
+}
+
+// addResources adds the hrefs that aren't already in the resource set as new resources
+func (i *IAMResource) addResources(resourceSetID string, hrefs []string) {
+	now := time.Now().UTC()
+	for _, href := range hrefs {
+		exists := false
+		for _, resource := range i.Resources[resourceSetID] {
+			exists = exists || resource.Href == href
+		}
+		if !exists {
+			i.Resources[resourceSetID] = append(i.Resources[resourceSetID], &ResourceSetResource{
+				Id:      i.Client.ids.NewID(ResourceSetResourceIDPrefix),
+				Href:    href,
+				Created: &now,
+			})
+		}
+	}
+}
+
+// removeResources removes the resources for a deleted user, group or app, and the users of a deleted
+// group, from every resource set
+func (i *IAMResource) removeResources(path string) {
+	href := ResourceHref(path)
+	for resourceSetID, resources := range i.Resources {
+		remaining := make([]*ResourceSetResource, 0, len(resources))
+		for _, resource := range resources {
+			if resource.Href != href && resource.Href != href+"/users" {
+				remaining = append(remaining, resource)
+			}
+		}
+		i.Resources[resourceSetID] = remaining
+	}
+}
+
+// CreateResourceSetBinding binds the custom role to the resource set for the members, assigning it to
+// each of them. Use AddResourceSetBindingMembers to assign an existing binding to more members
+func (i *IAMResource) CreateResourceSetBinding(ctx context.Context, resourceSetID string, request RoleBindingRequest) (*RoleBinding, *okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	resourceSet, role, err := i.getBinding(resourceSetID, request.Role)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	if len(request.Members) == 0 {
+		err := newValidationError("members", "A binding needs at least one member")
+		return nil, errorResponse(err), err
+	}
+	if len(i.bindingMembers(resourceSet.Id, role.Id)) > 0 {
+		err := NewError(ErrorCodeDuplicateRole, "The custom role is already bound to the resource set.")
+		return nil, errorResponse(err), err
+	}
+	if err := i.addBindingMembers(resourceSet.Id, role.Id, request.Members); err != nil {
+		return nil, errorResponse(err), err
+	}
+	binding := &RoleBinding{Id: role.Id}
+	return binding, newResponse(http.StatusOK, binding), nil
+}
+
+// ListResourceSetBindings returns a page of the bindings of custom roles to the resource set
+func (i *IAMResource) ListResourceSetBindings(ctx context.Context, resourceSetID string, qp *query.Params) ([]*RoleBinding, *okta.Response, error) {
+	i.Client.mu.RLock()
+	defer i.Client.mu.RUnlock()
+
+	resourceSet, err := i.getResourceSet(resourceSetID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	bindings := make([]*RoleBinding, 0)
+	for _, role := range i.Roles {
+		if len(i.bindingMembers(resourceSet.Id, role.Id)) > 0 {
+			bindings = append(bindings, &RoleBinding{Id: role.Id})
+		}
+	}
+	page, after := paginate(bindings, func(binding *RoleBinding) string { return binding.Id }, qp)
+	return page, i.Client.listResponse(ctx, fmt.Sprintf("/api/v1/iam/resource-sets/%v/bindings", resourceSetID), qp, after), nil
+}
+
+// DeleteResourceSetBinding unbinds the custom role from the resource set, unassigning it from every member
+func (i *IAMResource) DeleteResourceSetBinding(ctx context.Context, resourceSetID string, roleIDOrLabel string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	resourceSet, role, err := i.getBinding(resourceSetID, roleIDOrLabel)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	members := i.bindingMembers(resourceSet.Id, role.Id)
+	if len(members) == 0 {
+		err := newNotFoundError("RoleBinding", role.Id)
+		return errorResponse(err), err
+	}
+	for _, member := range members {
+		i.Client.unassignRole(member.Id)
+	}
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// ListResourceSetBindingMembers returns a page of the groups and users the custom role is bound to
+// over the resource set
+func (i *IAMResource) ListResourceSetBindingMembers(ctx context.Context, resourceSetID string, roleIDOrLabel string, qp *query.Params) ([]*RoleBindingMember, *okta.Response, error) {
+	i.Client.mu.RLock()
+	defer i.Client.mu.RUnlock()
+
+	resourceSet, role, err := i.getBinding(resourceSetID, roleIDOrLabel)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	members, after := paginate(i.bindingMembers(resourceSet.Id, role.Id), func(member *RoleBindingMember) string { return member.Id }, qp)
+	return members, i.Client.listResponse(ctx, fmt.Sprintf("/api/v1/iam/resource-sets/%v/bindings/%v/members", resourceSetID, roleIDOrLabel), qp, after), nil
+}
+
+// AddResourceSetBindingMembers assigns the custom role over the resource set to more members, skipping
+// the ones it is already assigned to
+func (i *IAMResource) AddResourceSetBindingMembers(ctx context.Context, resourceSetID string, roleIDOrLabel string, members []string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	resourceSet, role, err := i.getBinding(resourceSetID, roleIDOrLabel)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if err := i.addBindingMembers(resourceSet.Id, role.Id, members); err != nil {
+		return errorResponse(err), err
+	}
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// DeleteResourceSetBindingMember unassigns the custom role over the resource set from the member with
+// the memberID, which is the ID of its role assignment
+func (i *IAMResource) DeleteResourceSetBindingMember(ctx context.Context, resourceSetID string, roleIDOrLabel string, memberID string) (*okta.Response, error) {
+	i.Client.mu.Lock()
+	defer i.Client.mu.Unlock()
+
+	if err := i.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	resourceSet, role, err := i.getBinding(resourceSetID, roleIDOrLabel)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	for _, member := range i.bindingMembers(resourceSet.Id, role.Id) {
+		if member.Id == memberID {
+			i.Client.unassignRole(memberID)
+			return newResponse(http.StatusNoContent, nil), nil
+		}
+	}
+	err = newNotFoundError("RoleBindingMember", memberID)
+	return errorResponse(err), err
+}
+
+// getBinding returns the resource set and custom role of a binding, whether or not they are bound
+func (i *IAMResource) getBinding(resourceSetID string, roleIDOrLabel string) (*ResourceSet, *CustomRole, *okta.Error) {
+	resourceSet, err := i.getResourceSet(resourceSetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	role, err := i.getCustomRole(roleIDOrLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resourceSet, role, nil
+}
+
+// bindingMembers returns the groups and then the users the custom role is assigned to over the resource set
+func (i *IAMResource) bindingMembers(resourceSetID string, roleID string) []*RoleBindingMember {
+	binding := CustomRoleAssignment{Role: roleID, ResourceSet: resourceSetID}
+	members := make([]*RoleBindingMember, 0)
+	for _, group := range i.Client.Group.Groups {
+		for _, role := range i.Client.Group.GroupRoles[group.Id] {
+			if assignment, ok := i.Assignments[role.Id]; ok && assignment == binding {
+				members = append(members, &RoleBindingMember{Id: role.Id, Href: ResourceHref("groups/" + group.Id)})
+			}
+		}
+	}
+	for _, user := range i.Client.User.Users {
+		for _, role := range i.Client.User.UserRoles[user.Id] {
+			if assignment, ok := i.Assignments[role.Id]; ok && assignment == binding {
+				members = append(members, &RoleBindingMember{Id: role.Id, Href: ResourceHref("users/" + user.Id)})
+			}
+		}
+	}
+	return members
+}
+
+// addBindingMembers assigns the custom role over the resource set to each member, an ID or href of a
+// group or user, that doesn't have it yet. Every member is checked before any is assigned
+func (i *IAMResource) addBindingMembers(resourceSetID string, roleID string, members []string) *okta.Error {
+	groupIDs, userIDs := []string{}, []string{}
+	for _, member := range members {
+		path := strings.Trim(strings.TrimPrefix(strings.TrimPrefix(member, orgURL), "/api/v1"), "/")
+		id := path[strings.LastIndex(path, "/")+1:]
+		if group, err := i.Client.Group.getGroupByID(id); err == nil && !strings.HasPrefix(path, "users/") {
+			groupIDs = append(groupIDs, group.Id)
+		} else if user, err := i.Client.User.getUserByID(id); err == nil && !strings.HasPrefix(path, "groups/") {
+			userIDs = append(userIDs, user.Id)
+		} else {
+			return newValidationError("members", fmt.Sprintf("Invalid member %v", member))
+		}
+	}
+	assignment := CustomRoleAssignment{Role: roleID, ResourceSet: resourceSetID}
+	for _, groupID := range groupIDs {
+		if !i.hasAssignment(i.Client.Group.GroupRoles[groupID], assignment) {
+			i.Client.Group.GroupRoles[groupID] = append(i.Client.Group.GroupRoles[groupID], i.newCustomRoleAssignment(assignment, RoleAssignmentTypeGroup))
+		}
+	}
+	for _, userID := range userIDs {
+		if !i.hasAssignment(i.Client.User.UserRoles[userID], assignment) {
+			i.Client.User.UserRoles[userID] = append(i.Client.User.UserRoles[userID], i.newCustomRoleAssignment(assignment, RoleAssignmentTypeUser))
+		}
+	}
+	return nil
+}
+
+// hasAssignment returns whether any of the roles is an assignment of the custom role over the resource set
+func (i *IAMResource) hasAssignment(roles []*okta.Role, assignment CustomRoleAssignment) bool {
+	for _, role := range roles {
+		if a, ok := i.Assignments[role.Id]; ok && a == assignment {
+			return true
+		}
+	}
+	return false
+}
+
+// newCustomRoleAssignment creates a CUSTOM role assignment of the custom role over the resource set,
+// linking to both the way Okta does
+func (i *IAMResource) newCustomRoleAssignment(assignment CustomRoleAssignment, assignmentType string) *okta.Role {
+	now := time.Now().UTC()
+	role, _ := i.getCustomRole(assignment.Role)
+	created := &okta.Role{
+		Id:             i.Client.ids.NewID(CustomRoleAssignmentIDPrefix),
+		Type:           CustomRoleType,
+		Label:          role.Label,
+		AssignmentType: assignmentType,
+		Status:         RoleStatusActive,
+		Created:        &now,
+		LastUpdated:    &now,
+		Links: map[string]interface{}{
+			"role":         map[string]interface{}{"href": ResourceHref("iam/roles/" + assignment.Role)},
+			"resource-set": map[string]interface{}{"href": ResourceHref("iam/resource-sets/" + assignment.ResourceSet)},
+		},
+	}
+	i.Assignments[created.Id] = assignment
+	return created
+}
+
+// activeAssignments returns the custom role and resource set of every CUSTOM role assignment of a
+// group or user that still exists
+func (i *IAMResource) activeAssignments() []CustomRoleAssignment {
+	assignments := make([]CustomRoleAssignment, 0)
+	for _, roles := range i.Client.Group.GroupRoles {
+		for _, role := range roles {
+			if assignment, ok := i.Assignments[role.Id]; ok {
+				assignments = append(assignments, assignment)
+			}
+		}
+	}
+	for _, roles := range i.Client.User.UserRoles {
+		for _, role := range roles {
+			if assignment, ok := i.Assignments[role.Id]; ok {
+				assignments = append(assignments, assignment)
+			}
+		}
+	}
+	return assignments
+}
+
+// assignCustomRole validates the assignRoleRequest and returns the CUSTOM role assignment for it,
+// erring if the roles already contain one of the same custom role over the same resource set
+func (i *IAMResource) assignCustomRole(roles []*okta.Role, assignRoleRequest CustomRoleAssignRequest, assignmentType string) (*okta.Role, *okta.Error) {
+	if assignRoleRequest.Type != CustomRoleType {
+		return nil, newValidationError("type", "Custom role assignments need the CUSTOM type")
+	}
+	resourceSet, role, err := i.getBinding(assignRoleRequest.ResourceSet, assignRoleRequest.Role)
+	if err != nil {
+		return nil, err
+	}
+	assignment := CustomRoleAssignment{Role: role.Id, ResourceSet: resourceSet.Id}
+	if i.hasAssignment(roles, assignment) {
+		return nil, NewError(ErrorCodeDuplicateRole, "The custom role is already assigned over the resource set.")
+	}
+	return i.newCustomRoleAssignment(assignment, assignmentType), nil
+}
+
+// AssignCustomRoleToGroup assigns the custom role over the resource set in the assignRoleRequest to
+// the group, returning the CUSTOM role assignment
+func (g *GroupResource) AssignCustomRoleToGroup(ctx context.Context, groupID string, assignRoleRequest CustomRoleAssignRequest, qp *query.Params) (*okta.Role, *okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	return g.assignCustomRoleToGroup(groupID, assignRoleRequest)
+}
+
+// assignCustomRoleToGroup is AssignCustomRoleToGroup for callers that hold the lock and authorized the call
+func (g *GroupResource) assignCustomRoleToGroup(groupID string, assignRoleRequest CustomRoleAssignRequest) (*okta.Role, *okta.Response, error) {
+	group, err := g.getGroupByID(groupID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	role, roleErr := g.Client.IAM.assignCustomRole(g.GroupRoles[group.Id], assignRoleRequest, RoleAssignmentTypeGroup)
+	if roleErr != nil {
+		return nil, errorResponse(roleErr), roleErr
+	}
+	g.GroupRoles[group.Id] = append(g.GroupRoles[group.Id], role)
+	return role, newResponse(http.StatusCreated, role), nil
+}
+
+// AssignCustomRoleToUser assigns the custom role over the resource set in the assignRoleRequest to
+// the user directly, returning the CUSTOM role assignment
+func (u *UserResource) AssignCustomRoleToUser(ctx context.Context, userID string, assignRoleRequest CustomRoleAssignRequest, qp *query.Params) (*okta.Role, *okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	return u.assignCustomRoleToUser(userID, assignRoleRequest)
+}
+
+// assignCustomRoleToUser is AssignCustomRoleToUser for callers that hold the lock and authorized the call
+func (u *UserResource) assignCustomRoleToUser(userID string, assignRoleRequest CustomRoleAssignRequest) (*okta.Role, *okta.Response, error) {
+	user, err := u.getUserByID(userID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	role, roleErr := u.Client.IAM.assignCustomRole(u.UserRoles[user.Id], assignRoleRequest, RoleAssignmentTypeUser)
+	if roleErr != nil {
+		return nil, errorResponse(roleErr), roleErr
+	}
+	u.UserRoles[user.Id] = append(u.UserRoles[user.Id], role)
+	return role, newResponse(http.StatusCreated, role), nil
+}
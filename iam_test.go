@@ -0,0 +1,300 @@
+package mockokta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func newTestBinding(t *testing.T, client *MockClient) (*CustomRole, *ResourceSet, *okta.Group) {
+	t.Helper()
+	group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+	role, _, err := client.CreateCustomRole(context.TODO(), NewCustomRole("UserManager", "okta.users.manage", "okta.groups.members.manage"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	resourceSet, _, err := client.CreateResourceSet(context.TODO(), NewCreateResourceSetRequest("EngineeringUsers", "groups/"+group.Id+"/users"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return role, resourceSet, group
+}
+
+func TestIAMResource_CustomRoles(t *testing.T) {
+	t.Run("should create and get custom roles by ID or label", func(t *testing.T) {
+		client := NewClient()
+
+		role, _, err := client.CreateCustomRole(context.TODO(), NewCustomRole("UserManager", "okta.users.manage"))
+		byID, _, _ := client.GetCustomRole(context.TODO(), role.Id)
+		byLabel, _, _ := client.GetCustomRole(context.TODO(), "UserManager")
+
+		if err != nil || byID != role || byLabel != role {
+			t.Errorf("got %v and %v (%v) want %v", byID, byLabel, err, role)
+		}
+	})
+
+	tests := []struct {
+		name string
+		role CustomRole
+	}{
+		{"should err without a label", NewCustomRole("", "okta.users.manage")},
+		{"should err without permissions", NewCustomRole("UserManager")},
+		{"should err for an invalid permission", NewCustomRole("UserManager", "okta.users.destroy")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient()
+
+			_, resp, err := client.CreateCustomRole(context.TODO(), tt.role)
+
+			if err == nil || resp.StatusCode != 400 {
+				t.Errorf("got %v want a validation error", err)
+			}
+		})
+	}
+
+	t.Run("should err for a duplicate label", func(t *testing.T) {
+		client := NewClient()
+		client.CreateCustomRole(context.TODO(), NewCustomRole("UserManager", "okta.users.manage"))
+
+		_, _, err := client.CreateCustomRole(context.TODO(), NewCustomRole("UserManager", "okta.groups.manage"))
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should add and remove permissions but keep the last one", func(t *testing.T) {
+		client := NewClient()
+		role, _, _ := client.CreateCustomRole(context.TODO(), NewCustomRole("UserManager", "okta.users.manage"))
+
+		client.AddCustomRolePermission(context.TODO(), role.Id, "okta.groups.read")
+		client.RemoveCustomRolePermission(context.TODO(), role.Id, "okta.users.manage")
+		_, lastErr := client.RemoveCustomRolePermission(context.TODO(), role.Id, "okta.groups.read")
+
+		if len(role.Permissions) != 1 || role.Permissions[0] != "okta.groups.read" || lastErr == nil {
+			t.Errorf("got permissions %v (%v) want only okta.groups.read", role.Permissions, lastErr)
+		}
+	})
+}
+
+func TestIAMResource_ResourceSets(t *testing.T) {
+	t.Run("should store resources as hrefs", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+
+		resourceSet, _, err := client.CreateResourceSet(context.TODO(), NewCreateResourceSetRequest("Engineering", "groups/"+group.Id, ResourceHref("users")))
+		client.AddResourceSetResources(context.TODO(), resourceSet.Id, []string{"/api/v1/users", "apps"})
+		got, _, _ := client.ListResourceSetResources(context.TODO(), resourceSet.Id, nil)
+
+		want := []string{ResourceHref("groups/" + group.Id), ResourceHref("users"), ResourceHref("apps")}
+		if err != nil || len(got) != len(want) {
+			t.Fatalf("got %v (%v) want %v", got, err, want)
+		}
+		for idx := range got {
+			if got[idx].Href != want[idx] {
+				t.Errorf("got %v want %v", got[idx].Href, want[idx])
+			}
+		}
+	})
+
+	t.Run("should err for unknown resources", func(t *testing.T) {
+		client := NewClient()
+
+		_, _, missingErr := client.CreateResourceSet(context.TODO(), NewCreateResourceSetRequest("Set1", "groups/00g_missing"))
+		_, _, invalidErr := client.CreateResourceSet(context.TODO(), NewCreateResourceSetRequest("Set2", "policies"))
+
+		if missingErr == nil || invalidErr == nil {
+			t.Errorf("got %v and %v want validation errors", missingErr, invalidErr)
+		}
+	})
+
+	t.Run("should keep the last resource", func(t *testing.T) {
+		client := NewClient()
+		resourceSet, _, _ := client.CreateResourceSet(context.TODO(), NewCreateResourceSetRequest("AllUsers", "users"))
+		resources, _, _ := client.ListResourceSetResources(context.TODO(), resourceSet.Id, nil)
+
+		_, err := client.DeleteResourceSetResource(context.TODO(), resourceSet.Id, resources[0].Id)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should remove deleted groups from resource sets", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		resourceSet, _, _ := client.CreateResourceSet(context.TODO(), NewCreateResourceSetRequest("Engineering", "users", "groups/"+group.Id, "groups/"+group.Id+"/users"))
+
+		client.DeleteGroup(context.TODO(), group.Id)
+		got, _, _ := client.ListResourceSetResources(context.TODO(), resourceSet.Id, nil)
+
+		if len(got) != 1 || got[0].Href != ResourceHref("users") {
+			t.Errorf("got %v want only all users", got)
+		}
+	})
+}
+
+func TestIAMResource_CustomRoleAssignments(t *testing.T) {
+	t.Run("should assign a custom role over a resource set to a group", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+
+		assigned, resp, err := client.AssignCustomRoleToGroup(context.TODO(), group.Id, NewCustomRoleAssignRequest(role.Id, resourceSet.Id), nil)
+		roles, _, _ := client.ListGroupAssignedRoles(context.TODO(), group.Id, nil)
+
+		if err != nil || resp.StatusCode != 201 {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if assigned.Type != CustomRoleType || assigned.Label != role.Label || len(roles) != 1 || roles[0] != assigned {
+			t.Errorf("got %v want a CUSTOM %v role", roles, role.Label)
+		}
+		if got := client.IAM.Assignments[assigned.Id]; got.Role != role.Id || got.ResourceSet != resourceSet.Id {
+			t.Errorf("got assignment %v want %v over %v", got, role.Id, resourceSet.Id)
+		}
+	})
+
+	t.Run("should assign a custom role with AssignRoleToGroup and AssignRoleToUser", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		ctx := WithCustomRoleAssignment(context.TODO(), role.Label, resourceSet.Id)
+
+		groupRole, groupResp, groupErr := client.AssignRoleToGroup(ctx, group.Id, NewAssignRoleRequest(CustomRoleType), nil)
+		userRole, _, userErr := client.AssignRoleToUser(ctx, user.Id, NewAssignRoleRequest(CustomRoleType), nil)
+		_, dupResp, dupErr := client.AssignRoleToGroup(ctx, group.Id, NewAssignRoleRequest(CustomRoleType), nil)
+
+		if groupErr != nil || userErr != nil || groupResp.StatusCode != 201 {
+			t.Fatalf("got %v and %v want no errors", groupErr, userErr)
+		}
+		want := CustomRoleAssignment{Role: role.Id, ResourceSet: resourceSet.Id}
+		if groupRole.Type != CustomRoleType || client.IAM.Assignments[groupRole.Id] != want || client.IAM.Assignments[userRole.Id] != want {
+			t.Errorf("got %v and %v want CUSTOM assignments of %v", groupRole, userRole, want)
+		}
+		assertOktaError(t, dupResp, dupErr, ErrorCodeDuplicateRole)
+	})
+
+	t.Run("should err assigning the same custom role over the same resource set twice", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		client.AssignCustomRoleToGroup(context.TODO(), group.Id, NewCustomRoleAssignRequest(role.Id, resourceSet.Id), nil)
+
+		_, resp, err := client.AssignCustomRoleToGroup(context.TODO(), group.Id, NewCustomRoleAssignRequest(role.Label, resourceSet.Id), nil)
+
+		if err == nil || resp.StatusCode != 409 {
+			t.Errorf("got %v want a duplicate role error", err)
+		}
+	})
+
+	t.Run("should err assigning CUSTOM without a custom role", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		_, groupResp, groupErr := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest(CustomRoleType), nil)
+		_, userResp, userErr := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest(CustomRoleType), nil)
+		_, _, roleErr := client.AssignCustomRoleToGroup(context.TODO(), group.Id, NewCustomRoleAssignRequest("cr0_missing", "iam_missing"), nil)
+
+		assertOktaError(t, groupResp, groupErr, ErrorCodeValidation)
+		assertOktaError(t, userResp, userErr, ErrorCodeValidation)
+		if roleErr == nil {
+			t.Errorf("got %v want an error", roleErr)
+		}
+	})
+
+	t.Run("should not delete roles and resource sets that are bound", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		assigned, _, _ := client.AssignCustomRoleToGroup(context.TODO(), group.Id, NewCustomRoleAssignRequest(role.Id, resourceSet.Id), nil)
+
+		_, roleErr := client.DeleteCustomRole(context.TODO(), role.Id)
+		_, setErr := client.DeleteResourceSet(context.TODO(), resourceSet.Id)
+		if roleErr == nil || setErr == nil {
+			t.Errorf("got %v and %v want errors", roleErr, setErr)
+		}
+
+		client.RemoveRoleFromGroup(context.TODO(), group.Id, assigned.Id)
+		_, roleErr = client.DeleteCustomRole(context.TODO(), role.Id)
+		_, setErr = client.DeleteResourceSet(context.TODO(), resourceSet.Id)
+		if roleErr != nil || setErr != nil {
+			t.Errorf("got %v and %v want no errors", roleErr, setErr)
+		}
+	})
+}
+
+func TestIAMResource_Bindings(t *testing.T) {
+	t.Run("should assign the binding to its members", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		_, _, err := client.CreateResourceSetBinding(context.TODO(), resourceSet.Id, RoleBindingRequest{
+			Role:    role.Label,
+			Members: []string{ResourceHref("users/" + user.Id), group.Id},
+		})
+		bindings, _, _ := client.ListResourceSetBindings(context.TODO(), resourceSet.Id, nil)
+		members, _, _ := client.ListResourceSetBindingMembers(context.TODO(), resourceSet.Id, role.Id, nil)
+		userRoles, _, _ := client.ListAssignedRolesForUser(context.TODO(), user.Id, nil)
+
+		if err != nil || len(bindings) != 1 || bindings[0].Id != role.Id {
+			t.Fatalf("got %v (%v) want a binding of %v", bindings, err, role.Id)
+		}
+		if len(members) != 2 || members[0].Href != ResourceHref("groups/"+group.Id) || members[1].Href != ResourceHref("users/"+user.Id) {
+			t.Errorf("got members %v want the group and the user", members)
+		}
+		if len(userRoles) != 1 || userRoles[0].Id != members[1].Id || userRoles[0].AssignmentType != RoleAssignmentTypeUser {
+			t.Errorf("got roles %v want the binding's assignment", userRoles)
+		}
+	})
+
+	t.Run("should add and remove members", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.CreateResourceSetBinding(context.TODO(), resourceSet.Id, RoleBindingRequest{Role: role.Id, Members: []string{group.Id}})
+
+		client.AddResourceSetBindingMembers(context.TODO(), resourceSet.Id, role.Id, []string{group.Id, user.Id})
+		members, _, _ := client.ListResourceSetBindingMembers(context.TODO(), resourceSet.Id, role.Id, nil)
+		client.DeleteResourceSetBindingMember(context.TODO(), resourceSet.Id, role.Id, members[0].Id)
+		got, _, _ := client.ListResourceSetBindingMembers(context.TODO(), resourceSet.Id, role.Id, nil)
+
+		if len(members) != 2 || len(got) != 1 || got[0].Href != ResourceHref("users/"+user.Id) {
+			t.Errorf("got members %v then %v want the group and user then only the user", members, got)
+		}
+		if roles, _, _ := client.ListGroupAssignedRoles(context.TODO(), group.Id, nil); len(roles) != 0 {
+			t.Errorf("got group roles %v want none", roles)
+		}
+	})
+
+	t.Run("should unassign every member when the binding is deleted", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		client.CreateResourceSetBinding(context.TODO(), resourceSet.Id, RoleBindingRequest{Role: role.Id, Members: []string{group.Id}})
+
+		_, err := client.DeleteResourceSetBinding(context.TODO(), resourceSet.Id, role.Id)
+		bindings, _, _ := client.ListResourceSetBindings(context.TODO(), resourceSet.Id, nil)
+
+		if err != nil || len(bindings) != 0 || len(client.Group.GroupRoles[group.Id]) != 0 {
+			t.Errorf("got bindings %v (%v) want none", bindings, err)
+		}
+	})
+
+	t.Run("should err for invalid members and existing bindings", func(t *testing.T) {
+		client := NewClient()
+		role, resourceSet, group := newTestBinding(t, client)
+		client.CreateResourceSetBinding(context.TODO(), resourceSet.Id, RoleBindingRequest{Role: role.Id, Members: []string{group.Id}})
+
+		other, _, _ := client.CreateCustomRole(context.TODO(), NewCustomRole("GroupReader", "okta.groups.read"))
+
+		_, _, memberErr := client.CreateResourceSetBinding(context.TODO(), resourceSet.Id, RoleBindingRequest{Role: other.Id, Members: []string{group.Id, "00u_missing"}})
+		_, resp, existingErr := client.CreateResourceSetBinding(context.TODO(), resourceSet.Id, RoleBindingRequest{Role: role.Id, Members: []string{group.Id}})
+
+		if memberErr == nil || existingErr == nil || resp.StatusCode != 409 {
+			t.Errorf("got %v and %v want errors", memberErr, existingErr)
+		}
+		if len(client.Group.GroupRoles[group.Id]) != 1 {
+			t.Errorf("got group roles %v want only the first binding's", client.Group.GroupRoles[group.Id])
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package mockokta
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Prefixes of the IDs the mock generates, matching the object type prefixes used by Okta
+const (
+	GroupIDPrefix          = "00g"
+	UserIDPrefix           = "00u"
+	RoleAssignmentIDPrefix = "ra1"
+	GroupRuleIDPrefix      = "0pr"
+	ApplicationIDPrefix    = "0oa"
+	CustomRoleIDPrefix     = "cr0"
+	ResourceSetIDPrefix    = "iam"
+	// ResourceSetResourceIDPrefix and CustomRoleAssignmentIDPrefix are the prefixes of the IDs of
+	// the resources in a resource set and of the assignments of custom roles
+	ResourceSetResourceIDPrefix  = "ire"
+	CustomRoleAssignmentIDPrefix = "irb"
+)
+
+// idLength is the length of an Okta object ID including its prefix, e.g. 00g1emaKYZTWRYYRRTSK
+const idLength = 20
+
+// IDGenerator generates Okta-format IDs that are never repeated for the lifetime of the generator
+type IDGenerator struct {
+	mu     sync.Mutex
+	rand   *rand.Rand
+	issued map[string]bool
+}
+
+// NewIDGenerator creates an IDGenerator. Generators created with the same seed generate the same
+// sequence of IDs, so tests can assert exact IDs
+func NewIDGenerator(seed int64) *IDGenerator {
+	return &IDGenerator{
+		rand:   rand.New(rand.NewSource(seed)),
+		issued: make(map[string]bool),
+	}
+}
+
+// NewID returns a new unique ID starting with the prefix
+func (g *IDGenerator) NewID(prefix string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for {
+		b := make([]rune, idLength-len(prefix))
+		for i := range b {
+			b[i] = idRunes[g.rand.Intn(len(idRunes))]
+		}
+		id := prefix + string(b)
+		if !g.issued[id] {
+			g.issued[id] = true
+			return id
+		}
+	}
+}
+
+// reserve marks the IDs as issued, so IDs of objects the generator didn't create aren't generated again
+func (g *IDGenerator) reserve(ids ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, id := range ids {
+		g.issued[id] = true
+	}
+}
+
+// reserveIDs reserves the IDs of every object of the client, after it was given objects it didn't
+// create, like the ones of a restored Snapshot
+func (client *MockClient) reserveIDs() {
+	for _, group := range client.Group.Groups {
+		client.ids.reserve(group.Id)
+	}
+	for _, user := range client.User.Users {
+		client.ids.reserve(user.Id)
+	}
+	for _, roles := range client.Group.GroupRoles {
+		for _, role := range roles {
+			client.ids.reserve(role.Id)
+		}
+	}
+	for _, roles := range client.User.UserRoles {
+		for _, role := range roles {
+			client.ids.reserve(role.Id)
+		}
+	}
+	for _, rule := range client.Group.GroupRules {
+		client.ids.reserve(rule.Id)
+	}
+	for _, app := range client.Application.Applications {
+		if fields, ok := fieldsOf(app); ok {
+			client.ids.reserve(*fields.id)
+		}
+	}
+	for _, role := range client.IAM.Roles {
+		client.ids.reserve(role.Id)
+	}
+	for _, resourceSet := range client.IAM.ResourceSets {
+		client.ids.reserve(resourceSet.Id)
+	}
+	for _, resources := range client.IAM.Resources {
+		for _, resource := range resources {
+			client.ids.reserve(resource.Id)
+		}
+	}
+}
+
+// ClientOption configures a MockClient created with NewClient
+type ClientOption func(*MockClient)
+
+// WithIDSeed makes the client generate IDs deterministically from the seed instead of randomly
+func WithIDSeed(seed int64) ClientOption {
+	return func(c *MockClient) {
+		c.ids = NewIDGenerator(seed)
+	}
+}
+
+// WithIDGenerator makes the client generate IDs with an existing IDGenerator
+func WithIDGenerator(ids *IDGenerator) ClientOption {
+	return func(c *MockClient) {
+		c.ids = ids
+	}
+}
+
+// newDefaultIDGenerator creates the IDGenerator used when no seed is given
+func newDefaultIDGenerator() *IDGenerator {
+	return NewIDGenerator(time.Now().UnixNano())
+}
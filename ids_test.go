@@ -0,0 +1,98 @@
+package mockokta
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIDGenerator_NewID(t *testing.T) {
+	t.Run("should generate okta format ids", func(t *testing.T) {
+		got := NewIDGenerator(1).NewID(GroupIDPrefix)
+
+		if !strings.HasPrefix(got, GroupIDPrefix) || len(got) != idLength {
+			t.Errorf("got %v want a %v character id starting with %v", got, idLength, GroupIDPrefix)
+		}
+	})
+
+	t.Run("should generate the same ids for the same seed", func(t *testing.T) {
+		a, b := NewIDGenerator(1), NewIDGenerator(1)
+
+		for i := 0; i < 10; i++ {
+			if got, want := a.NewID(UserIDPrefix), b.NewID(UserIDPrefix); got != want {
+				t.Errorf("got %v want %v", got, want)
+			}
+		}
+	})
+
+	t.Run("should not repeat ids", func(t *testing.T) {
+		ids := NewIDGenerator(1)
+		seen := make(map[string]bool)
+
+		for i := 0; i < 1000; i++ {
+			id := ids.NewID(UserIDPrefix)
+			if seen[id] {
+				t.Fatalf("got repeated id %v", id)
+			}
+			seen[id] = true
+		}
+	})
+}
+
+func TestMockClient_IDs(t *testing.T) {
+	t.Run("should not reuse the id of a deleted group", func(t *testing.T) {
+		client := NewClient()
+		group1, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup1"))
+		client.CreateGroup(context.TODO(), *NewGroup("TestGroup2"))
+		client.DeleteGroup(context.TODO(), group1.Id)
+
+		group3, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup3"))
+
+		for _, group := range client.Group.Groups {
+			if group != group3 && group.Id == group3.Id {
+				t.Errorf("got duplicate group id %v", group3.Id)
+			}
+		}
+	})
+
+	t.Run("should give role assignments unique ids across groups", func(t *testing.T) {
+		client := NewClient()
+		group1, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup1"))
+		group2, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup2"))
+
+		role1, _, _ := client.AssignRoleToGroup(context.TODO(), group1.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+		role2, _, _ := client.AssignRoleToGroup(context.TODO(), group2.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		if role1.Id == role2.Id || !strings.HasPrefix(role1.Id, RoleAssignmentIDPrefix) {
+			t.Errorf("got role ids %v and %v want unique ids starting with %v", role1.Id, role2.Id, RoleAssignmentIDPrefix)
+		}
+	})
+
+	t.Run("should generate exact ids with a seed", func(t *testing.T) {
+		want := NewIDGenerator(42)
+		client := NewClient(WithIDSeed(42))
+
+		if everyone := client.Group.EveryoneGroup(); everyone.Id != want.NewID(GroupIDPrefix) {
+			t.Errorf("got Everyone group id %v which does not match the seeded generator", everyone.Id)
+		}
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		if group.Id != want.NewID(GroupIDPrefix) || user.Id != want.NewID(UserIDPrefix) {
+			t.Errorf("got ids %v and %v which do not match the seeded generator", group.Id, user.Id)
+		}
+	})
+
+	t.Run("should not use an id for a group that failed validation", func(t *testing.T) {
+		want := NewIDGenerator(42)
+		client := NewClient(WithIDSeed(42))
+		want.NewID(GroupIDPrefix)
+		client.CreateGroup(context.TODO(), *NewGroup(""))
+
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		if got := want.NewID(GroupIDPrefix); group.Id != got {
+			t.Errorf("got %v want %v", group.Id, got)
+		}
+	})
+}
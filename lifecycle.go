@@ -0,0 +1,279 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// Okta user statuses, see https://developer.okta.com/docs/reference/api/users/#user-status
+const (
+	UserStatusStaged          = "STAGED"
+	UserStatusProvisioned     = "PROVISIONED"
+	UserStatusActive          = "ACTIVE"
+	UserStatusRecovery        = "RECOVERY"
+	UserStatusPasswordExpired = "PASSWORD_EXPIRED"
+	UserStatusLockedOut       = "LOCKED_OUT"
+	UserStatusSuspended       = "SUSPENDED"
+	UserStatusDeprovisioned   = "DEPROVISIONED"
+)
+
+// ActivateUser is a wrapper to call client.User.ActivateUser to make it easier to match an interface for the okta client
+func (client *MockClient) ActivateUser(ctx context.Context, userID string, qp *query.Params) (*okta.UserActivationToken, *okta.Response, error) {
+	return intercept(ctx, client, "ActivateUser", []interface{}{userID, qp}, func() (*okta.UserActivationToken, *okta.Response, error) {
+		return client.User.ActivateUser(ctx, userID, qp)
+	})
+}
+
+// DeactivateUser is a wrapper to call client.User.DeactivateUser to make it easier to match an interface for the okta client
+func (client *MockClient) DeactivateUser(ctx context.Context, userID string, qp *query.Params) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeactivateUser", []interface{}{userID, qp}, func() (*okta.Response, error) {
+		return client.User.DeactivateUser(ctx, userID, qp)
+	})
+}
+
+// SuspendUser is a wrapper to call client.User.SuspendUser to make it easier to match an interface for the okta client
+func (client *MockClient) SuspendUser(ctx context.Context, userID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "SuspendUser", []interface{}{userID}, func() (*okta.Response, error) {
+		return client.User.SuspendUser(ctx, userID)
+	})
+}
+
+// UnsuspendUser is a wrapper to call client.User.UnsuspendUser to make it easier to match an interface for the okta client
+func (client *MockClient) UnsuspendUser(ctx context.Context, userID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "UnsuspendUser", []interface{}{userID}, func() (*okta.Response, error) {
+		return client.User.UnsuspendUser(ctx, userID)
+	})
+}
+
+// UnlockUser is a wrapper to call client.User.UnlockUser to make it easier to match an interface for the okta client
+func (client *MockClient) UnlockUser(ctx context.Context, userID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "UnlockUser", []interface{}{userID}, func() (*okta.Response, error) {
+		return client.User.UnlockUser(ctx, userID)
+	})
+}
+
+// ResetPassword is a wrapper to call client.User.ResetPassword to make it easier to match an interface for the okta client
+func (client *MockClient) ResetPassword(ctx context.Context, userID string, qp *query.Params) (*okta.ResetPasswordToken, *okta.Response, error) {
+	return intercept(ctx, client, "ResetPassword", []interface{}{userID, qp}, func() (*okta.ResetPasswordToken, *okta.Response, error) {
+		return client.User.ResetPassword(ctx, userID, qp)
+	})
+}
+
+// ExpirePassword is a wrapper to call client.User.ExpirePassword to make it easier to match an interface for the okta client
+func (client *MockClient) ExpirePassword(ctx context.Context, userID string) (*okta.User, *okta.Response, error) {
+	return intercept(ctx, client, "ExpirePassword", []interface{}{userID}, func() (*okta.User, *okta.Response, error) {
+		return client.User.ExpirePassword(ctx, userID)
+	})
+}
+
+// DeactivateOrDeleteUser is a wrapper to call client.User.DeactivateOrDeleteUser to make it easier to match an interface for the okta client
+func (client *MockClient) DeactivateOrDeleteUser(ctx context.Context, userID string, qp *query.Params) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "DeactivateOrDeleteUser", []interface{}{userID, qp}, func() (*okta.Response, error) {
+		return client.User.DeactivateOrDeleteUser(ctx, userID, qp)
+	})
+}
+
+// setUserStatus moves the user to the status and updates its status timestamps
+func setUserStatus(user *okta.User, status string) {
+	now := time.Now().UTC()
+	user.Status = status
+	user.StatusChanged = &now
+	user.LastUpdated = &now
+}
+
+// activateUser activates a STAGED or DEPROVISIONED user, making it ACTIVE if it has a password
+// or PROVISIONED until it sets one if not
+func activateUser(user *okta.User) {
+	if user.Credentials != nil && user.Credentials.Password != nil {
+		setUserStatus(user, UserStatusActive)
+	} else {
+		setUserStatus(user, UserStatusProvisioned)
+	}
+	user.Activated = user.StatusChanged
+}
+
+// transitionUser looks up the user and moves it to the status if it is currently in one of the from
+// statuses, otherwise it returns err, or E0000038 if err is nil. The principal needs the permission
+// over the user, unless it is empty for transitions the mock simulates like LockOutUser
+func (u *UserResource) transitionUser(userID string, permission string, status string, from []string, err *okta.Error) (*okta.User, *okta.Response, error) {
+	user, lookupErr := u.getUserByID(userID)
+	if lookupErr != nil {
+		return nil, errorResponse(lookupErr), lookupErr
+	}
+	if permission != "" {
+		if authErr := u.Client.authorizeUser(permission, user.Id); authErr != nil {
+			return nil, errorResponse(authErr), authErr
+		}
+	}
+	if !SliceContainsString(from, user.Status) {
+		if err == nil {
+			err = NewError(ErrorCodeInvalidStatus)
+		}
+		return nil, errorResponse(err), err
+	}
+	setUserStatus(user, status)
+	return user, newResponse(http.StatusOK, nil), nil
+}
+
+// ActivateUser activates a STAGED or DEPROVISIONED user. When the sendEmail query param is false
+// the activation token is returned instead of being emailed to the user
+func (u *UserResource) ActivateUser(ctx context.Context, userID string, qp *query.Params) (*okta.UserActivationToken, *okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	user, err := u.getUserByID(userID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	if err := u.Client.authorizeUser("okta.users.lifecycle.activate", user.Id); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if user.Status == UserStatusActive {
+		err := NewError(ErrorCodeAlreadyActive)
+		return nil, errorResponse(err), err
+	}
+	if user.Status != UserStatusStaged && user.Status != UserStatusDeprovisioned {
+		err := NewError(ErrorCodeInvalidStatus)
+		return nil, errorResponse(err), err
+	}
+	activateUser(user)
+
+	token := &okta.UserActivationToken{}
+	if qp != nil && qp.SendEmail != nil && !*qp.SendEmail {
+		token.ActivationToken = randomID(20)
+		token.ActivationUrl = fmt.Sprintf("%s/welcome/%s", orgURL, token.ActivationToken)
+	}
+	return token, newResponse(http.StatusOK, token), nil
+}
+
+// DeactivateUser deprovisions a user in any status other than DEPROVISIONED
+func (u *UserResource) DeactivateUser(ctx context.Context, userID string, qp *query.Params) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	return u.deactivateUser(userID)
+}
+
+func (u *UserResource) deactivateUser(userID string) (*okta.Response, error) {
+	user, resp, err := u.transitionUser(userID, "okta.users.lifecycle.deactivate", UserStatusDeprovisioned, []string{
+		UserStatusStaged, UserStatusProvisioned, UserStatusActive, UserStatusRecovery,
+		UserStatusPasswordExpired, UserStatusLockedOut, UserStatusSuspended,
+	}, nil)
+	if err == nil {
+		user.Activated = nil
+	}
+	return resp, err
+}
+
+// SuspendUser suspends an ACTIVE user
+func (u *UserResource) SuspendUser(ctx context.Context, userID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	_, resp, err := u.transitionUser(userID, "okta.users.lifecycle.suspend", UserStatusSuspended, []string{UserStatusActive},
+		newValidationError("status", "Cannot suspend a user that is not active"))
+	return resp, err
+}
+
+// UnsuspendUser returns a SUSPENDED user to ACTIVE
+func (u *UserResource) UnsuspendUser(ctx context.Context, userID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	_, resp, err := u.transitionUser(userID, "okta.users.lifecycle.unsuspend", UserStatusActive, []string{UserStatusSuspended},
+		newValidationError("status", "Cannot unsuspend a user that is not suspended"))
+	return resp, err
+}
+
+// UnlockUser returns a LOCKED_OUT user to ACTIVE
+func (u *UserResource) UnlockUser(ctx context.Context, userID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	_, resp, err := u.transitionUser(userID, "okta.users.lifecycle.unlock", UserStatusActive, []string{UserStatusLockedOut}, NewError(ErrorCodeUnlockNotAllowed))
+	return resp, err
+}
+
+// ResetPassword moves the user to RECOVERY. When the sendEmail query param is false the reset
+// password URL is returned instead of being emailed to the user
+func (u *UserResource) ResetPassword(ctx context.Context, userID string, qp *query.Params) (*okta.ResetPasswordToken, *okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	_, resp, err := u.transitionUser(userID, "okta.users.credentials.resetPassword", UserStatusRecovery, []string{
+		UserStatusActive, UserStatusRecovery, UserStatusPasswordExpired, UserStatusLockedOut,
+	}, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+	token := &okta.ResetPasswordToken{}
+	if qp != nil && qp.SendEmail != nil && !*qp.SendEmail {
+		token.ResetPasswordUrl = fmt.Sprintf("%s/reset_password/%s", orgURL, randomID(20))
+	}
+	return token, newResponse(http.StatusOK, token), nil
+}
+
+// ExpirePassword moves the user to PASSWORD_EXPIRED so they must change their password on next sign in
+func (u *UserResource) ExpirePassword(ctx context.Context, userID string) (*okta.User, *okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	user, resp, err := u.transitionUser(userID, "okta.users.credentials.expirePassword", UserStatusPasswordExpired, []string{
+		UserStatusActive, UserStatusRecovery, UserStatusPasswordExpired,
+	}, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+	return user, newResponse(http.StatusOK, user), nil
+}
+
+// DeactivateOrDeleteUser deactivates the user, or deletes it if it is already DEPROVISIONED
+func (u *UserResource) DeactivateOrDeleteUser(ctx context.Context, userID string, qp *query.Params) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	user, err := u.getUserByID(userID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if user.Status != UserStatusDeprovisioned {
+		if _, err := u.deactivateUser(userID); err != nil {
+			return errorResponse(err), err
+		}
+		return newResponse(http.StatusNoContent, nil), nil
+	}
+	if err := u.Client.authorizeUser("okta.users.lifecycle.delete", user.Id); err != nil {
+		return errorResponse(err), err
+	}
+
+	for idx, x := range u.Users {
+		if x.Id == userID {
+			u.Users = append(u.Users[:idx], u.Users[idx+1:]...)
+			break
+		}
+	}
+	for _, role := range u.UserRoles[user.Id] {
+		u.Client.forgetRole(role.Id)
+	}
+	delete(u.UserRoles, user.Id)
+	u.Client.IAM.removeResources("users/" + user.Id)
+	u.Client.Group.removeUserFromAllGroups(user.Id)
+	u.Client.Application.removeUserFromAllApplications(user.Id)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// LockOutUser simulates the user being locked out after too many failed sign in attempts
+func (u *UserResource) LockOutUser(userID string) error {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	_, _, err := u.transitionUser(userID, "", UserStatusLockedOut, []string{
+		UserStatusActive, UserStatusRecovery, UserStatusPasswordExpired,
+	}, nil)
+	return err
+}
@@ -0,0 +1,216 @@
+package mockokta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+func newUserWithStatus(t *testing.T, client *MockClient, status string) *okta.User {
+	t.Helper()
+	request := NewCreateUserRequest("TestUser@test.com")
+	request.Credentials = &okta.UserCredentials{Password: &okta.PasswordCredential{Value: "Password1!"}}
+	user, _, err := client.User.CreateUser(context.TODO(), request, nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	setUserStatus(user, status)
+	return user
+}
+
+func TestUserResource_CreateUserStatus(t *testing.T) {
+	t.Run("should stage user when activate is false", func(t *testing.T) {
+		client := NewClient()
+
+		user, _, _ := client.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), query.NewQueryParams(query.WithActivate(false)))
+
+		if user.Status != UserStatusStaged || user.Activated != nil {
+			t.Errorf("got status %v want %v", user.Status, UserStatusStaged)
+		}
+	})
+
+	t.Run("should provision user without password", func(t *testing.T) {
+		client := NewClient()
+
+		user, _, _ := client.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		if user.Status != UserStatusProvisioned || user.Activated == nil {
+			t.Errorf("got status %v want %v", user.Status, UserStatusProvisioned)
+		}
+	})
+
+	t.Run("should activate user with password without returning it", func(t *testing.T) {
+		client := NewClient()
+		request := NewCreateUserRequest("TestUser@test.com")
+		request.Credentials = &okta.UserCredentials{Password: &okta.PasswordCredential{Value: "Password1!"}}
+
+		user, _, _ := client.CreateUser(context.TODO(), request, query.NewQueryParams(query.WithActivate(true)))
+
+		if user.Status != UserStatusActive {
+			t.Errorf("got status %v want %v", user.Status, UserStatusActive)
+		}
+		if user.Credentials.Password.Value != "" {
+			t.Errorf("expected password value to be hidden")
+		}
+	})
+
+	t.Run("should require login", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.CreateUser(context.TODO(), okta.CreateUserRequest{Profile: &okta.UserProfile{"email": "TestUser@test.com"}}, nil)
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+	})
+
+	t.Run("should add user to groupIds", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		request := NewCreateUserRequest("TestUser@test.com")
+		request.GroupIds = []string{group.Id}
+
+		user, _, _ := client.CreateUser(context.TODO(), request, nil)
+
+		if !client.Group.GroupContainsUser(*group, (*user.Profile)["email"].(string)) {
+			t.Errorf("expected group %v to contain user %v", group.Profile.Name, user.Id)
+		}
+	})
+
+	t.Run("should err on unknown groupIds", func(t *testing.T) {
+		client := NewClient()
+		request := NewCreateUserRequest("TestUser@test.com")
+		request.GroupIds = []string{"NonExistentId"}
+
+		_, resp, err := client.CreateUser(context.TODO(), request, nil)
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+		if len(client.User.Users) != 0 {
+			t.Errorf("expected user not to be created")
+		}
+	})
+}
+
+func TestUserResource_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       string
+		operation  func(client *MockClient, userID string) (*okta.Response, error)
+		wantStatus string
+		wantCode   string
+	}{
+		{"activate staged", UserStatusStaged, activate, UserStatusActive, ""},
+		{"activate deprovisioned", UserStatusDeprovisioned, activate, UserStatusActive, ""},
+		{"activate active", UserStatusActive, activate, UserStatusActive, ErrorCodeAlreadyActive},
+		{"activate suspended", UserStatusSuspended, activate, UserStatusSuspended, ErrorCodeInvalidStatus},
+		{"deactivate active", UserStatusActive, deactivate, UserStatusDeprovisioned, ""},
+		{"deactivate suspended", UserStatusSuspended, deactivate, UserStatusDeprovisioned, ""},
+		{"deactivate deprovisioned", UserStatusDeprovisioned, deactivate, UserStatusDeprovisioned, ErrorCodeInvalidStatus},
+		{"suspend active", UserStatusActive, suspend, UserStatusSuspended, ""},
+		{"suspend staged", UserStatusStaged, suspend, UserStatusStaged, ErrorCodeValidation},
+		{"unsuspend suspended", UserStatusSuspended, unsuspend, UserStatusActive, ""},
+		{"unsuspend active", UserStatusActive, unsuspend, UserStatusActive, ErrorCodeValidation},
+		{"unlock locked out", UserStatusLockedOut, unlock, UserStatusActive, ""},
+		{"unlock active", UserStatusActive, unlock, UserStatusActive, ErrorCodeUnlockNotAllowed},
+		{"reset password active", UserStatusActive, resetPassword, UserStatusRecovery, ""},
+		{"reset password locked out", UserStatusLockedOut, resetPassword, UserStatusRecovery, ""},
+		{"reset password staged", UserStatusStaged, resetPassword, UserStatusStaged, ErrorCodeInvalidStatus},
+		{"expire password active", UserStatusActive, expirePassword, UserStatusPasswordExpired, ""},
+		{"expire password suspended", UserStatusSuspended, expirePassword, UserStatusSuspended, ErrorCodeInvalidStatus},
+		{"deactivate or delete active", UserStatusActive, deactivateOrDelete, UserStatusDeprovisioned, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient()
+			user := newUserWithStatus(t, client, tt.from)
+
+			resp, err := tt.operation(client, user.Id)
+
+			if tt.wantCode != "" {
+				assertOktaError(t, resp, err, tt.wantCode)
+			} else if err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if user.Status != tt.wantStatus {
+				t.Errorf("got status %v want %v", user.Status, tt.wantStatus)
+			}
+		})
+	}
+
+	t.Run("should delete deprovisioned user and its memberships", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		user := newUserWithStatus(t, client, UserStatusActive)
+		client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+
+		_, err := client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if _, err := client.User.GetUserByID(user.Id); err == nil {
+			t.Errorf("expected user %v to be deleted", user.Id)
+		}
+		if users, _, _ := client.ListGroupUsers(context.TODO(), group.Id, nil); len(users) != 0 {
+			t.Errorf("expected group to be empty but got %v", users)
+		}
+	})
+
+	t.Run("should return activation token when not sending email", func(t *testing.T) {
+		client := NewClient()
+		user := newUserWithStatus(t, client, UserStatusStaged)
+
+		token, _, _ := client.ActivateUser(context.TODO(), user.Id, query.NewQueryParams(query.WithSendEmail(false)))
+
+		if token.ActivationToken == "" || token.ActivationUrl == "" {
+			t.Errorf("expected activation token but got %#v", token)
+		}
+	})
+
+	t.Run("should lock out active user", func(t *testing.T) {
+		client := NewClient()
+		user := newUserWithStatus(t, client, UserStatusActive)
+
+		err := client.User.LockOutUser(user.Id)
+
+		if err != nil || user.Status != UserStatusLockedOut {
+			t.Errorf("got status %v (%v) want %v", user.Status, err, UserStatusLockedOut)
+		}
+	})
+}
+
+func activate(client *MockClient, userID string) (*okta.Response, error) {
+	_, resp, err := client.ActivateUser(context.TODO(), userID, nil)
+	return resp, err
+}
+
+func deactivate(client *MockClient, userID string) (*okta.Response, error) {
+	return client.DeactivateUser(context.TODO(), userID, nil)
+}
+
+func suspend(client *MockClient, userID string) (*okta.Response, error) {
+	return client.SuspendUser(context.TODO(), userID)
+}
+
+func unsuspend(client *MockClient, userID string) (*okta.Response, error) {
+	return client.UnsuspendUser(context.TODO(), userID)
+}
+
+func unlock(client *MockClient, userID string) (*okta.Response, error) {
+	return client.UnlockUser(context.TODO(), userID)
+}
+
+func resetPassword(client *MockClient, userID string) (*okta.Response, error) {
+	_, resp, err := client.ResetPassword(context.TODO(), userID, nil)
+	return resp, err
+}
+
+func expirePassword(client *MockClient, userID string) (*okta.Response, error) {
+	_, resp, err := client.ExpirePassword(context.TODO(), userID)
+	return resp, err
+}
+
+func deactivateOrDelete(client *MockClient, userID string) (*okta.Response, error) {
+	return client.DeactivateOrDeleteUser(context.TODO(), userID, nil)
+}
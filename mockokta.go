@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/okta/okta-sdk-golang/v2/okta"
@@ -12,33 +15,86 @@ import (
 
 var adminRoles = []string{"SUPER_ADMIN", "ORG_ADMIN", "GROUP_ADMIN", "GROUP_MEMBERSHIP_ADMIN", "USER_ADMIN", "APP_ADMIN", "READ_ONLY_ADMIN", "MOBILE_ADMIN", "HELP_DESK_ADMIN", "REPORT_ADMIN", "API_ACCESS_MANAGEMENT_ADMIN", "CUSTOM"}
 
-// MockClient is our client to simulate the okta golang sdk client
+// MockClient is our client to simulate the okta golang sdk client. Its methods are safe for
+// concurrent use, but reading or writing the resources' exported fields directly is not. Neither is
+// changing the groups, users and roles its methods return, which are the mock's own rather than
+// copies like the schemas
 type MockClient struct {
-	Group *GroupResource
-	User  *UserResource
+	Group       *GroupResource
+	User        *UserResource
+	Schema      *SchemaResource
+	Application *ApplicationResource
+	IAM         *IAMResource
+	sdk         *okta.Client
+	ids         *IDGenerator
+	// principal is the ID or login of the admin user the client acts as, see SetPrincipal
+	principal string
+	// faults are the faults injected with InjectFault, calls the calls recorded for Calls, callCounts
+	// the number of calls of each method and rateLimitWindows the requests counted against each of
+	// the rateLimits. They are guarded by callsMu rather than mu so faults can be injected while a
+	// call is waiting
+	faults           []*injectedFault
+	calls            []*Call
+	callCounts       map[string]int
+	rateLimits       []RateLimit
+	rateLimitWindows []rateLimitWindow
+	callsMu          sync.Mutex
+	clock            Clock
+	// mu guards the state of every resource, so calls that read one resource while changing
+	// another, like AddUserToGroup, see a consistent view of both
+	mu sync.RWMutex
 }
 
 // NewClient Creates a New Okta Client with all the necessary attributes
-func NewClient() *MockClient {
-	c := &MockClient{}
+func NewClient(opts ...ClientOption) *MockClient {
+	c := &MockClient{ids: newDefaultIDGenerator(), callCounts: make(map[string]int), clock: systemClock{}}
 	c.Group = &GroupResource{
-		Client:     c,
-		GroupRoles: make(map[string][]*okta.Role),
-		GroupUsers: make(map[string][]string),
+		Client:         c,
+		GroupRoles:     make(map[string][]*okta.Role),
+		RoleTargets:    make(map[string]*RoleTargets),
+		GroupUsers:     make(map[string][]string),
+		GroupRuleUsers: make(map[string]map[string][]string),
 	}
 	c.User = &UserResource{
-		Client: c,
+		Client:      c,
+		UserRoles:   make(map[string][]*okta.Role),
+		RoleTargets: make(map[string]*RoleTargets),
 	}
+	c.Application = &ApplicationResource{
+		Client:    c,
+		AppUsers:  make(map[string][]*okta.AppUser),
+		AppGroups: make(map[string][]*okta.ApplicationGroupAssignment),
+	}
+	c.IAM = &IAMResource{
+		Client:      c,
+		Resources:   make(map[string][]*ResourceSetResource),
+		Assignments: make(map[string]CustomRoleAssignment),
+	}
+	c.Schema = &SchemaResource{
+		Client:      c,
+		UserSchema:  NewDefaultUserSchema(),
+		GroupSchema: NewDefaultGroupSchema(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Group.seedEveryoneGroup()
+	c.sdk = newSDKClient(c)
 	return c
 }
 
-// GroupResource contains all the information to add fake groups, and maps of Group Names
-// to Roles and Users
+// GroupResource contains all the information to add fake groups, and maps of Group IDs
+// to their assigned Roles and member User IDs. RoleTargets maps the IDs of the Roles
+// assigned to groups to the groups and apps they are restricted to, and GroupRuleUsers
+// maps Group Rule IDs to the Group IDs and then the User IDs of the memberships each rule created
 type GroupResource struct {
-	Client     *MockClient
-	Groups     []*okta.Group
-	GroupRoles map[string][]*okta.Role
-	GroupUsers map[string][]string
+	Client         *MockClient
+	Groups         []*okta.Group
+	GroupRoles     map[string][]*okta.Role
+	RoleTargets    map[string]*RoleTargets
+	GroupUsers     map[string][]string
+	GroupRules     []*okta.GroupRule
+	GroupRuleUsers map[string]map[string][]string
 }
 
 // Wrapper methods for Okta API Calls
@@ -50,47 +106,107 @@ func (client *MockClient) Initialize(ctx context.Context, conf ...okta.ConfigSet
 
 // ListGroups is a wrapper to call client.Group.ListGroups to make it easier to match an interface for the okta client
 func (client *MockClient) ListGroups(ctx context.Context, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
-	return client.Group.ListGroups(ctx, qp)
+	return intercept(ctx, client, "ListGroups", []interface{}{qp}, func() ([]*okta.Group, *okta.Response, error) {
+		return client.Group.ListGroups(ctx, qp)
+	})
 }
 
 // ListGroupUsers is a wrapper to call client.Group.ListGroupUsers to make it easier to match an interface for the okta client
 func (client *MockClient) ListGroupUsers(ctx context.Context, groupID string, qp *query.Params) ([]*okta.User, *okta.Response, error) {
-	return client.Group.ListGroupUsers(ctx, groupID, qp)
+	return intercept(ctx, client, "ListGroupUsers", []interface{}{groupID, qp}, func() ([]*okta.User, *okta.Response, error) {
+		return client.Group.ListGroupUsers(ctx, groupID, qp)
+	})
 }
 
 // ListGroupAssignedRoles is a wrapper to call client.Group.ListGroupAssignedRoles to make it easier to match an interface for the okta client
 func (client *MockClient) ListGroupAssignedRoles(ctx context.Context, groupID string, qp *query.Params) ([]*okta.Role, *okta.Response, error) {
-	return client.Group.ListGroupAssignedRoles(ctx, groupID, qp)
+	return intercept(ctx, client, "ListGroupAssignedRoles", []interface{}{groupID, qp}, func() ([]*okta.Role, *okta.Response, error) {
+		return client.Group.ListGroupAssignedRoles(ctx, groupID, qp)
+	})
 }
 
 // CreateGroup is a wrapper to call client.Group.CreateGroup to make it easier to match an interface for the okta client
 func (client *MockClient) CreateGroup(ctx context.Context, group okta.Group) (*okta.Group, *okta.Response, error) {
-	return client.Group.CreateGroup(ctx, group)
+	return intercept(ctx, client, "CreateGroup", []interface{}{group}, func() (*okta.Group, *okta.Response, error) {
+		return client.Group.CreateGroup(ctx, group)
+	})
+}
+
+// GetGroup is a wrapper to call client.Group.GetGroup to make it easier to match an interface for the okta client
+func (client *MockClient) GetGroup(ctx context.Context, groupID string) (*okta.Group, *okta.Response, error) {
+	return intercept(ctx, client, "GetGroup", []interface{}{groupID}, func() (*okta.Group, *okta.Response, error) {
+		return client.Group.GetGroup(ctx, groupID)
+	})
+}
+
+// UpdateGroup is a wrapper to call client.Group.UpdateGroup to make it easier to match an interface for the okta client
+func (client *MockClient) UpdateGroup(ctx context.Context, groupID string, group okta.Group) (*okta.Group, *okta.Response, error) {
+	return intercept(ctx, client, "UpdateGroup", []interface{}{groupID, group}, func() (*okta.Group, *okta.Response, error) {
+		return client.Group.UpdateGroup(ctx, groupID, group)
+	})
 }
 
 // DeleteGroup is a wrapper to call client.Group.DeleteGroup to make it easier to match an interface for the okta client
 func (client *MockClient) DeleteGroup(ctx context.Context, groupID string) (*okta.Response, error) {
-	return client.Group.DeleteGroup(ctx, groupID)
+	return interceptResponse(ctx, client, "DeleteGroup", []interface{}{groupID}, func() (*okta.Response, error) {
+		return client.Group.DeleteGroup(ctx, groupID)
+	})
 }
 
 // AssignRoleToGroup is a wrapper to call client.Group.AssignRoleToGroup to make it easier to match an interface for the okta client
 func (client *MockClient) AssignRoleToGroup(ctx context.Context, groupID string, assignRoleRequest okta.AssignRoleRequest, qp *query.Params) (*okta.Role, *okta.Response, error) {
-	return client.Group.AssignRoleToGroup(ctx, groupID, assignRoleRequest, qp)
+	return intercept(ctx, client, "AssignRoleToGroup", []interface{}{groupID, assignRoleRequest, qp}, func() (*okta.Role, *okta.Response, error) {
+		return client.Group.AssignRoleToGroup(ctx, groupID, assignRoleRequest, qp)
+	})
+}
+
+// CreateUser is a wrapper to call client.User.CreateUser to make it easier to match an interface for the okta client
+func (client *MockClient) CreateUser(ctx context.Context, body okta.CreateUserRequest, qp *query.Params) (*okta.User, *okta.Response, error) {
+	return intercept(ctx, client, "CreateUser", []interface{}{body, qp}, func() (*okta.User, *okta.Response, error) {
+		return client.User.CreateUser(ctx, body, qp)
+	})
+}
+
+// GetUser is a wrapper to call client.User.GetUser to make it easier to match an interface for the okta client
+func (client *MockClient) GetUser(ctx context.Context, userID string) (*okta.User, *okta.Response, error) {
+	return intercept(ctx, client, "GetUser", []interface{}{userID}, func() (*okta.User, *okta.Response, error) {
+		return client.User.GetUser(ctx, userID)
+	})
+}
+
+// UpdateUser is a wrapper to call client.User.UpdateUser to make it easier to match an interface for the okta client
+func (client *MockClient) UpdateUser(ctx context.Context, userID string, body okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	return intercept(ctx, client, "UpdateUser", []interface{}{userID, body, qp}, func() (*okta.User, *okta.Response, error) {
+		return client.User.UpdateUser(ctx, userID, body, qp)
+	})
+}
+
+// PartialUpdateUser is a wrapper to call client.User.PartialUpdateUser to make it easier to match an interface for the okta client
+func (client *MockClient) PartialUpdateUser(ctx context.Context, userID string, body okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	return intercept(ctx, client, "PartialUpdateUser", []interface{}{userID, body, qp}, func() (*okta.User, *okta.Response, error) {
+		return client.User.PartialUpdateUser(ctx, userID, body, qp)
+	})
 }
 
 // ListUsers is a wrapper to call client.Group.ListUsers to make it easier to match an interface for the okta client
 func (client *MockClient) ListUsers(ctx context.Context, qp *query.Params) ([]*okta.User, *okta.Response, error) {
-	return client.User.ListUsers(ctx, qp)
+	return intercept(ctx, client, "ListUsers", []interface{}{qp}, func() ([]*okta.User, *okta.Response, error) {
+		return client.User.ListUsers(ctx, qp)
+	})
 }
 
 // AddUserToGroup is a wrapper to call client.Group.AddUserToGroup to make it easier to match an interface for the okta client
 func (client *MockClient) AddUserToGroup(ctx context.Context, groupID string, userID string) (*okta.Response, error) {
-	return client.Group.AddUserToGroup(ctx, groupID, userID)
+	return interceptResponse(ctx, client, "AddUserToGroup", []interface{}{groupID, userID}, func() (*okta.Response, error) {
+		return client.Group.AddUserToGroup(ctx, groupID, userID)
+	})
 }
 
 // RemoveUserFromGroup is a wrapper to call client.Group.RemoveUserFromGroup to make it easier to match an interface for the okta client
 func (client *MockClient) RemoveUserFromGroup(ctx context.Context, groupID string, userID string) (*okta.Response, error) {
-	return client.Group.RemoveUserFromGroup(ctx, groupID, userID)
+	return interceptResponse(ctx, client, "RemoveUserFromGroup", []interface{}{groupID, userID}, func() (*okta.Response, error) {
+		return client.Group.RemoveUserFromGroup(ctx, groupID, userID)
+	})
 }
 
 // NewGroup will Create a New *okta.Group with the specified Group name
@@ -104,109 +220,249 @@ func NewGroup(groupName string) *okta.Group {
 
 // CreateGroup will add the group to the list of groups
 func (g *GroupResource) CreateGroup(ctx context.Context, group okta.Group) (*okta.Group, *okta.Response, error) {
-	group.Id = fmt.Sprint(len(g.Groups) + 1)
-	for _, x := range g.Groups {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
 
-		if x.Profile.Name == group.Profile.Name {
-			return nil, nil, fmt.Errorf("unable to create group: group exists")
-		}
+	if err := g.Client.authorize("okta.groups.create"); err != nil {
+		return nil, errorResponse(err), err
 	}
+	if err := g.validateGroupProfile("", group.Profile); err != nil {
+		return nil, errorResponse(err), err
+	}
+	created := g.addGroup(group, GroupTypeOkta)
+	return created, newResponse(http.StatusOK, created), nil
+}
+
+// GetGroup returns the group with the groupID
+func (g *GroupResource) GetGroup(ctx context.Context, groupID string) (*okta.Group, *okta.Response, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
 
-	if len(group.Profile.Name) > 255 || len(group.Profile.Name) < 1 {
-		return nil, nil, fmt.Errorf("unable to create group: invalid name length")
+	group, err := g.getGroupByID(groupID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return group, newResponse(http.StatusOK, group), nil
+}
+
+// UpdateGroup replaces the profile of the group with the groupID with the profile of the group
+// passed in, keeping its name unique, and returns the updated group
+func (g *GroupResource) UpdateGroup(ctx context.Context, groupID string, group okta.Group) (*okta.Group, *okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	existing, err := g.getGroupByID(groupID)
+	if err != nil {
+		return nil, errorResponse(err), err
 	}
-	g.Groups = append(g.Groups, &group)
-	return &group, nil, nil
+	if err := g.Client.authorizeGroup("okta.groups.manage", existing.Id); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if err := checkGroupModifiable(existing, "update"); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if err := g.validateGroupProfile(groupID, group.Profile); err != nil {
+		return nil, errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	profile := *group.Profile
+	existing.Profile = &profile
+	existing.LastUpdated = &now
+	return existing, newResponse(http.StatusOK, existing), nil
+}
+
+// validateGroupProfile checks a group profile is valid against the group schema and that its name isn't used by any group
+// other than the one with the groupID
+func (g *GroupResource) validateGroupProfile(groupID string, profile *okta.GroupProfile) *okta.Error {
+	if profile == nil {
+		return newValidationError("profile", "The field cannot be left blank")
+	}
+	if err := g.Client.Schema.validateGroupProfile(*profile); err != nil {
+		return err
+	}
+	for _, x := range g.Groups {
+		if x.Id != groupID && x.Profile.Name == profile.Name {
+			return newValidationError("name", "An object with this field already exists in the current organization")
+		}
+	}
+	return nil
 }
 
 // DeleteGroup will remove a specified group ID from the list of Groups
 func (g *GroupResource) DeleteGroup(ctx context.Context, groupID string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
 	for idx, group := range g.Groups {
 		if group.Id == groupID {
+			if err := g.Client.authorizeGroup("okta.groups.manage", groupID); err != nil {
+				return errorResponse(err), err
+			}
+			if err := checkGroupModifiable(group, "delete"); err != nil {
+				return errorResponse(err), err
+			}
 			g.Groups[idx] = g.Groups[len(g.Groups)-1]
 			g.Groups[len(g.Groups)-1] = &okta.Group{}
 			g.Groups = g.Groups[:len(g.Groups)-1]
-			return nil, nil
+			delete(g.GroupUsers, groupID)
+			for _, assigned := range g.GroupRuleUsers {
+				delete(assigned, groupID)
+			}
+			for _, role := range g.GroupRoles[groupID] {
+				g.Client.forgetRole(role.Id)
+			}
+			delete(g.GroupRoles, groupID)
+			g.Client.IAM.removeResources("groups/" + groupID)
+			g.Client.Application.removeGroupFromAllApplications(groupID)
+			g.Client.removeGroupFromRoleTargets(groupID)
+			g.invalidateGroupRules(groupID)
+			g.applyGroupRules()
+			return newResponse(http.StatusNoContent, nil), nil
 		}
 	}
-	return nil, fmt.Errorf("group not found")
+	err := newNotFoundError("UserGroup", groupID)
+	return errorResponse(err), err
 }
 
-// ListGroups will return a list of all groups
-func (g *GroupResource) ListGroups(context.Context, *query.Params) ([]*okta.Group, *okta.Response, error) {
-	return g.Groups, nil, nil
+// ListGroups will return a page of the groups matching the q, search and filter query params
+func (g *GroupResource) ListGroups(ctx context.Context, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	groups, err := matchGroups(g.Groups, qp)
+	if err != nil {
+		err := NewError(ErrorCodeInvalidSearch, err.Error())
+		return nil, errorResponse(err), err
+	}
+	page, after := paginate(groups, func(group *okta.Group) string { return group.Id }, qp)
+	return page, g.Client.listResponse(ctx, "/api/v1/groups", qp, after), nil
 }
 
 // AddUserToGroup will take a groupID and userID and add the user to the group
 func (g *GroupResource) AddUserToGroup(ctx context.Context, groupID string, userID string) (*okta.Response, error) {
-	group, err := g.GetGroupByID(groupID)
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorizeGroup("okta.groups.members.manage", groupID); err != nil {
+		return errorResponse(err), err
+	}
+	resp, err := g.addUserToGroup(groupID, userID)
+	g.applyGroupRules()
+	return resp, err
+}
+
+func (g *GroupResource) addUserToGroup(groupID string, userID string) (*okta.Response, error) {
+	group, err := g.getGroupByID(groupID)
 	if err != nil {
-		return nil, err
+		return errorResponse(err), err
+	}
+	if err := checkGroupModifiable(group, "add users to"); err != nil {
+		return errorResponse(err), err
 	}
-	user, err := g.Client.User.GetUserByID(userID)
+	user, err := g.Client.User.getUserByID(userID)
 	if err != nil {
-		return nil, err
+		return errorResponse(err), err
 	}
 
-	g.GroupUsers[group.Profile.Name] = append(g.GroupUsers[group.Profile.Name], (*user.Profile)["email"].(string))
+	if !SliceContainsString(g.GroupUsers[group.Id], user.Id) {
+		g.GroupUsers[group.Id] = append(g.GroupUsers[group.Id], user.Id)
+		membershipUpdated(group)
+	}
 
-	return nil, nil
+	return newResponse(http.StatusNoContent, nil), nil
 }
 
-// RemoveUserFromGroup will take a groupID and userID and remove the user from the group
+// RemoveUserFromGroup will take a groupID and userID and remove the user from the group. Like Okta,
+// users an active group rule assigns to the group can't be removed while they match it
 func (g *GroupResource) RemoveUserFromGroup(ctx context.Context, groupID string, userID string) (*okta.Response, error) {
-	group, err := g.GetGroupByID(groupID)
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	group, err := g.getGroupByID(groupID)
 	if err != nil {
-		return nil, err
+		return errorResponse(err), err
+	}
+	if err := g.Client.authorizeGroup("okta.groups.members.manage", group.Id); err != nil {
+		return errorResponse(err), err
 	}
-	groupName := group.Profile.Name
-	user, err := g.Client.User.GetUserByID(userID)
+	if err := checkGroupModifiable(group, "remove users from"); err != nil {
+		return errorResponse(err), err
+	}
+	user, err := g.Client.User.getUserByID(userID)
 	if err != nil {
-		return nil, err
+		return errorResponse(err), err
 	}
-	userEmail := (*user.Profile)["email"].(string)
 
-	for idx, u := range g.GroupUsers[groupName] {
-		if u == userEmail {
-			g.GroupUsers[groupName][idx] = g.GroupUsers[groupName][len(g.GroupUsers[groupName])-1]
-			g.GroupUsers[groupName][len(g.GroupUsers[groupName])-1] = ""
-			g.GroupUsers[groupName] = g.GroupUsers[groupName][:len(g.GroupUsers[groupName])-1]
+	if rule := g.ruleAssigningUser(group.Id, user); rule != nil {
+		err := NewError(ErrorCodeUnsupportedOperation, fmt.Sprintf("The user is assigned to the group by the group rule %v.", rule.Name))
+		return errorResponse(err), err
+	}
+
+	if SliceContainsString(g.GroupUsers[group.Id], user.Id) {
+		g.GroupUsers[group.Id] = removeString(g.GroupUsers[group.Id], user.Id)
+		for _, assigned := range g.GroupRuleUsers {
+			if userIDs, ok := assigned[group.Id]; ok {
+				assigned[group.Id] = removeString(userIDs, user.Id)
+			}
 		}
+		membershipUpdated(group)
+		g.applyGroupRules()
 	}
-	return nil, nil
+	return newResponse(http.StatusNoContent, nil), nil
 }
 
-// AssignRoleToGroup will assigned the role in the assignRoleRequest to the group specified by ID, and return the role it assigned
+// AssignRoleToGroup will assigned the role in the assignRoleRequest to the group specified by ID, and return the role it assigned.
+// CUSTOM roles need the custom role and resource set of WithCustomRoleAssignment on the ctx
 func (g *GroupResource) AssignRoleToGroup(ctx context.Context, groupID string, assignRoleRequest okta.AssignRoleRequest, qp *query.Params) (*okta.Role, *okta.Response, error) {
-	if !SliceContainsString(adminRoles, assignRoleRequest.Type) {
-		return nil, nil, fmt.Errorf("invalid role")
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if request, ok := customRoleAssignment(ctx); ok && assignRoleRequest.Type == CustomRoleType {
+		return g.assignCustomRoleToGroup(groupID, request)
+	}
+	role, roleErr := g.Client.newRoleAssignment(assignRoleRequest, RoleAssignmentTypeGroup)
+	if roleErr != nil {
+		return nil, errorResponse(roleErr), roleErr
 	}
-	group, err := g.GetGroupByID(groupID)
+	group, err := g.getGroupByID(groupID)
 	if err != nil {
-		return nil, nil, err
+		return nil, errorResponse(err), err
 	}
-	if g.GroupContainsRole(*group, assignRoleRequest.Type) {
-		return nil, nil, fmt.Errorf("group role exists")
+	if g.groupContainsRole(*group, assignRoleRequest.Type) {
+		err := NewError(ErrorCodeDuplicateRole, "The role specified is already assigned to the group.")
+		return nil, errorResponse(err), err
 	}
-	role := NewRole(assignRoleRequest.Type)
-	role.Id = fmt.Sprintf("%v", len(g.GroupRoles)+1)
-	g.GroupRoles[group.Profile.Name] = append(g.GroupRoles[group.Profile.Name], &role)
-	return &role, nil, nil
+	g.GroupRoles[group.Id] = append(g.GroupRoles[group.Id], role)
+	return role, newResponse(http.StatusCreated, role), nil
 }
 
 // ListGroupAssignedRoles will list all the roles for a specified groupID
 func (g *GroupResource) ListGroupAssignedRoles(ctx context.Context, groupID string, qp *query.Params) ([]*okta.Role, *okta.Response, error) {
 
-	group, _ := g.GetGroupByID(groupID)
-	roles := make([]*okta.Role, 0)
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
 
-	roles = append(roles, g.GroupRoles[group.Profile.Name]...)
-	return roles, nil, nil
+	group, err := g.getGroupByID(groupID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	roles, after := paginate(g.GroupRoles[group.Id], func(role *okta.Role) string { return role.Id }, qp)
+	return roles, g.Client.listResponse(ctx, fmt.Sprintf("/api/v1/groups/%v/roles", groupID), qp, after), nil
 }
 
 // GroupContainsRole will search a group for a certain role and return a boolean of it found it
 func (g *GroupResource) GroupContainsRole(group okta.Group, roleType string) bool {
-	for _, groupRole := range g.GroupRoles[group.Profile.Name] {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	return g.groupContainsRole(group, roleType)
+}
+
+func (g *GroupResource) groupContainsRole(group okta.Group, roleType string) bool {
+	for _, groupRole := range g.GroupRoles[group.Id] {
 
 		if groupRole.Type == roleType {
 			return true
@@ -217,95 +473,295 @@ func (g *GroupResource) GroupContainsRole(group okta.Group, roleType string) boo
 
 // ListGroupUsers will return a slice of all users in the specified group
 func (g *GroupResource) ListGroupUsers(ctx context.Context, groupID string, qp *query.Params) ([]*okta.User, *okta.Response, error) {
-	group, _ := g.GetGroupByID(groupID)
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	group, err := g.getGroupByID(groupID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
 	users := make([]*okta.User, 0)
-	for _, user := range g.GroupUsers[group.Profile.Name] {
-		user, _ := g.Client.User.GetUserByEmail(user)
-		users = append(users, user)
+	for _, userID := range g.GroupUsers[group.Id] {
+		if user, err := g.Client.User.getUserByID(userID); err == nil {
+			users = append(users, user)
+		}
 	}
-	return users, nil, nil
+	users, after := paginate(users, func(user *okta.User) string { return user.Id }, qp)
+	return users, g.Client.listResponse(ctx, fmt.Sprintf("/api/v1/groups/%v/users", groupID), qp, after), nil
 }
 
 // GroupContainsUser will search a group for a user by email and return a boolean indicating
 // if it found the user or not
 func (g *GroupResource) GroupContainsUser(group okta.Group, userEmail string) bool {
-	for _, groupUser := range g.GroupUsers[group.Profile.Name] {
-		if groupUser == userEmail {
-			return true
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	return g.groupContainsUser(group, userEmail)
+}
+
+func (g *GroupResource) groupContainsUser(group okta.Group, userEmail string) bool {
+	user, err := g.Client.User.getUserByEmail(userEmail)
+	if err != nil {
+		return false
+	}
+	return SliceContainsString(g.GroupUsers[group.Id], user.Id)
+}
+
+// removeUserFromAllGroups removes the user's memberships when it is deleted
+func (g *GroupResource) removeUserFromAllGroups(userID string) {
+	for _, group := range g.Groups {
+		if SliceContainsString(g.GroupUsers[group.Id], userID) {
+			g.GroupUsers[group.Id] = removeString(g.GroupUsers[group.Id], userID)
+			membershipUpdated(group)
 		}
 	}
-	return false
+	for _, assigned := range g.GroupRuleUsers {
+		for groupID, userIDs := range assigned {
+			assigned[groupID] = removeString(userIDs, userID)
+		}
+	}
+	g.applyGroupRules()
+}
+
+// membershipUpdated records that the group's members changed
+func membershipUpdated(group *okta.Group) {
+	now := time.Now().UTC()
+	group.LastMembershipUpdated = &now
 }
 
 // GetGroupByID will search for a group with the specified groupID and return the group
 func (g *GroupResource) GetGroupByID(groupID string) (*okta.Group, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	return g.getGroupByID(groupID)
+}
+
+func (g *GroupResource) getGroupByID(groupID string) (*okta.Group, error) {
 	for _, group := range g.Groups {
 		if group.Id == groupID {
 			return group, nil
 		}
 	}
-	return nil, fmt.Errorf("group not found")
+	return nil, newNotFoundError("UserGroup", groupID)
 }
 
 // GetGroupByName will search for a group with the specified groupName and return the group
 func (g *GroupResource) GetGroupByName(groupName string) (*okta.Group, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
 	for _, group := range g.Groups {
 		if group.Profile.Name == groupName {
 			return group, nil
 		}
 	}
-	return nil, fmt.Errorf("group not found")
+	return nil, newNotFoundError("UserGroup", groupName)
 }
 
-// UserResource contains the simulated Users
+// UserResource contains the simulated Users, a map of User IDs to the Roles assigned to them directly,
+// and a map of the IDs of those Roles to the groups and apps they are restricted to
 type UserResource struct {
-	Client *MockClient
-	Users  []*okta.User
+	Client      *MockClient
+	Users       []*okta.User
+	UserRoles   map[string][]*okta.Role
+	RoleTargets map[string]*RoleTargets
 }
 
-// CreateUser will Create a User with the specified email and return it
-func (u *UserResource) CreateUser(userEmail string) (*okta.User, error) {
-	userID := fmt.Sprint(len(u.Users) + 1)
-	for _, u := range u.Users {
-		if (*u.Profile)["email"] == userEmail {
-			return nil, fmt.Errorf("user exists")
+// CreateUser will create a user from the request and return it. Like Okta the user is STAGED when the
+// activate query param is false, otherwise it is ACTIVE if a password was set or PROVISIONED if not
+func (u *UserResource) CreateUser(ctx context.Context, body okta.CreateUserRequest, qp *query.Params) (*okta.User, *okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize("okta.users.create"); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if body.Profile == nil {
+		err := newValidationError("profile", "The field cannot be left blank")
+		return nil, errorResponse(err), err
+	}
+	if err := u.validateProfile("", *body.Profile); err != nil {
+		return nil, errorResponse(err), err
+	}
+	for _, groupID := range body.GroupIds {
+		group, err := u.Client.Group.getGroupByID(groupID)
+		if err != nil {
+			return nil, errorResponse(err), err
+		}
+		if err := u.Client.authorizeGroup("okta.groups.members.manage", group.Id); err != nil {
+			return nil, errorResponse(err), err
 		}
+		if err := checkGroupModifiable(group, "add users to"); err != nil {
+			return nil, errorResponse(err), err
+		}
+	}
+
+	now := time.Now().UTC()
+	profile := okta.UserProfile{}
+	for k, v := range *body.Profile {
+		profile[k] = v
 	}
 	user := &okta.User{
-		Id: userID,
+		Id:          u.Client.ids.NewID(UserIDPrefix),
+		Created:     &now,
+		LastUpdated: &now,
+		Profile:     &profile,
+		Status:      UserStatusStaged,
+		Type:        body.Type,
+		Credentials: &okta.UserCredentials{
+			Provider: &okta.AuthenticationProvider{Name: "OKTA", Type: "OKTA"},
+		},
+	}
+	if body.Credentials != nil && body.Credentials.Password != nil {
+		// Okta never returns the password value, only that one is set
+		user.Credentials.Password = &okta.PasswordCredential{}
+	}
+	if qp == nil || qp.Activate == nil || *qp.Activate {
+		activateUser(user)
+	}
+	u.Users = append(u.Users, user)
+	if everyone := u.Client.Group.everyoneGroup(); everyone != nil {
+		u.Client.Group.GroupUsers[everyone.Id] = append(u.Client.Group.GroupUsers[everyone.Id], user.Id)
+		membershipUpdated(everyone)
+	}
+
+	for _, groupID := range body.GroupIds {
+		u.Client.Group.addUserToGroup(groupID, user.Id)
+	}
+	u.Client.Group.applyGroupRules()
+	return user, newResponse(http.StatusOK, user), nil
+}
+
+// NewCreateUserRequest Creates a new CreateUserRequest with the email as the user's login and email
+func NewCreateUserRequest(userEmail string) okta.CreateUserRequest {
+	return okta.CreateUserRequest{
 		Profile: &okta.UserProfile{
+			"login": userEmail,
 			"email": userEmail,
 		},
 	}
-	u.Users = append(u.Users, user)
-	return user, nil
 }
 
-// ListUsers returns a list of all okta Users
+// ListUsers returns a page of okta Users
 func (u *UserResource) ListUsers(ctx context.Context, qp *query.Params) ([]*okta.User, *okta.Response, error) {
-	users := make([]*okta.User, 0)
-	users = append(users, u.Users...)
-	return users, nil, nil
+	u.Client.mu.RLock()
+	defer u.Client.mu.RUnlock()
+
+	users, after := paginate(u.Users, func(user *okta.User) string { return user.Id }, qp)
+	return users, u.Client.listResponse(ctx, "/api/v1/users", qp, after), nil
+}
+
+// GetUser returns the user with the userID, or with the userID as its login like the Okta API
+func (u *UserResource) GetUser(ctx context.Context, userID string) (*okta.User, *okta.Response, error) {
+	u.Client.mu.RLock()
+	defer u.Client.mu.RUnlock()
+
+	user, err := u.getUser(userID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return user, newResponse(http.StatusOK, user), nil
+}
+
+func (u *UserResource) getUser(userID string) (*okta.User, error) {
+	for _, user := range u.Users {
+		if login, _ := (*user.Profile)["login"].(string); user.Id == userID || strings.EqualFold(login, userID) {
+			return user, nil
+		}
+	}
+	return nil, newNotFoundError("User", userID)
+}
+
+// UpdateUser replaces the profile of the user with the userID, or with the userID as its login, with
+// the profile of the user passed in after validating it against the user schema
+func (u *UserResource) UpdateUser(ctx context.Context, userID string, body okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	profile := okta.UserProfile{}
+	if body.Profile != nil {
+		for k, v := range *body.Profile {
+			profile[k] = v
+		}
+	}
+	return u.updateProfile(userID, profile)
+}
+
+// PartialUpdateUser sets only the attributes in the profile of the user passed in, leaving the rest
+// of the user's profile as it is
+func (u *UserResource) PartialUpdateUser(ctx context.Context, userID string, body okta.User, qp *query.Params) (*okta.User, *okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	user, err := u.getUser(userID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	profile := okta.UserProfile{}
+	for k, v := range *user.Profile {
+		profile[k] = v
+	}
+	if body.Profile != nil {
+		for k, v := range *body.Profile {
+			profile[k] = v
+		}
+	}
+	return u.updateProfile(user.Id, profile)
+}
+
+// updateProfile validates the profile and sets it on the user, then reapplies the group rules as
+// the user's attributes may have changed which rules match it
+func (u *UserResource) updateProfile(userID string, profile okta.UserProfile) (*okta.User, *okta.Response, error) {
+	user, err := u.getUser(userID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	if err := u.Client.authorizeUser("okta.users.userprofile.manage", user.Id); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if err := u.validateProfile(user.Id, profile); err != nil {
+		return nil, errorResponse(err), err
+	}
+	now := time.Now().UTC()
+	user.Profile = &profile
+	user.LastUpdated = &now
+	u.Client.Group.applyGroupRules()
+	return user, newResponse(http.StatusOK, user), nil
 }
 
 // GetUserByEmail searches for a user with the email and returns it
 func (u *UserResource) GetUserByEmail(email string) (*okta.User, error) {
+	u.Client.mu.RLock()
+	defer u.Client.mu.RUnlock()
+
+	return u.getUserByEmail(email)
+}
+
+func (u *UserResource) getUserByEmail(email string) (*okta.User, error) {
 	for _, user := range u.Users {
 		if (*user.Profile)["email"] == email {
 			return user, nil
 		}
 	}
-	return nil, fmt.Errorf("user not found")
+	return nil, newNotFoundError("User", email)
 }
 
 // GetUserByID searches for user by userID and returns it
 func (u *UserResource) GetUserByID(userID string) (*okta.User, error) {
+	u.Client.mu.RLock()
+	defer u.Client.mu.RUnlock()
+
+	return u.getUserByID(userID)
+}
+
+func (u *UserResource) getUserByID(userID string) (*okta.User, error) {
 	for _, user := range u.Users {
 		if user.Id == userID {
 			return user, nil
 		}
 	}
-	return nil, fmt.Errorf("user not found")
+	return nil, newNotFoundError("User", userID)
 }
 
 // NewRole Creates a new okta role and returns it
@@ -332,9 +788,22 @@ func SliceContainsString(slice []string, str string) bool {
 	return false
 }
 
-// RandAdminRoleRequest generates a random role from the valid admin roles
+// removeString returns the slice without any occurrences of str
+func removeString(slice []string, str string) []string {
+	remaining := make([]string, 0, len(slice))
+	for _, s := range slice {
+		if s != str {
+			remaining = append(remaining, s)
+		}
+	}
+	return remaining
+}
+
+// RandAdminRoleRequest generates a random role from the valid standard admin roles, which leaves out
+// CUSTOM since assigning it needs a custom role and resource set
 func RandAdminRoleRequest() okta.AssignRoleRequest {
 	rand.Seed(time.Now().UnixNano())
-	roleRequest := NewAssignRoleRequest(adminRoles[rand.Intn(len(adminRoles))])
+	standardRoles := removeString(adminRoles, CustomRoleType)
+	roleRequest := NewAssignRoleRequest(standardRoles[rand.Intn(len(standardRoles))])
 	return roleRequest
 }
@@ -2,12 +2,15 @@ package mockokta
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -62,6 +65,104 @@ func TestGroupResource_CreateGroup(t *testing.T) {
 			t.Fatalf("got %v want %v", got, want)
 		}
 	})
+
+	t.Run("should set timestamps", func(t *testing.T) {
+		client := NewClient()
+
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		if group.Created == nil || group.LastUpdated == nil || group.LastMembershipUpdated == nil {
+			t.Errorf("got %v want timestamps to be set", group)
+		}
+	})
+}
+
+func TestGroupResource_GetGroup(t *testing.T) {
+	t.Run("should get group", func(t *testing.T) {
+		client := NewClient()
+		want, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		got, _, err := client.Group.GetGroup(context.TODO(), want.Id)
+
+		if err != nil || got != want {
+			t.Errorf("got %v (%v) want %v", got, err, want)
+		}
+	})
+
+	t.Run("should err if group doesn't exist", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.Group.GetGroup(context.TODO(), "NonExistentId")
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
+}
+
+func TestGroupResource_UpdateGroup(t *testing.T) {
+	t.Run("should rename and re-describe group", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		created := *group.LastUpdated
+		update := NewGroup("RenamedGroup")
+		update.Profile.Description = "A renamed group"
+
+		got, _, err := client.Group.UpdateGroup(context.TODO(), group.Id, *update)
+
+		if err != nil || got.Profile.Name != "RenamedGroup" || got.Profile.Description != "A renamed group" {
+			t.Fatalf("got %v (%v) want %v", got, err, update.Profile)
+		}
+		if got.LastUpdated.Before(created) || !got.Created.Equal(created) {
+			t.Errorf("got created %v last updated %v want only last updated to change", got.Created, got.LastUpdated)
+		}
+		if found, _ := client.Group.GetGroupByName("RenamedGroup"); found != group {
+			t.Errorf("expected stored group to be updated")
+		}
+	})
+
+	t.Run("should keep its name", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		update := NewGroup("TestGroup")
+		update.Profile.Description = "A described group"
+
+		_, _, err := client.Group.UpdateGroup(context.TODO(), group.Id, *update)
+
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should not rename to an existing group name", func(t *testing.T) {
+		client := NewClient()
+		client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup1"))
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup2"))
+
+		_, resp, err := client.Group.UpdateGroup(context.TODO(), group.Id, *NewGroup("TestGroup1"))
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+		if group.Profile.Name != "TestGroup2" {
+			t.Errorf("got %v want group to be unchanged", group.Profile.Name)
+		}
+	})
+
+	t.Run("should not update with an invalid profile", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		_, resp, err := client.Group.UpdateGroup(context.TODO(), group.Id, okta.Group{})
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+
+		_, resp, err = client.Group.UpdateGroup(context.TODO(), group.Id, *NewGroup(""))
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+	})
+
+	t.Run("should err if group doesn't exist", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.Group.UpdateGroup(context.TODO(), "NonExistentId", *NewGroup("TestGroup"))
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
 }
 
 func TestGroupResource_ListGroups(t *testing.T) {
@@ -74,10 +175,50 @@ func TestGroupResource_ListGroups(t *testing.T) {
 
 	client.Group.Groups = append(client.Group.Groups, group1, group2)
 
-	want := []*okta.Group{group1, group2}
+	want := []*okta.Group{client.Group.EveryoneGroup(), group1, group2}
 	got, _, _ := client.Group.ListGroups(context.TODO(), nil)
 
 	assert.ElementsMatch(t, got, want)
+
+	t.Run("should match q as a name prefix", func(t *testing.T) {
+		client := NewClient()
+		eng, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("eng-backend"))
+		client.Group.CreateGroup(context.TODO(), *NewGroup("ops-eng"))
+
+		want := []*okta.Group{eng}
+		got, _, _ := client.Group.ListGroups(context.TODO(), query.NewQueryParams(query.WithQ("ENG-")))
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("should match search and filter expressions", func(t *testing.T) {
+		client := NewClient()
+		eng, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("eng"))
+		client.Group.CreateGroup(context.TODO(), *NewGroup("ops"))
+		eng.Type = "OKTA_GROUP"
+
+		want := []*okta.Group{eng}
+		got, _, _ := client.Group.ListGroups(context.TODO(), query.NewQueryParams(
+			query.WithSearch(`profile.name eq "eng" or profile.name eq "sales"`),
+			query.WithFilter(`type eq "OKTA_GROUP"`),
+		))
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("should err on invalid search", func(t *testing.T) {
+		client := NewClient()
+
+		_, _, err := client.Group.ListGroups(context.TODO(), query.NewQueryParams(query.WithSearch(`profile.name is "eng"`)))
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
 }
 
 func TestGroupResource_AssignRoleToGroup(t *testing.T) {
@@ -188,8 +329,8 @@ func TestUserResource_CreateUser(t *testing.T) {
 
 		client := NewClient()
 
-		client.User.CreateUser(userEmail)
-		_, err := client.User.CreateUser(userEmail)
+		client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmail), nil)
+		_, _, err := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmail), nil)
 
 		if err == nil {
 			t.Errorf("expected error but didn't get one")
@@ -201,7 +342,7 @@ func TestUserResource_CreateUser(t *testing.T) {
 
 		client := NewClient()
 
-		want, _ := client.User.CreateUser(userEmail)
+		want, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmail), nil)
 		got, _ := client.User.GetUserByEmail(userEmail)
 
 		if !reflect.DeepEqual(got, want) {
@@ -211,11 +352,36 @@ func TestUserResource_CreateUser(t *testing.T) {
 }
 
 func TestGroupResource_AddUserToGroup(t *testing.T) {
+	t.Run("should update the group's last membership update", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		created := *group.LastMembershipUpdated
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		time.Sleep(time.Millisecond)
+
+		client.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
+
+		if !group.LastMembershipUpdated.After(created) || !group.LastUpdated.Equal(created) {
+			t.Errorf("got last membership updated %v last updated %v", group.LastMembershipUpdated, group.LastUpdated)
+		}
+	})
+	t.Run("should not add a user twice", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		client.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		client.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
+
+		if got := client.Group.GroupUsers[group.Id]; !reflect.DeepEqual(got, []string{user.Id}) {
+			t.Errorf("got %v want %v", got, []string{user.Id})
+		}
+	})
 	t.Run("should err if group doesn't exist", func(t *testing.T) {
 		userEmailArg := "TestUser@test.com"
 
 		client := NewClient()
-		user, _ := client.User.CreateUser(userEmailArg)
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg), nil)
 
 		_, err := client.Group.AddUserToGroup(context.TODO(), "1", user.Id)
 
@@ -242,7 +408,7 @@ func TestGroupResource_AddUserToGroup(t *testing.T) {
 
 		client := NewClient()
 		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup(groupNameArg))
-		user, _ := client.User.CreateUser(userEmailArg)
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg), nil)
 
 		client.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
 
@@ -256,7 +422,7 @@ func TestGroupResource_RemoveUserFromGroup(t *testing.T) {
 		userEmailArg := "TestUser@test.com"
 
 		client := NewClient()
-		user, _ := client.User.CreateUser(userEmailArg)
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg), nil)
 
 		_, err := client.Group.RemoveUserFromGroup(context.TODO(), "1", user.Id)
 
@@ -283,7 +449,7 @@ func TestGroupResource_RemoveUserFromGroup(t *testing.T) {
 
 		client := NewClient()
 		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup(groupNameArg))
-		user, _ := client.User.CreateUser(userEmailArg)
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg), nil)
 		client.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
 
 		client.Group.RemoveUserFromGroup(context.TODO(), group.Id, user.Id)
@@ -301,9 +467,9 @@ func TestGroupResource_RemoveUserFromGroup(t *testing.T) {
 
 		client := NewClient()
 		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup(groupNameArg))
-		user1, _ := client.User.CreateUser(userEmailArg1)
-		user2, _ := client.User.CreateUser(userEmailArg2)
-		user3, _ := client.User.CreateUser(userEmailArg3)
+		user1, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg1), nil)
+		user2, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg2), nil)
+		user3, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg3), nil)
 
 		client.Group.AddUserToGroup(context.TODO(), group.Id, user1.Id)
 		client.Group.AddUserToGroup(context.TODO(), group.Id, user2.Id)
@@ -312,7 +478,7 @@ func TestGroupResource_RemoveUserFromGroup(t *testing.T) {
 		client.Group.RemoveUserFromGroup(context.TODO(), group.Id, user2.Id)
 
 		want := 2
-		got := len(client.Group.GroupUsers[group.Profile.Name])
+		got := len(client.Group.GroupUsers[group.Id])
 
 		if got != want {
 			t.Errorf("expected group %v to have %d users but found %d", groupNameArg, want, got)
@@ -340,7 +506,7 @@ func TestGroupResource_DeleteGroup(t *testing.T) {
 
 		client.Group.DeleteGroup(context.TODO(), group.Id)
 
-		want := []*okta.Group{}
+		want := []*okta.Group{client.Group.EveryoneGroup()}
 		got, _, _ := client.Group.ListGroups(context.TODO(), nil)
 
 		if !reflect.DeepEqual(got, want) {
@@ -360,7 +526,7 @@ func TestGroupResource_DeleteGroup(t *testing.T) {
 
 		client.Group.DeleteGroup(context.TODO(), group2.Id)
 
-		want := []*okta.Group{group1, group3}
+		want := []*okta.Group{client.Group.EveryoneGroup(), group1, group3}
 		got, _, _ := client.Group.ListGroups(context.TODO(), nil)
 
 		if !reflect.DeepEqual(got, want) {
@@ -368,10 +534,45 @@ func TestGroupResource_DeleteGroup(t *testing.T) {
 		}
 
 	})
+	t.Run("should not keep members and roles for a recreated group", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		client.Group.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+		client.Group.DeleteGroup(context.TODO(), group.Id)
+
+		deleted := group.Id
+		group, _, _ = client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		users, _, _ := client.Group.ListGroupUsers(context.TODO(), group.Id, nil)
+		roles, _, _ := client.Group.ListGroupAssignedRoles(context.TODO(), group.Id, nil)
+
+		if len(users) != 0 || len(roles) != 0 {
+			t.Errorf("got users %v and roles %v want none", users, roles)
+		}
+		_, hasUsers := client.Group.GroupUsers[deleted]
+		_, hasRoles := client.Group.GroupRoles[deleted]
+		if hasUsers || hasRoles {
+			t.Errorf("expected deleted group's memberships and roles to be removed")
+		}
+	})
 
 }
 
 func TestGroupResource_ListGroupUsers(t *testing.T) {
+	t.Run("should keep members whose email changed", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
+
+		(*user.Profile)["email"] = "Renamed@test.com"
+		got, _, _ := client.Group.ListGroupUsers(context.TODO(), group.Id, nil)
+
+		if len(got) != 1 || got[0] != user || !client.Group.GroupContainsUser(*group, "Renamed@test.com") {
+			t.Errorf("got %v want %v", got, user)
+		}
+	})
 	t.Run("list existing users", func(t *testing.T) {
 		userEmailArg1 := "TestUser1@test.com"
 		userEmailArg2 := "TestUser2@test.com"
@@ -381,9 +582,9 @@ func TestGroupResource_ListGroupUsers(t *testing.T) {
 
 		client := NewClient()
 		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup(groupNameArg))
-		user1, _ := client.User.CreateUser(userEmailArg1)
-		user2, _ := client.User.CreateUser(userEmailArg2)
-		user3, _ := client.User.CreateUser(userEmailArg3)
+		user1, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg1), nil)
+		user2, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg2), nil)
+		user3, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg3), nil)
 
 		client.Group.AddUserToGroup(context.TODO(), group.Id, user1.Id)
 		client.Group.AddUserToGroup(context.TODO(), group.Id, user2.Id)
@@ -418,8 +619,8 @@ func TestUserResource_ListUsers(t *testing.T) {
 		userEmailArg1 := "TestUser1"
 		userEmailArg2 := "TestUser2"
 
-		user1, _ := client.User.CreateUser(userEmailArg1)
-		user2, _ := client.User.CreateUser(userEmailArg2)
+		user1, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg1), nil)
+		user2, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(userEmailArg2), nil)
 
 		want := []*okta.User{user1, user2}
 		got, _, _ := client.User.ListUsers(context.TODO(), nil)
@@ -440,6 +641,60 @@ func TestUserResource_ListUsers(t *testing.T) {
 
 }
 
+func TestMockClient_Concurrency(t *testing.T) {
+	t.Run("should add users to a group from many goroutines", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		users := make([]*okta.User, 50)
+		for i := range users {
+			users[i], _, _ = client.User.CreateUser(context.TODO(), NewCreateUserRequest(fmt.Sprintf("TestUser%d@test.com", i)), nil)
+		}
+
+		var wg sync.WaitGroup
+		for _, user := range users {
+			wg.Add(1)
+			go func(user *okta.User) {
+				defer wg.Done()
+				client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+				client.ListGroupUsers(context.TODO(), group.Id, nil)
+				client.Group.GroupContainsUser(*group, (*user.Profile)["email"].(string))
+			}(user)
+		}
+		wg.Wait()
+
+		got, _, _ := client.ListGroupUsers(context.TODO(), group.Id, nil)
+		if len(got) != len(users) {
+			t.Errorf("got %v users want %v", len(got), len(users))
+		}
+	})
+
+	t.Run("should create and list groups and users from many goroutines", func(t *testing.T) {
+		client := NewClient()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				group, _, _ := client.CreateGroup(context.TODO(), *NewGroup(fmt.Sprintf("TestGroup%d", i)))
+				client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+				client.ListGroupAssignedRoles(context.TODO(), group.Id, nil)
+				client.ListGroups(context.TODO(), nil)
+				user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(fmt.Sprintf("TestUser%d@test.com", i)), nil)
+				client.User.ListUsers(context.TODO(), nil)
+				client.DeactivateUser(context.TODO(), user.Id, nil)
+			}(i)
+		}
+		wg.Wait()
+
+		groups, _, _ := client.ListGroups(context.TODO(), nil)
+		users, _, _ := client.User.ListUsers(context.TODO(), nil)
+		if len(groups) != 51 || len(users) != 50 {
+			t.Errorf("got %v groups and %v users want 50 users and 50 groups plus Everyone", len(groups), len(users))
+		}
+	})
+}
+
 var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
 func RandStringRunes(n int) string {
@@ -0,0 +1,274 @@
+package mockokta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// ImportUsers adds the users of a dump of the Okta API's /api/v1/users to the org as they are,
+// keeping their IDs, statuses, timestamps and profiles. The dump is a JSON array of users, or the
+// arrays of several pages one after the other. Profiles aren't validated against the user schema, as
+// a real org's users may have attributes it doesn't have. Importing isn't restricted by the principal
+func (u *UserResource) ImportUsers(r io.Reader) error {
+	users, err := decodePages[*okta.User](r)
+	if err != nil {
+		return fmt.Errorf("reading users: %w", err)
+	}
+
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	for _, user := range users {
+		if err := u.importUser(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importUser adds the user from a dump to the org and the Everyone group
+func (u *UserResource) importUser(user *okta.User) error {
+	if user.Id == "" {
+		return fmt.Errorf("user %q: missing id", userLogin(user))
+	}
+	if existing, _ := u.getUser(user.Id); existing != nil {
+		return fmt.Errorf("user %v: already exists", user.Id)
+	}
+	if existing, _ := u.getUser(userLogin(user)); existing != nil {
+		return fmt.Errorf("user %v: login %q is already taken by user %v", user.Id, userLogin(user), existing.Id)
+	}
+	if user.Profile == nil {
+		user.Profile = &okta.UserProfile{}
+	}
+	u.Users = append(u.Users, user)
+	u.Client.ids.reserve(user.Id)
+	if everyone := u.Client.Group.everyoneGroup(); everyone != nil {
+		u.Client.Group.GroupUsers[everyone.Id] = append(u.Client.Group.GroupUsers[everyone.Id], user.Id)
+	}
+	return nil
+}
+
+// ImportGroups adds the groups of a dump of the Okta API's /api/v1/groups to the org as they are,
+// keeping their IDs, types and timestamps. The org's Everyone group is replaced by the one of the
+// dump, keeping its members
+func (g *GroupResource) ImportGroups(r io.Reader) error {
+	groups, err := decodePages[*okta.Group](r)
+	if err != nil {
+		return fmt.Errorf("reading groups: %w", err)
+	}
+
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	for _, group := range groups {
+		if err := g.importGroup(group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importGroup adds the group from a dump to the org, or puts it in place of the Everyone group
+func (g *GroupResource) importGroup(group *okta.Group) error {
+	if group.Id == "" || group.Profile == nil {
+		return fmt.Errorf("group %q: missing id or profile", group.Id)
+	}
+	if existing, _ := g.getGroupByID(group.Id); existing != nil {
+		return fmt.Errorf("group %v: already exists", group.Id)
+	}
+	g.Client.ids.reserve(group.Id)
+	if everyone := g.everyoneGroup(); everyone != nil && group.Type == GroupTypeBuiltIn && group.Profile.Name == EveryoneGroupName {
+		g.replaceGroup(everyone, group)
+		return nil
+	}
+	g.Groups = append(g.Groups, group)
+	return nil
+}
+
+// replaceGroup puts the group in place of the existing one, moving its members and roles
+func (g *GroupResource) replaceGroup(existing *okta.Group, group *okta.Group) {
+	for i := range g.Groups {
+		if g.Groups[i] == existing {
+			g.Groups[i] = group
+		}
+	}
+	if userIDs, ok := g.GroupUsers[existing.Id]; ok {
+		g.GroupUsers[group.Id] = userIDs
+		delete(g.GroupUsers, existing.Id)
+	}
+	if roles, ok := g.GroupRoles[existing.Id]; ok {
+		g.GroupRoles[group.Id] = roles
+		delete(g.GroupRoles, existing.Id)
+	}
+}
+
+// ImportGroupUsers adds the members of a dump of the Okta API's /api/v1/groups/{groupID}/users to
+// the imported group with the groupID. Members the org doesn't have yet are imported like ImportUsers
+func (g *GroupResource) ImportGroupUsers(groupID string, r io.Reader) error {
+	users, err := decodePages[*okta.User](r)
+	if err != nil {
+		return fmt.Errorf("reading users of group %v: %w", groupID, err)
+	}
+
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if _, err := g.getGroupByID(groupID); err != nil {
+		return fmt.Errorf("group %v: %w", groupID, err)
+	}
+	for _, user := range users {
+		if existing, _ := g.Client.User.getUserByID(user.Id); existing == nil {
+			if err := g.Client.User.importUser(user); err != nil {
+				return err
+			}
+		}
+		if !SliceContainsString(g.GroupUsers[groupID], user.Id) {
+			g.GroupUsers[groupID] = append(g.GroupUsers[groupID], user.Id)
+		}
+	}
+	return nil
+}
+
+// ImportGroupRoles adds the role assignments of a dump of the Okta API's
+// /api/v1/groups/{groupID}/roles to the imported group with the groupID, keeping their IDs and
+// timestamps. CUSTOM role assignments are imported without their custom role and resource set, so
+// they grant no permissions. The roles come without their targets, and a role without targets
+// manages the whole org when the client has a principal, so import the group targets of the roles
+// Okta restricts with ImportGroupRoleTargets. App targets aren't imported
+func (g *GroupResource) ImportGroupRoles(groupID string, r io.Reader) error {
+	roles, err := decodePages[*okta.Role](r)
+	if err != nil {
+		return fmt.Errorf("reading roles of group %v: %w", groupID, err)
+	}
+
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if _, err := g.getGroupByID(groupID); err != nil {
+		return fmt.Errorf("group %v: %w", groupID, err)
+	}
+	for _, role := range roles {
+		if role.Id == "" {
+			return fmt.Errorf("role %v of group %v: missing id", role.Type, groupID)
+		}
+		if _, err := g.getGroupRole(groupID, role.Id); err == nil {
+			return fmt.Errorf("role %v of group %v: already exists", role.Id, groupID)
+		}
+		g.GroupRoles[groupID] = append(g.GroupRoles[groupID], role)
+		g.Client.ids.reserve(role.Id)
+	}
+	return nil
+}
+
+// ImportGroupRoleTargets restricts the imported role assignment with the roleID of the group with the
+// groupID to the groups of a dump of the Okta API's /api/v1/groups/{groupID}/roles/{roleID}/targets/groups.
+// Target groups the org doesn't have yet are imported like ImportGroups
+func (g *GroupResource) ImportGroupRoleTargets(groupID string, roleID string, r io.Reader) error {
+	groups, err := decodePages[*okta.Group](r)
+	if err != nil {
+		return fmt.Errorf("reading targets of role %v of group %v: %w", roleID, groupID, err)
+	}
+
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	role, roleErr := g.getGroupRole(groupID, roleID)
+	if roleErr != nil {
+		return fmt.Errorf("role %v of group %v: %w", roleID, groupID, roleErr)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	if err := checkTargetSupported(role, groupTargetRoles, "group"); err != nil {
+		return fmt.Errorf("role %v of group %v: %w", roleID, groupID, err)
+	}
+	t := roleTargets(role, g.RoleTargets)
+	for _, group := range groups {
+		if existing, _ := g.getGroupByID(group.Id); existing == nil {
+			if err := g.importGroup(group); err != nil {
+				return err
+			}
+		}
+		if !SliceContainsString(t.Groups, group.Id) {
+			t.Groups = append(t.Groups, group.Id)
+		}
+	}
+	return nil
+}
+
+// NewClientFromOktaExport creates a client with the options and the org of an export of the Okta API
+// in the directory, laid out like the API's paths:
+//
+//	groups.json                                     /api/v1/groups
+//	users.json                                      /api/v1/users
+//	groups/{id}/users.json                          /api/v1/groups/{id}/users
+//	groups/{id}/roles.json                          /api/v1/groups/{id}/roles
+//	groups/{id}/roles/{roleId}/targets/groups.json  /api/v1/groups/{id}/roles/{roleId}/targets/groups
+//
+// Every file is optional, so an export of only users and groups can be imported too
+func NewClientFromOktaExport(dir string, opts ...ClientOption) (*MockClient, error) {
+	client := NewClient(opts...)
+	imports := []func(r io.Reader) error{client.Group.ImportGroups, client.User.ImportUsers}
+	for i, name := range []string{"groups.json", "users.json"} {
+		if err := importFile(filepath.Join(dir, name), imports[i]); err != nil {
+			return nil, err
+		}
+	}
+	for _, group := range client.Group.Groups {
+		groupID := group.Id
+		importUsers := func(r io.Reader) error { return client.Group.ImportGroupUsers(groupID, r) }
+		if err := importFile(filepath.Join(dir, "groups", groupID, "users.json"), importUsers); err != nil {
+			return nil, err
+		}
+		importRoles := func(r io.Reader) error { return client.Group.ImportGroupRoles(groupID, r) }
+		if err := importFile(filepath.Join(dir, "groups", groupID, "roles.json"), importRoles); err != nil {
+			return nil, err
+		}
+		for _, role := range client.Group.GroupRoles[groupID] {
+			roleID := role.Id
+			importTargets := func(r io.Reader) error { return client.Group.ImportGroupRoleTargets(groupID, roleID, r) }
+			if err := importFile(filepath.Join(dir, "groups", groupID, "roles", roleID, "targets", "groups.json"), importTargets); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return client, nil
+}
+
+// importFile imports the file at the path if it exists
+func importFile(path string, importFunc func(r io.Reader) error) error {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := importFunc(f); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// decodePages decodes a JSON array, or the JSON arrays of several pages one after the other
+func decodePages[T any](r io.Reader) ([]T, error) {
+	items := make([]T, 0)
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var page []T
+		if err := decoder.Decode(&page); err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+	}
+	return items, nil
+}
@@ -0,0 +1,307 @@
+package mockokta
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testExportGroups = `[
+  {
+    "id": "00g1everyone0000000",
+    "created": "2019-02-06T16:17:40.000Z",
+    "lastUpdated": "2019-02-06T16:17:40.000Z",
+    "lastMembershipUpdated": "2024-05-01T10:00:00.000Z",
+    "objectClass": ["okta:user_group"],
+    "type": "BUILT_IN",
+    "profile": {"name": "Everyone", "description": "All users in your organization"},
+    "_links": {"users": {"href": "https://test.okta.com/api/v1/groups/00g1everyone0000000/users"}}
+  },
+  {
+    "id": "00g1admins000000000",
+    "created": "2020-03-10T08:00:00.000Z",
+    "lastUpdated": "2021-01-01T00:00:00.000Z",
+    "lastMembershipUpdated": "2024-05-01T10:00:00.000Z",
+    "objectClass": ["okta:user_group"],
+    "type": "OKTA_GROUP",
+    "profile": {"name": "Admins", "description": "Org admins"}
+  }
+]`
+
+const testExportUsers = `[
+  {
+    "id": "00u1alice000000000",
+    "status": "ACTIVE",
+    "created": "2020-03-10T08:00:00.000Z",
+    "activated": "2020-03-10T08:05:00.000Z",
+    "statusChanged": "2020-03-10T08:05:00.000Z",
+    "lastLogin": "2024-05-01T09:00:00.000Z",
+    "lastUpdated": "2023-07-01T12:00:00.000Z",
+    "passwordChanged": "2020-03-10T08:05:00.000Z",
+    "type": {"id": "oty1default0000000"},
+    "profile": {"login": "alice@test.com", "email": "alice@test.com", "firstName": "Alice", "costCenter": "R&D", "badgeNumber": 42},
+    "credentials": {"provider": {"type": "OKTA", "name": "OKTA"}}
+  }
+]
+[
+  {
+    "id": "00u1bob00000000000",
+    "status": "SUSPENDED",
+    "created": "2021-01-01T00:00:00.000Z",
+    "statusChanged": "2022-01-01T00:00:00.000Z",
+    "lastUpdated": "2022-01-01T00:00:00.000Z",
+    "profile": {"login": "bob@test.com", "email": "bob@test.com"}
+  }
+]`
+
+const testExportGroupRoles = `[
+  {
+    "id": "ra1admins000000000",
+    "label": "Super Administrator",
+    "type": "SUPER_ADMIN",
+    "status": "ACTIVE",
+    "created": "2020-03-10T08:00:00.000Z",
+    "lastUpdated": "2020-03-10T08:00:00.000Z",
+    "assignmentType": "GROUP"
+  }
+]`
+
+const testExportGroupAdminRoles = `[
+  {
+    "id": "ra1groupadmin00000",
+    "label": "Group Administrator",
+    "type": "GROUP_ADMIN",
+    "status": "ACTIVE",
+    "assignmentType": "GROUP"
+  }
+]`
+
+const testExportRoleTargets = `[
+  {
+    "id": "00g1support00000000",
+    "type": "OKTA_GROUP",
+    "profile": {"name": "Support", "description": "Support engineers"}
+  }
+]`
+
+// writeTestExport writes the files of an Okta export to a temporary directory
+func writeTestExport(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	return dir
+}
+
+func TestUserResource_ImportUsers(t *testing.T) {
+	t.Run("should keep the IDs, statuses, timestamps and profiles of every page", func(t *testing.T) {
+		client := NewClient()
+
+		err := client.User.ImportUsers(strings.NewReader(testExportUsers))
+
+		alice, _, getErr := client.GetUser(context.TODO(), "alice@test.com")
+		bob, _, _ := client.GetUser(context.TODO(), "00u1bob00000000000")
+		if err != nil || getErr != nil {
+			t.Fatalf("unexpected errors %v and %v", err, getErr)
+		}
+		if alice.Id != "00u1alice000000000" || alice.Status != UserStatusActive || (*alice.Profile)["badgeNumber"] != float64(42) {
+			t.Errorf("got %v want alice as exported", alice)
+		}
+		if want := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC); alice.LastLogin == nil || !alice.LastLogin.Equal(want) {
+			t.Errorf("got last login %v want %v", alice.LastLogin, want)
+		}
+		if bob.Status != UserStatusSuspended || !client.Group.GroupContainsUser(*client.Group.EveryoneGroup(), "bob@test.com") {
+			t.Errorf("got %v want bob SUSPENDED and in Everyone", bob)
+		}
+	})
+
+	t.Run("should err on users that already exist", func(t *testing.T) {
+		client := NewClient()
+		client.User.ImportUsers(strings.NewReader(testExportUsers))
+
+		err := client.User.ImportUsers(strings.NewReader(testExportUsers))
+
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("got %v want an error", err)
+		}
+	})
+
+	t.Run("should err on malformed dumps", func(t *testing.T) {
+		client := NewClient()
+
+		err := client.User.ImportUsers(strings.NewReader(`{"id": "00u1alice000000000"}`))
+
+		if err == nil {
+			t.Errorf("got no error want an error")
+		}
+	})
+
+	t.Run("should not generate the IDs of imported users", func(t *testing.T) {
+		seeded := NewClient(WithIDSeed(1))
+		user, _, _ := seeded.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client := NewClient(WithIDSeed(1))
+		client.User.ImportUsers(strings.NewReader(`[{"id": "` + user.Id + `", "status": "ACTIVE", "profile": {"login": "other@test.com"}}]`))
+
+		created, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		if created.Id == user.Id {
+			t.Errorf("got the imported ID %v again", created.Id)
+		}
+	})
+}
+
+func TestGroupResource_ImportGroups(t *testing.T) {
+	t.Run("should keep the IDs and replace the Everyone group", func(t *testing.T) {
+		client := NewClient()
+		client.User.ImportUsers(strings.NewReader(testExportUsers))
+
+		err := client.Group.ImportGroups(strings.NewReader(testExportGroups))
+
+		admins, _, getErr := client.GetGroup(context.TODO(), "00g1admins000000000")
+		everyone := client.Group.EveryoneGroup()
+		if err != nil || getErr != nil || admins.Profile.Name != "Admins" || len(client.Group.Groups) != 2 {
+			t.Fatalf("got %v (%v, %v) want the exported groups", client.Group.Groups, err, getErr)
+		}
+		if everyone.Id != "00g1everyone0000000" || len(client.Group.GroupUsers[everyone.Id]) != 2 {
+			t.Errorf("got Everyone %v with members %v", everyone, client.Group.GroupUsers[everyone.Id])
+		}
+	})
+}
+
+func TestGroupResource_ImportGroupUsers(t *testing.T) {
+	t.Run("should add the members and import unknown ones", func(t *testing.T) {
+		client := NewClient()
+		client.Group.ImportGroups(strings.NewReader(testExportGroups))
+
+		err := client.Group.ImportGroupUsers("00g1admins000000000", strings.NewReader(testExportUsers))
+
+		members, _, _ := client.ListGroupUsers(context.TODO(), "00g1admins000000000", nil)
+		if err != nil || len(members) != 2 || len(client.User.Users) != 2 {
+			t.Errorf("got members %v (%v) want alice and bob", members, err)
+		}
+	})
+
+	t.Run("should err on unknown groups", func(t *testing.T) {
+		client := NewClient()
+
+		err := client.Group.ImportGroupUsers("00g1admins000000000", strings.NewReader(testExportUsers))
+
+		if err == nil {
+			t.Errorf("got no error want an error")
+		}
+	})
+}
+
+func TestGroupResource_ImportGroupRoles(t *testing.T) {
+	t.Run("should keep the IDs of the role assignments", func(t *testing.T) {
+		client := NewClient()
+		client.Group.ImportGroups(strings.NewReader(testExportGroups))
+
+		err := client.Group.ImportGroupRoles("00g1admins000000000", strings.NewReader(testExportGroupRoles))
+
+		role, _, getErr := client.GetRole(context.TODO(), "00g1admins000000000", "ra1admins000000000")
+		if err != nil || getErr != nil || role.Type != "SUPER_ADMIN" {
+			t.Errorf("got %v (%v, %v) want the exported role", role, err, getErr)
+		}
+	})
+}
+
+func TestGroupResource_ImportGroupRoleTargets(t *testing.T) {
+	t.Run("should restrict the role to the target groups and import unknown ones", func(t *testing.T) {
+		client := NewClient()
+		client.Group.ImportGroups(strings.NewReader(testExportGroups))
+		client.Group.ImportGroupRoles("00g1admins000000000", strings.NewReader(testExportGroupAdminRoles))
+
+		err := client.Group.ImportGroupRoleTargets("00g1admins000000000", "ra1groupadmin00000", strings.NewReader(testExportRoleTargets))
+
+		targets, _, listErr := client.ListGroupTargetsForGroupRole(context.TODO(), "00g1admins000000000", "ra1groupadmin00000", nil)
+		if err != nil || listErr != nil || len(targets) != 1 || targets[0].Profile.Name != "Support" {
+			t.Errorf("got %v (%v, %v) want the Support group", targets, err, listErr)
+		}
+	})
+
+	t.Run("should err for roles that don't take group targets", func(t *testing.T) {
+		client := NewClient()
+		client.Group.ImportGroups(strings.NewReader(testExportGroups))
+		client.Group.ImportGroupRoles("00g1admins000000000", strings.NewReader(testExportGroupRoles))
+
+		err := client.Group.ImportGroupRoleTargets("00g1admins000000000", "ra1admins000000000", strings.NewReader(testExportRoleTargets))
+
+		if err == nil {
+			t.Errorf("got no error want an error")
+		}
+	})
+}
+
+func TestNewClientFromOktaExport(t *testing.T) {
+	t.Run("should import the org with admins that can act as the principal", func(t *testing.T) {
+		dir := writeTestExport(t, map[string]string{
+			"groups.json":                           testExportGroups,
+			"users.json":                            testExportUsers,
+			"groups/00g1admins000000000/users.json": `[{"id": "00u1alice000000000", "status": "ACTIVE", "profile": {"login": "alice@test.com"}}]`,
+			"groups/00g1admins000000000/roles.json": testExportGroupRoles,
+		})
+
+		client, err := NewClientFromOktaExport(dir, WithPrincipal("alice@test.com"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		_, _, createErr := client.CreateGroup(context.TODO(), *NewGroup("Other"))
+
+		if createErr != nil || !client.Group.GroupContainsRole(*mustGetGroupByName(t, client, "Admins"), "SUPER_ADMIN") {
+			t.Errorf("got %v want alice to be a super admin", createErr)
+		}
+	})
+
+	t.Run("should keep admins restricted to their target groups", func(t *testing.T) {
+		dir := writeTestExport(t, map[string]string{
+			"groups.json":                           testExportGroups,
+			"users.json":                            testExportUsers,
+			"groups/00g1admins000000000/users.json": `[{"id": "00u1alice000000000", "status": "ACTIVE", "profile": {"login": "alice@test.com"}}]`,
+			"groups/00g1admins000000000/roles.json": testExportGroupAdminRoles,
+			"groups/00g1admins000000000/roles/ra1groupadmin00000/targets/groups.json": testExportRoleTargets,
+		})
+
+		client, err := NewClientFromOktaExport(dir, WithPrincipal("alice@test.com"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		_, targetErr := client.AddUserToGroup(context.TODO(), "00g1support00000000", "00u1bob00000000000")
+		resp, otherErr := client.AddUserToGroup(context.TODO(), "00g1admins000000000", "00u1bob00000000000")
+
+		if targetErr != nil {
+			t.Errorf("unexpected error %v", targetErr)
+		}
+		assertForbidden(t, resp, otherErr)
+	})
+
+	t.Run("should skip missing files", func(t *testing.T) {
+		dir := writeTestExport(t, map[string]string{"users.json": testExportUsers})
+
+		client, err := NewClientFromOktaExport(dir)
+
+		if err != nil || len(client.User.Users) != 2 || len(client.Group.Groups) != 1 {
+			t.Errorf("got %v want the users and the Everyone group", err)
+		}
+	})
+
+	t.Run("should name the file of errors", func(t *testing.T) {
+		dir := writeTestExport(t, map[string]string{"groups.json": `[{"id": 1}]`})
+
+		_, err := NewClientFromOktaExport(dir)
+
+		if err == nil || !strings.Contains(err.Error(), "groups.json") {
+			t.Errorf("got %v want an error about groups.json", err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package mockokta
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// orgURL is the fake org the mock's *okta.Response links point at
+const orgURL = "https://mockokta.okta.com"
+
+// paginate returns the page of items following the qp.After cursor, limited to qp.Limit items,
+// along with the cursor for the next page, or an empty string if this is the last page
+func paginate[T any](items []T, id func(T) string, qp *query.Params) ([]T, string) {
+	page := make([]T, 0)
+	if qp == nil {
+		return append(page, items...), ""
+	}
+	start := 0
+	if qp.After != "" {
+		start = len(items)
+		for idx, item := range items {
+			if id(item) == qp.After {
+				start = idx + 1
+				break
+			}
+		}
+	}
+	end := len(items)
+	if qp.Limit > 0 && start+int(qp.Limit) < end {
+		end = start + int(qp.Limit)
+	}
+	page = append(page, items[start:end]...)
+	if end < len(items) && len(page) > 0 {
+		return page, id(page[len(page)-1])
+	}
+	return page, ""
+}
+
+// cannedResponseKey is the context key used to hand a prepared *http.Response to the transport
+type cannedResponseKey struct{}
+
+// transport is an http.RoundTripper that serves requests from the mock's state without touching the network
+type transport struct {
+	client *MockClient
+}
+
+// RoundTrip returns the canned response stored in the request context if there is one, otherwise it
+// serves the request from the mock's handler
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if resp, ok := req.Context().Value(cannedResponseKey{}).(*http.Response); ok {
+		resp.Request = req
+		return resp, nil
+	}
+	rec := httptest.NewRecorder()
+	t.client.handler().ServeHTTP(rec, req)
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+// newSDKClient creates a real okta client whose requests are served by the mock, so the
+// *okta.Response values we return can follow Link headers with Next
+func newSDKClient(client *MockClient) *okta.Client {
+	_, sdk, err := okta.NewClient(context.Background(),
+		okta.WithOrgUrl(orgURL),
+		okta.WithToken("mockokta"),
+		okta.WithAuthorizationMode("SSWS"),
+		okta.WithCache(false),
+		okta.WithRequestTimeout(0),
+		okta.WithRateLimitMaxRetries(0),
+		okta.WithHttpClientPtr(&http.Client{Transport: &transport{client: client}}),
+	)
+	if err != nil {
+		return nil
+	}
+	return sdk
+}
+
+// listResponse returns an *okta.Response for a page of results from path, with a rel="next" Link
+// header pointing at the after cursor when there is another page
+func (client *MockClient) listResponse(ctx context.Context, path string, qp *query.Params, after string) *okta.Response {
+	params := query.Params{}
+	if qp != nil {
+		params = *qp
+	}
+	self := path + params.String()
+	header := http.Header{}
+	header.Add("Content-Type", "application/json")
+	header.Add("Link", fmt.Sprintf(`<%s%s>; rel="self"`, orgURL, self))
+	if after != "" {
+		params.After = after
+		header.Add("Link", fmt.Sprintf(`<%s%s%s>; rel="next"`, orgURL, path, params.String()))
+	}
+	httpResp := &http.Response{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+	}
+
+	if client.sdk != nil {
+		re := client.sdk.CloneRequestExecutor()
+		req, err := re.NewRequest(http.MethodGet, self, nil)
+		if err == nil {
+			resp, err := re.Do(context.WithValue(ctx, cannedResponseKey{}, httpResp), req, nil)
+			if err == nil {
+				return resp
+			}
+		}
+	}
+	resp := &okta.Response{Response: httpResp, Self: self}
+	if after != "" {
+		resp.NextPage = path + params.String()
+	}
+	return resp
+}
+
+// queryParamsFromURL parses the query params the mock understands out of a request URL
+func queryParamsFromURL(values url.Values) *query.Params {
+	qp := query.NewQueryParams(
+		query.WithQ(values.Get("q")),
+		query.WithAfter(values.Get("after")),
+		query.WithFilter(values.Get("filter")),
+		query.WithSearch(values.Get("search")),
+	)
+	if limit, err := strconv.ParseInt(values.Get("limit"), 10, 64); err == nil {
+		qp.Limit = limit
+	}
+	if activate, err := strconv.ParseBool(values.Get("activate")); err == nil {
+		qp.Activate = &activate
+	}
+	if sendEmail, err := strconv.ParseBool(values.Get("sendEmail")); err == nil {
+		qp.SendEmail = &sendEmail
+	}
+	if removeUsers, err := strconv.ParseBool(values.Get("removeUsers")); err == nil {
+		qp.RemoveUsers = &removeUsers
+	}
+	return qp
+}
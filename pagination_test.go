@@ -0,0 +1,129 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []string{"1", "2", "3", "4", "5"}
+	id := func(s string) string { return s }
+
+	tests := []struct {
+		name      string
+		qp        *query.Params
+		wantPage  []string
+		wantAfter string
+	}{
+		{"nil params returns everything", nil, items, ""},
+		{"no limit returns everything", query.NewQueryParams(), items, ""},
+		{"first page", query.NewQueryParams(query.WithLimit(2)), []string{"1", "2"}, "2"},
+		{"middle page", query.NewQueryParams(query.WithLimit(2), query.WithAfter("2")), []string{"3", "4"}, "4"},
+		{"last page", query.NewQueryParams(query.WithLimit(2), query.WithAfter("4")), []string{"5"}, ""},
+		{"exact last page", query.NewQueryParams(query.WithLimit(5)), items, ""},
+		{"unknown cursor", query.NewQueryParams(query.WithAfter("x")), []string{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, after := paginate(items, id, tt.qp)
+
+			if !reflect.DeepEqual(page, tt.wantPage) || after != tt.wantAfter {
+				t.Errorf("got %v %q want %v %q", page, after, tt.wantPage, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestMockClient_ListGroupsPagination(t *testing.T) {
+	t.Run("should follow next links until the last page", func(t *testing.T) {
+		client := NewClient()
+		for i := 0; i < 5; i++ {
+			client.Group.CreateGroup(context.TODO(), *NewGroup(fmt.Sprintf("TestGroup%d", i)))
+		}
+
+		groups, resp, err := client.ListGroups(context.TODO(), query.NewQueryParams(query.WithLimit(2)))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		pages := 1
+		for resp.HasNextPage() {
+			var next []*okta.Group
+			resp, err = resp.Next(context.TODO(), &next)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			groups = append(groups, next...)
+			pages++
+		}
+
+		if pages != 3 {
+			t.Errorf("expected 3 pages but got %d", pages)
+		}
+		for i, group := range groups {
+			if group.Id != client.Group.Groups[i].Id {
+				t.Errorf("got group %v at %d want %v", group.Id, i, client.Group.Groups[i].Id)
+			}
+		}
+	})
+
+	t.Run("should keep the search when paginating", func(t *testing.T) {
+		client := NewClient()
+		for i := 0; i < 3; i++ {
+			client.Group.CreateGroup(context.TODO(), *NewGroup(fmt.Sprintf("eng-%d", i)))
+			client.Group.CreateGroup(context.TODO(), *NewGroup(fmt.Sprintf("ops-%d", i)))
+		}
+
+		_, resp, _ := client.ListGroups(context.TODO(), query.NewQueryParams(query.WithQ("eng"), query.WithLimit(2)))
+		var next []*okta.Group
+		resp, _ = resp.Next(context.TODO(), &next)
+
+		if len(next) != 1 || next[0].Profile.Name != "eng-2" || resp.HasNextPage() {
+			t.Errorf("got %v want only eng-2 on the last page", next)
+		}
+	})
+
+	t.Run("should not have next page without limit", func(t *testing.T) {
+		client := NewClient()
+		client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		_, resp, _ := client.ListGroups(context.TODO(), nil)
+
+		if resp == nil || resp.HasNextPage() {
+			t.Errorf("expected a response without a next page but got %#v", resp)
+		}
+	})
+}
+
+func TestMockClient_ListUsersPagination(t *testing.T) {
+	client := NewClient()
+	group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+	for i := 0; i < 3; i++ {
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest(fmt.Sprintf("TestUser%d@test.com", i)), nil)
+		client.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
+	}
+
+	t.Run("should paginate users", func(t *testing.T) {
+		users, resp, _ := client.ListUsers(context.TODO(), query.NewQueryParams(query.WithLimit(2)))
+		var next []*okta.User
+		resp, err := resp.Next(context.TODO(), &next)
+
+		if err != nil || len(users) != 2 || len(next) != 1 || next[0].Id != client.User.Users[2].Id || resp.HasNextPage() {
+			t.Errorf("got %v then %v (%v) want pages of 2 and 1", users, next, err)
+		}
+	})
+
+	t.Run("should paginate group users", func(t *testing.T) {
+		users, resp, _ := client.ListGroupUsers(context.TODO(), group.Id, query.NewQueryParams(query.WithLimit(2)))
+		var next []*okta.User
+		resp, err := resp.Next(context.TODO(), &next)
+
+		if err != nil || len(users) != 2 || len(next) != 1 || resp.HasNextPage() {
+			t.Errorf("got %v then %v (%v) want pages of 2 and 1", users, next, err)
+		}
+	})
+}
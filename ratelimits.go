@@ -0,0 +1,291 @@
+package mockokta
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// Clock tells the time to the rate limits of a MockClient, so tests can move time forward instead of
+// waiting for a rate limit window to end
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a Clock that only moves when it is advanced
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a FakeClock stopped at now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the time the clock is stopped at
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by the duration
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// WithClock makes the client's rate limits use the clock instead of the system time
+func WithClock(clock Clock) ClientOption {
+	return func(c *MockClient) {
+		c.clock = clock
+	}
+}
+
+// RateLimit is a budget of requests per window for the API endpoints with the HTTP method, or any
+// method if it is empty, and a path matching Path. In Path a * segment matches any single segment
+// and a trailing /** matches any remaining segments, so /api/v1/users/* matches /api/v1/users/{id}
+type RateLimit struct {
+	Method string
+	Path   string
+	Limit  int
+	Window time.Duration
+}
+
+// DefaultRateLimits returns per minute budgets modelled on the default rate limits of an Okta org, see
+// https://developer.okta.com/docs/reference/rl-global-mgmt/. The budgets are checked in order, so the
+// specific endpoints come before the catch-all /api/v1/** budget
+func DefaultRateLimits() []RateLimit {
+	return []RateLimit{
+		{http.MethodGet, "/api/v1/users", 600, time.Minute},
+		{http.MethodPost, "/api/v1/users", 600, time.Minute},
+		{http.MethodGet, "/api/v1/users/*", 2000, time.Minute},
+		{"", "/api/v1/users/**", 600, time.Minute},
+		{"", "/api/v1/groups", 500, time.Minute},
+		{"", "/api/v1/groups/*", 1000, time.Minute},
+		{"", "/api/v1/groups/**", 500, time.Minute},
+		{"", "/api/v1/apps", 100, time.Minute},
+		{"", "/api/v1/apps/**", 500, time.Minute},
+		{"", "/api/v1/**", 1200, time.Minute},
+	}
+}
+
+// WithRateLimits makes the client enforce the rate limits from its creation, see SetRateLimits
+func WithRateLimits(limits ...RateLimit) ClientOption {
+	return func(c *MockClient) {
+		c.setRateLimits(limits)
+	}
+}
+
+// SetRateLimits makes every call through the client's methods count against the first of the rate
+// limits matching the API endpoint of the method, starting new windows for all of them. Once a
+// limit is spent the calls return a 429 E0000047 error until its window ends. Responses have the
+// X-Rate-Limit-Limit, X-Rate-Limit-Remaining and X-Rate-Limit-Reset headers Okta sends. Calling it
+// with no limits stops enforcing them
+func (client *MockClient) SetRateLimits(limits ...RateLimit) {
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	client.setRateLimits(limits)
+}
+
+func (client *MockClient) setRateLimits(limits []RateLimit) {
+	client.rateLimits = limits
+	client.rateLimitWindows = make([]rateLimitWindow, len(limits))
+}
+
+// rateLimitWindow is the number of requests made in the current window of a rate limit, which ends
+// at reset
+type rateLimitWindow struct {
+	reset time.Time
+	used  int
+}
+
+// endpoint is the HTTP method and path of the Okta API endpoint a MockClient method calls
+type endpoint struct {
+	method string
+	path   string
+}
+
+// endpoints are the API endpoints of the MockClient methods, with * for the IDs in their paths
+var endpoints = map[string]endpoint{
+	"ListUsers":              {http.MethodGet, "/api/v1/users"},
+	"CreateUser":             {http.MethodPost, "/api/v1/users"},
+	"GetUser":                {http.MethodGet, "/api/v1/users/*"},
+	"UpdateUser":             {http.MethodPut, "/api/v1/users/*"},
+	"PartialUpdateUser":      {http.MethodPost, "/api/v1/users/*"},
+	"DeactivateOrDeleteUser": {http.MethodDelete, "/api/v1/users/*"},
+	"ActivateUser":           {http.MethodPost, "/api/v1/users/*/lifecycle/activate"},
+	"DeactivateUser":         {http.MethodPost, "/api/v1/users/*/lifecycle/deactivate"},
+	"SuspendUser":            {http.MethodPost, "/api/v1/users/*/lifecycle/suspend"},
+	"UnsuspendUser":          {http.MethodPost, "/api/v1/users/*/lifecycle/unsuspend"},
+	"UnlockUser":             {http.MethodPost, "/api/v1/users/*/lifecycle/unlock"},
+	"ResetPassword":          {http.MethodPost, "/api/v1/users/*/lifecycle/reset_password"},
+	"ExpirePassword":         {http.MethodPost, "/api/v1/users/*/lifecycle/expire_password"},
+
+	"ListAssignedRolesForUser":  {http.MethodGet, "/api/v1/users/*/roles"},
+	"AssignRoleToUser":          {http.MethodPost, "/api/v1/users/*/roles"},
+	"AssignCustomRoleToUser":    {http.MethodPost, "/api/v1/users/*/roles"},
+	"GetUserRole":               {http.MethodGet, "/api/v1/users/*/roles/*"},
+	"RemoveRoleFromUser":        {http.MethodDelete, "/api/v1/users/*/roles/*"},
+	"ListGroupTargetsForRole":   {http.MethodGet, "/api/v1/users/*/roles/*/targets/groups"},
+	"AddGroupTargetToRole":      {http.MethodPut, "/api/v1/users/*/roles/*/targets/groups/*"},
+	"RemoveGroupTargetFromRole": {http.MethodDelete, "/api/v1/users/*/roles/*/targets/groups/*"},
+	"ListApplicationTargetsForApplicationAdministratorRoleForUser":   {http.MethodGet, "/api/v1/users/*/roles/*/targets/catalog/apps"},
+	"AddAllAppsAsTargetToRole":                                       {http.MethodPut, "/api/v1/users/*/roles/*/targets/catalog/apps"},
+	"AddApplicationTargetToAdminRoleForUser":                         {http.MethodPut, "/api/v1/users/*/roles/*/targets/catalog/apps/*"},
+	"RemoveApplicationTargetFromApplicationAdministratorRoleForUser": {http.MethodDelete, "/api/v1/users/*/roles/*/targets/catalog/apps/*"},
+	"AddApplicationTargetToAppAdminRoleForUser":                      {http.MethodPut, "/api/v1/users/*/roles/*/targets/catalog/apps/*/*"},
+	"RemoveApplicationTargetFromAdministratorRoleForUser":            {http.MethodDelete, "/api/v1/users/*/roles/*/targets/catalog/apps/*/*"},
+
+	"ListGroups":                   {http.MethodGet, "/api/v1/groups"},
+	"CreateGroup":                  {http.MethodPost, "/api/v1/groups"},
+	"GetGroup":                     {http.MethodGet, "/api/v1/groups/*"},
+	"UpdateGroup":                  {http.MethodPut, "/api/v1/groups/*"},
+	"DeleteGroup":                  {http.MethodDelete, "/api/v1/groups/*"},
+	"ListGroupUsers":               {http.MethodGet, "/api/v1/groups/*/users"},
+	"AddUserToGroup":               {http.MethodPut, "/api/v1/groups/*/users/*"},
+	"RemoveUserFromGroup":          {http.MethodDelete, "/api/v1/groups/*/users/*"},
+	"ListGroupAssignedRoles":       {http.MethodGet, "/api/v1/groups/*/roles"},
+	"AssignRoleToGroup":            {http.MethodPost, "/api/v1/groups/*/roles"},
+	"AssignCustomRoleToGroup":      {http.MethodPost, "/api/v1/groups/*/roles"},
+	"GetRole":                      {http.MethodGet, "/api/v1/groups/*/roles/*"},
+	"RemoveRoleFromGroup":          {http.MethodDelete, "/api/v1/groups/*/roles/*"},
+	"ListGroupTargetsForGroupRole": {http.MethodGet, "/api/v1/groups/*/roles/*/targets/groups"},
+	"AddGroupTargetToGroupAdministratorRoleForGroup":                      {http.MethodPut, "/api/v1/groups/*/roles/*/targets/groups/*"},
+	"RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup":             {http.MethodDelete, "/api/v1/groups/*/roles/*/targets/groups/*"},
+	"ListApplicationTargetsForApplicationAdministratorRoleForGroup":       {http.MethodGet, "/api/v1/groups/*/roles/*/targets/catalog/apps"},
+	"AddApplicationTargetToAdminRoleGivenToGroup":                         {http.MethodPut, "/api/v1/groups/*/roles/*/targets/catalog/apps/*"},
+	"RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup": {http.MethodDelete, "/api/v1/groups/*/roles/*/targets/catalog/apps/*"},
+	"AddApplicationInstanceTargetToAppAdminRoleGivenToGroup":              {http.MethodPut, "/api/v1/groups/*/roles/*/targets/catalog/apps/*/*"},
+	"RemoveApplicationTargetFromAdministratorRoleGivenToGroup":            {http.MethodDelete, "/api/v1/groups/*/roles/*/targets/catalog/apps/*/*"},
+
+	"ListGroupRules":      {http.MethodGet, "/api/v1/groups/rules"},
+	"CreateGroupRule":     {http.MethodPost, "/api/v1/groups/rules"},
+	"GetGroupRule":        {http.MethodGet, "/api/v1/groups/rules/*"},
+	"DeleteGroupRule":     {http.MethodDelete, "/api/v1/groups/rules/*"},
+	"ActivateGroupRule":   {http.MethodPost, "/api/v1/groups/rules/*/lifecycle/activate"},
+	"DeactivateGroupRule": {http.MethodPost, "/api/v1/groups/rules/*/lifecycle/deactivate"},
+
+	"ListApplications":                 {http.MethodGet, "/api/v1/apps"},
+	"CreateApplication":                {http.MethodPost, "/api/v1/apps"},
+	"GetApplication":                   {http.MethodGet, "/api/v1/apps/*"},
+	"DeleteApplication":                {http.MethodDelete, "/api/v1/apps/*"},
+	"ActivateApplication":              {http.MethodPost, "/api/v1/apps/*/lifecycle/activate"},
+	"DeactivateApplication":            {http.MethodPost, "/api/v1/apps/*/lifecycle/deactivate"},
+	"ListApplicationUsers":             {http.MethodGet, "/api/v1/apps/*/users"},
+	"AssignUserToApplication":          {http.MethodPost, "/api/v1/apps/*/users"},
+	"GetApplicationUser":               {http.MethodGet, "/api/v1/apps/*/users/*"},
+	"DeleteApplicationUser":            {http.MethodDelete, "/api/v1/apps/*/users/*"},
+	"ListApplicationGroupAssignments":  {http.MethodGet, "/api/v1/apps/*/groups"},
+	"GetApplicationGroupAssignment":    {http.MethodGet, "/api/v1/apps/*/groups/*"},
+	"CreateApplicationGroupAssignment": {http.MethodPut, "/api/v1/apps/*/groups/*"},
+	"DeleteApplicationGroupAssignment": {http.MethodDelete, "/api/v1/apps/*/groups/*"},
+
+	"GetUserSchema":     {http.MethodGet, "/api/v1/meta/schemas/user/*"},
+	"UpdateUserProfile": {http.MethodPost, "/api/v1/meta/schemas/user/*"},
+	"GetGroupSchema":    {http.MethodGet, "/api/v1/meta/schemas/group/default"},
+	"UpdateGroupSchema": {http.MethodPost, "/api/v1/meta/schemas/group/default"},
+
+	"ListCustomRoles":                {http.MethodGet, "/api/v1/iam/roles"},
+	"CreateCustomRole":               {http.MethodPost, "/api/v1/iam/roles"},
+	"GetCustomRole":                  {http.MethodGet, "/api/v1/iam/roles/*"},
+	"UpdateCustomRole":               {http.MethodPut, "/api/v1/iam/roles/*"},
+	"DeleteCustomRole":               {http.MethodDelete, "/api/v1/iam/roles/*"},
+	"AddCustomRolePermission":        {http.MethodPost, "/api/v1/iam/roles/*/permissions/*"},
+	"RemoveCustomRolePermission":     {http.MethodDelete, "/api/v1/iam/roles/*/permissions/*"},
+	"ListResourceSets":               {http.MethodGet, "/api/v1/iam/resource-sets"},
+	"CreateResourceSet":              {http.MethodPost, "/api/v1/iam/resource-sets"},
+	"GetResourceSet":                 {http.MethodGet, "/api/v1/iam/resource-sets/*"},
+	"UpdateResourceSet":              {http.MethodPut, "/api/v1/iam/resource-sets/*"},
+	"DeleteResourceSet":              {http.MethodDelete, "/api/v1/iam/resource-sets/*"},
+	"ListResourceSetResources":       {http.MethodGet, "/api/v1/iam/resource-sets/*/resources"},
+	"AddResourceSetResources":        {http.MethodPatch, "/api/v1/iam/resource-sets/*/resources"},
+	"DeleteResourceSetResource":      {http.MethodDelete, "/api/v1/iam/resource-sets/*/resources/*"},
+	"ListResourceSetBindings":        {http.MethodGet, "/api/v1/iam/resource-sets/*/bindings"},
+	"CreateResourceSetBinding":       {http.MethodPost, "/api/v1/iam/resource-sets/*/bindings"},
+	"DeleteResourceSetBinding":       {http.MethodDelete, "/api/v1/iam/resource-sets/*/bindings/*"},
+	"ListResourceSetBindingMembers":  {http.MethodGet, "/api/v1/iam/resource-sets/*/bindings/*/members"},
+	"AddResourceSetBindingMembers":   {http.MethodPatch, "/api/v1/iam/resource-sets/*/bindings/*/members"},
+	"DeleteResourceSetBindingMember": {http.MethodDelete, "/api/v1/iam/resource-sets/*/bindings/*/members/*"},
+}
+
+// rateLimitHeaders are the headers with the state of the rate limit of a request
+var rateLimitHeaders = []string{"X-Rate-Limit-Limit", "X-Rate-Limit-Remaining", "X-Rate-Limit-Reset"}
+
+// rateLimit counts the call to the method against the first rate limit matching its endpoint and
+// returns the rate limit headers of its response, with a 429 E0000047 error once the limit is spent
+func (client *MockClient) rateLimit(method string) (http.Header, *okta.Response, error) {
+	client.callsMu.Lock()
+	defer client.callsMu.Unlock()
+
+	e, ok := endpoints[method]
+	if !ok {
+		return nil, nil, nil
+	}
+	for i, limit := range client.rateLimits {
+		if limit.Method != "" && limit.Method != e.method || !matchEndpointPath(limit.Path, e.path) {
+			continue
+		}
+		now := client.clock.Now()
+		window := &client.rateLimitWindows[i]
+		if !now.Before(window.reset) {
+			*window = rateLimitWindow{reset: now.Add(limit.Window)}
+		}
+		header := http.Header{}
+		header.Set("Date", now.UTC().Format(http.TimeFormat))
+		header.Set("X-Rate-Limit-Limit", strconv.Itoa(limit.Limit))
+		header.Set("X-Rate-Limit-Reset", strconv.FormatInt(window.reset.Unix(), 10))
+		if window.used >= limit.Limit {
+			header.Set("X-Rate-Limit-Remaining", "0")
+			err := NewError(ErrorCodeRateLimited)
+			return header, errorResponse(err), err
+		}
+		window.used++
+		header.Set("X-Rate-Limit-Remaining", strconv.Itoa(limit.Limit-window.used))
+		return header, nil, nil
+	}
+	return nil, nil, nil
+}
+
+// matchEndpointPath returns whether the path of an endpoint matches the path of a rate limit
+func matchEndpointPath(pattern string, path string) bool {
+	patternSegments := strings.Split(pattern, "/")
+	segments := strings.Split(path, "/")
+	for i, segment := range patternSegments {
+		if segment == "**" && i == len(patternSegments)-1 {
+			return len(segments) > i
+		}
+		if i >= len(segments) || segment != "*" && segment != segments[i] {
+			return false
+		}
+	}
+	return len(segments) == len(patternSegments)
+}
+
+// setHeaders adds the headers to the response
+func setHeaders(resp *okta.Response, header http.Header) {
+	if resp == nil || resp.Response == nil {
+		return
+	}
+	if resp.Header == nil {
+		resp.Header = http.Header{}
+	}
+	for name, values := range header {
+		resp.Header[name] = values
+	}
+}
@@ -0,0 +1,112 @@
+package mockokta
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func TestMockClient_SetRateLimits(t *testing.T) {
+	t.Run("should return 429s once the limit is spent until the window ends", func(t *testing.T) {
+		clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		client := NewClient(WithClock(clock), WithRateLimits(RateLimit{Path: "/api/v1/groups", Limit: 2, Window: time.Minute}))
+
+		_, first, _ := client.ListGroups(context.TODO(), nil)
+		_, second, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		_, resp, err := client.ListGroups(context.TODO(), nil)
+		clock.Advance(time.Minute)
+		_, afterReset, resetErr := client.ListGroups(context.TODO(), nil)
+
+		assertOktaError(t, resp, err, ErrorCodeRateLimited)
+		reset := strconv.FormatInt(clock.Now().Unix(), 10)
+		for i, tt := range []struct {
+			resp      *okta.Response
+			remaining string
+			reset     string
+		}{{first, "1", reset}, {second, "0", reset}, {resp, "0", reset}, {afterReset, "1", strconv.FormatInt(clock.Now().Add(time.Minute).Unix(), 10)}} {
+			if got := tt.resp.Header.Get("X-Rate-Limit-Remaining"); got != tt.remaining {
+				t.Errorf("got %v remaining after call %v want %v", got, i+1, tt.remaining)
+			}
+			if got := tt.resp.Header.Get("X-Rate-Limit-Reset"); got != tt.reset {
+				t.Errorf("got reset %v after call %v want %v", got, i+1, tt.reset)
+			}
+			if got := tt.resp.Header.Get("X-Rate-Limit-Limit"); got != "2" {
+				t.Errorf("got limit %v want 2", got)
+			}
+		}
+		if resetErr != nil {
+			t.Errorf("unexpected error %v", resetErr)
+		}
+	})
+
+	t.Run("should count each endpoint against its own limit", func(t *testing.T) {
+		client := NewClient(WithRateLimits(
+			RateLimit{Method: http.MethodGet, Path: "/api/v1/users", Limit: 1, Window: time.Minute},
+			RateLimit{Path: "/api/v1/**", Limit: 1, Window: time.Minute},
+		))
+
+		_, _, listErr := client.ListUsers(context.TODO(), nil)
+		_, _, groupErr := client.ListGroups(context.TODO(), nil)
+		_, resp, err := client.GetGroup(context.TODO(), "00gmissing")
+
+		if listErr != nil || groupErr != nil {
+			t.Errorf("got %v and %v want no errors", listErr, groupErr)
+		}
+		assertOktaError(t, resp, err, ErrorCodeRateLimited)
+	})
+
+	t.Run("should stop enforcing limits", func(t *testing.T) {
+		client := NewClient(WithRateLimits(RateLimit{Path: "/api/v1/**", Limit: 1, Window: time.Minute}))
+		client.ListGroups(context.TODO(), nil)
+
+		client.SetRateLimits()
+		_, resp, err := client.ListGroups(context.TODO(), nil)
+
+		if err != nil || resp.Header.Get("X-Rate-Limit-Limit") != "" {
+			t.Errorf("got %v (%v) want no rate limit", resp.Header, err)
+		}
+	})
+
+	t.Run("should know the endpoint of every method", func(t *testing.T) {
+		responseType := reflect.TypeOf(&okta.Response{})
+		clientType := reflect.TypeOf(&MockClient{})
+		for i := 0; i < clientType.NumMethod(); i++ {
+			method := clientType.Method(i)
+			numOut := method.Type.NumOut()
+			if numOut < 2 || method.Type.Out(numOut-2) != responseType {
+				continue
+			}
+			if _, ok := endpoints[method.Name]; !ok {
+				t.Errorf("got no endpoint for %v", method.Name)
+			}
+		}
+	})
+}
+
+func TestMatchEndpointPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/api/v1/users", "/api/v1/users", true},
+		{"/api/v1/users", "/api/v1/users/*", false},
+		{"/api/v1/users/*", "/api/v1/users/*", true},
+		{"/api/v1/users/*", "/api/v1/users/*/roles", false},
+		{"/api/v1/users/**", "/api/v1/users/*/roles", true},
+		{"/api/v1/users/**", "/api/v1/users", false},
+		{"/api/v1/**", "/api/v1/groups/*/users/*", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := matchEndpointPath(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// Okta role assignment types and statuses, see https://developer.okta.com/docs/reference/api/roles/#role-object
+const (
+	RoleAssignmentTypeUser  = "USER"
+	RoleAssignmentTypeGroup = "GROUP"
+	RoleStatusActive        = "ACTIVE"
+)
+
+// AssignRoleToUser is a wrapper to call client.User.AssignRoleToUser to make it easier to match an interface for the okta client
+func (client *MockClient) AssignRoleToUser(ctx context.Context, userID string, assignRoleRequest okta.AssignRoleRequest, qp *query.Params) (*okta.Role, *okta.Response, error) {
+	return intercept(ctx, client, "AssignRoleToUser", []interface{}{userID, assignRoleRequest, qp}, func() (*okta.Role, *okta.Response, error) {
+		return client.User.AssignRoleToUser(ctx, userID, assignRoleRequest, qp)
+	})
+}
+
+// ListAssignedRolesForUser is a wrapper to call client.User.ListAssignedRolesForUser to make it easier to match an interface for the okta client
+func (client *MockClient) ListAssignedRolesForUser(ctx context.Context, userID string, qp *query.Params) ([]*okta.Role, *okta.Response, error) {
+	return intercept(ctx, client, "ListAssignedRolesForUser", []interface{}{userID, qp}, func() ([]*okta.Role, *okta.Response, error) {
+		return client.User.ListAssignedRolesForUser(ctx, userID, qp)
+	})
+}
+
+// GetUserRole is a wrapper to call client.User.GetUserRole to make it easier to match an interface for the okta client
+func (client *MockClient) GetUserRole(ctx context.Context, userID string, roleID string) (*okta.Role, *okta.Response, error) {
+	return intercept(ctx, client, "GetUserRole", []interface{}{userID, roleID}, func() (*okta.Role, *okta.Response, error) {
+		return client.User.GetUserRole(ctx, userID, roleID)
+	})
+}
+
+// GetRole is a wrapper to call client.Group.GetRole to make it easier to match an interface for the okta client
+func (client *MockClient) GetRole(ctx context.Context, groupID string, roleID string) (*okta.Role, *okta.Response, error) {
+	return intercept(ctx, client, "GetRole", []interface{}{groupID, roleID}, func() (*okta.Role, *okta.Response, error) {
+		return client.Group.GetRole(ctx, groupID, roleID)
+	})
+}
+
+// RemoveRoleFromUser is a wrapper to call client.User.RemoveRoleFromUser to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveRoleFromUser(ctx context.Context, userID string, roleID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveRoleFromUser", []interface{}{userID, roleID}, func() (*okta.Response, error) {
+		return client.User.RemoveRoleFromUser(ctx, userID, roleID)
+	})
+}
+
+// RemoveRoleFromGroup is a wrapper to call client.Group.RemoveRoleFromGroup to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveRoleFromGroup(ctx context.Context, groupID string, roleID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveRoleFromGroup", []interface{}{groupID, roleID}, func() (*okta.Response, error) {
+		return client.Group.RemoveRoleFromGroup(ctx, groupID, roleID)
+	})
+}
+
+// newRoleAssignment validates the role type in the assignRoleRequest and creates a role assignment
+// of it with a new ID. okta.AssignRoleRequest only has a type, so it can't carry the custom role and
+// resource set a CUSTOM assignment needs: CUSTOM roles are assigned with AssignCustomRoleToGroup and
+// AssignCustomRoleToUser, or with AssignRoleToGroup and AssignRoleToUser given WithCustomRoleAssignment
+func (client *MockClient) newRoleAssignment(assignRoleRequest okta.AssignRoleRequest, assignmentType string) (*okta.Role, *okta.Error) {
+	if !SliceContainsString(adminRoles, assignRoleRequest.Type) {
+		return nil, newValidationError("type", "Invalid role type")
+	}
+	if assignRoleRequest.Type == CustomRoleType {
+		return nil, newValidationError("role", "Custom role assignments need a role and a resource set")
+	}
+	now := time.Now().UTC()
+	role := NewRole(assignRoleRequest.Type)
+	role.Id = client.ids.NewID(RoleAssignmentIDPrefix)
+	role.AssignmentType = assignmentType
+	role.Status = RoleStatusActive
+	role.Created = &now
+	role.LastUpdated = &now
+	return &role, nil
+}
+
+// AssignRoleToUser assigns the role in the assignRoleRequest to the user directly and returns the role it assigned.
+// CUSTOM roles need the custom role and resource set of WithCustomRoleAssignment on the ctx
+func (u *UserResource) AssignRoleToUser(ctx context.Context, userID string, assignRoleRequest okta.AssignRoleRequest, qp *query.Params) (*okta.Role, *okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if request, ok := customRoleAssignment(ctx); ok && assignRoleRequest.Type == CustomRoleType {
+		return u.assignCustomRoleToUser(userID, request)
+	}
+	role, roleErr := u.Client.newRoleAssignment(assignRoleRequest, RoleAssignmentTypeUser)
+	if roleErr != nil {
+		return nil, errorResponse(roleErr), roleErr
+	}
+	user, err := u.getUserByID(userID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	for _, assigned := range u.UserRoles[user.Id] {
+		if assigned.Type == assignRoleRequest.Type {
+			err := NewError(ErrorCodeDuplicateRole, "The role specified is already assigned to the user.")
+			return nil, errorResponse(err), err
+		}
+	}
+	u.UserRoles[user.Id] = append(u.UserRoles[user.Id], role)
+	return role, newResponse(http.StatusCreated, role), nil
+}
+
+// ListAssignedRolesForUser lists the roles assigned to the user directly, followed by the roles it
+// has through its groups, which have the GROUP assignment type
+func (u *UserResource) ListAssignedRolesForUser(ctx context.Context, userID string, qp *query.Params) ([]*okta.Role, *okta.Response, error) {
+	u.Client.mu.RLock()
+	defer u.Client.mu.RUnlock()
+
+	user, err := u.getUserByID(userID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	roles, after := paginate(u.assignedRoles(user.Id), func(role *okta.Role) string { return role.Id }, qp)
+	return roles, u.Client.listResponse(ctx, fmt.Sprintf("/api/v1/users/%v/roles", userID), qp, after), nil
+}
+
+// assignedRoles returns the roles assigned to the user with the userID directly and through its groups
+func (u *UserResource) assignedRoles(userID string) []*okta.Role {
+	roles := append([]*okta.Role{}, u.UserRoles[userID]...)
+	for _, group := range u.Client.Group.Groups {
+		if SliceContainsString(u.Client.Group.GroupUsers[group.Id], userID) {
+			roles = append(roles, u.Client.Group.GroupRoles[group.Id]...)
+		}
+	}
+	return roles
+}
+
+// GetUserRole returns the role with the roleID assigned directly to the user
+func (u *UserResource) GetUserRole(ctx context.Context, userID string, roleID string) (*okta.Role, *okta.Response, error) {
+	u.Client.mu.RLock()
+	defer u.Client.mu.RUnlock()
+
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return role, newResponse(http.StatusOK, role), nil
+}
+
+// GetRole returns the role with the roleID assigned to the group
+func (g *GroupResource) GetRole(ctx context.Context, groupID string, roleID string) (*okta.Role, *okta.Response, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return role, newResponse(http.StatusOK, role), nil
+}
+
+// RemoveRoleFromUser unassigns the role assigned directly to the user with the roleID
+func (u *UserResource) RemoveRoleFromUser(ctx context.Context, userID string, roleID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	user, err := u.getUserByID(userID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	roles, ok := removeRole(u.UserRoles[user.Id], roleID)
+	if !ok {
+		err := newNotFoundError("Role", roleID)
+		return errorResponse(err), err
+	}
+	u.UserRoles[user.Id] = roles
+	u.Client.forgetRole(roleID)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// RemoveRoleFromGroup unassigns the role assigned to the group with the roleID
+func (g *GroupResource) RemoveRoleFromGroup(ctx context.Context, groupID string, roleID string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	group, err := g.getGroupByID(groupID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	roles, ok := removeRole(g.GroupRoles[group.Id], roleID)
+	if !ok {
+		err := newNotFoundError("Role", roleID)
+		return errorResponse(err), err
+	}
+	g.GroupRoles[group.Id] = roles
+	g.Client.forgetRole(roleID)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// unassignRole unassigns the role assignment with the roleID from its group or user
+func (client *MockClient) unassignRole(roleID string) {
+	for groupID, roles := range client.Group.GroupRoles {
+		client.Group.GroupRoles[groupID], _ = removeRole(roles, roleID)
+	}
+	for userID, roles := range client.User.UserRoles {
+		client.User.UserRoles[userID], _ = removeRole(roles, roleID)
+	}
+	client.forgetRole(roleID)
+}
+
+// forgetRole removes the targets and the custom role binding of the unassigned role with the roleID
+func (client *MockClient) forgetRole(roleID string) {
+	delete(client.Group.RoleTargets, roleID)
+	delete(client.User.RoleTargets, roleID)
+	delete(client.IAM.Assignments, roleID)
+}
+
+// removeRole returns the roles without the one with the roleID, and whether it was found
+func removeRole(roles []*okta.Role, roleID string) ([]*okta.Role, bool) {
+	for idx, role := range roles {
+		if role.Id == roleID {
+			return append(roles[:idx:idx], roles[idx+1:]...), true
+		}
+	}
+	return roles, false
+}
@@ -0,0 +1,247 @@
+package mockokta
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func TestUserResource_AssignRoleToUser(t *testing.T) {
+	t.Run("should assign the role to the user", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		role, resp, err := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		if err != nil || resp.StatusCode != 201 {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if role.Type != "USER_ADMIN" || role.AssignmentType != RoleAssignmentTypeUser || role.Status != RoleStatusActive {
+			t.Errorf("got %v want an active USER_ADMIN role assigned to the user", role)
+		}
+	})
+
+	t.Run("should err if the role is invalid", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		_, _, err := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("Invalid_Role"), nil)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should err if the role is already assigned", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		_, resp, err := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		if err == nil || resp.StatusCode != 409 {
+			t.Errorf("got %v want a duplicate role error", err)
+		}
+	})
+
+	t.Run("should err if the user doesn't exist", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.AssignRoleToUser(context.TODO(), "00u_missing", NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+}
+
+func TestUserResource_ListAssignedRolesForUser(t *testing.T) {
+	t.Run("should list direct roles followed by group roles", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Admins"))
+		client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		groupRole, _, _ := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("GROUP_ADMIN"), nil)
+		userRole, _, _ := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		got, _, _ := client.ListAssignedRolesForUser(context.TODO(), user.Id, nil)
+
+		want := []*okta.Role{userRole, groupRole}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+		if groupRole.AssignmentType != RoleAssignmentTypeGroup {
+			t.Errorf("got assignment type %v want %v", groupRole.AssignmentType, RoleAssignmentTypeGroup)
+		}
+	})
+
+	t.Run("should return an empty list for users without roles", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		got, _, _ := client.ListAssignedRolesForUser(context.TODO(), user.Id, nil)
+
+		if got == nil || len(got) != 0 {
+			t.Errorf("got %#v want an empty list", got)
+		}
+	})
+}
+
+func TestUserResource_RemoveRoleFromUser(t *testing.T) {
+	t.Run("should remove the role", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		role, _, _ := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		resp, err := client.RemoveRoleFromUser(context.TODO(), user.Id, role.Id)
+		got, _, _ := client.ListAssignedRolesForUser(context.TODO(), user.Id, nil)
+
+		if err != nil || resp.StatusCode != 204 || len(got) != 0 {
+			t.Errorf("got roles %v (%v) want none", got, err)
+		}
+	})
+
+	t.Run("should err removing a role the user only has through a group", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Admins"))
+		client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		role, _, _ := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("GROUP_ADMIN"), nil)
+
+		resp, err := client.RemoveRoleFromUser(context.TODO(), user.Id, role.Id)
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+
+	t.Run("should remove the roles of deleted users", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+
+		if len(client.User.UserRoles) != 0 {
+			t.Errorf("got roles %v want none", client.User.UserRoles)
+		}
+	})
+
+	t.Run("should remove the targets of the role", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target"))
+		role, _, _ := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("GROUP_ADMIN"), nil)
+		client.AddGroupTargetToRole(context.TODO(), user.Id, role.Id, group.Id)
+
+		client.RemoveRoleFromUser(context.TODO(), user.Id, role.Id)
+
+		if _, ok := client.User.RoleTargets[role.Id]; ok {
+			t.Errorf("got targets %v want none", client.User.RoleTargets[role.Id])
+		}
+	})
+}
+
+func TestUserResource_GetUserRole(t *testing.T) {
+	t.Run("should get the role", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		role, _, _ := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		got, resp, err := client.GetUserRole(context.TODO(), user.Id, role.Id)
+
+		if err != nil || resp.StatusCode != 200 || got.Type != "USER_ADMIN" {
+			t.Errorf("got %v (%v) want %v", got, err, role)
+		}
+	})
+
+	t.Run("should err if the role isn't assigned to the user", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		_, resp, err := client.GetUserRole(context.TODO(), user.Id, "ra1_missing")
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+}
+
+func TestGroupResource_RemoveRoleFromGroup(t *testing.T) {
+	t.Run("should remove the role", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Admins"))
+		role, _, _ := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("GROUP_ADMIN"), nil)
+
+		resp, err := client.RemoveRoleFromGroup(context.TODO(), group.Id, role.Id)
+
+		if err != nil || resp.StatusCode != 204 || client.Group.GroupContainsRole(*group, "GROUP_ADMIN") {
+			t.Errorf("got %v want the role to be removed", err)
+		}
+	})
+
+	t.Run("should err if the role isn't assigned to the group", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Admins"))
+
+		resp, err := client.RemoveRoleFromGroup(context.TODO(), group.Id, "ra1_missing")
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+
+	t.Run("should remove the targets of the role", func(t *testing.T) {
+		client := NewClient()
+		group, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+		target, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target"))
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), group.Id, role.Id, target.Id)
+
+		client.RemoveRoleFromGroup(context.TODO(), group.Id, role.Id)
+		client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("GROUP_ADMIN"), nil)
+
+		if _, ok := client.Group.RoleTargets[role.Id]; ok {
+			t.Errorf("got targets %v want none", client.Group.RoleTargets[role.Id])
+		}
+	})
+
+	t.Run("should remove the targets of the roles of deleted groups", func(t *testing.T) {
+		client := NewClient()
+		group, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+		target, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target"))
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), group.Id, role.Id, target.Id)
+
+		client.DeleteGroup(context.TODO(), group.Id)
+
+		if len(client.Group.RoleTargets) != 0 {
+			t.Errorf("got targets %v want none", client.Group.RoleTargets)
+		}
+	})
+}
+
+func TestGroupResource_GetRole(t *testing.T) {
+	t.Run("should get the role", func(t *testing.T) {
+		client := NewClient()
+		group, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+
+		got, resp, err := client.GetRole(context.TODO(), group.Id, role.Id)
+
+		if err != nil || resp.StatusCode != 200 || got.Id != role.Id {
+			t.Errorf("got %v (%v) want %v", got, err, role)
+		}
+	})
+
+	t.Run("should err if the role isn't assigned to the group", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Admins"))
+
+		_, resp, err := client.GetRole(context.TODO(), group.Id, "ra1_missing")
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+}
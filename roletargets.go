@@ -0,0 +1,586 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// groupTargetRoles are the role types that can be restricted to target groups, and appTargetRoles
+// the ones that can be restricted to target apps
+var (
+	groupTargetRoles = []string{"GROUP_ADMIN", "GROUP_MEMBERSHIP_ADMIN", "USER_ADMIN", "HELP_DESK_ADMIN"}
+	appTargetRoles   = []string{"APP_ADMIN"}
+)
+
+// RoleTargets are the groups and apps a role assignment is restricted to. A role assignment without
+// group targets applies to every group, and one without app targets to every app
+type RoleTargets struct {
+	Groups []string
+	Apps   []AppTarget
+}
+
+// AppTarget is an app a role assignment is restricted to: every instance of the app with the Name,
+// or only the instance with the ID if it is set
+type AppTarget struct {
+	Name string
+	ID   string
+}
+
+// AddGroupTargetToGroupAdministratorRoleForGroup is a wrapper to call client.Group.AddGroupTargetToGroupAdministratorRoleForGroup to make it easier to match an interface for the okta client
+func (client *MockClient) AddGroupTargetToGroupAdministratorRoleForGroup(ctx context.Context, groupID string, roleID string, targetGroupID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddGroupTargetToGroupAdministratorRoleForGroup", []interface{}{groupID, roleID, targetGroupID}, func() (*okta.Response, error) {
+		return client.Group.AddGroupTargetToGroupAdministratorRoleForGroup(ctx, groupID, roleID, targetGroupID)
+	})
+}
+
+// ListGroupTargetsForGroupRole is a wrapper to call client.Group.ListGroupTargetsForGroupRole to make it easier to match an interface for the okta client
+func (client *MockClient) ListGroupTargetsForGroupRole(ctx context.Context, groupID string, roleID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	return intercept(ctx, client, "ListGroupTargetsForGroupRole", []interface{}{groupID, roleID, qp}, func() ([]*okta.Group, *okta.Response, error) {
+		return client.Group.ListGroupTargetsForGroupRole(ctx, groupID, roleID, qp)
+	})
+}
+
+// RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup is a wrapper to call client.Group.RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup(ctx context.Context, groupID string, roleID string, targetGroupID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup", []interface{}{groupID, roleID, targetGroupID}, func() (*okta.Response, error) {
+		return client.Group.RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup(ctx, groupID, roleID, targetGroupID)
+	})
+}
+
+// AddApplicationTargetToAdminRoleGivenToGroup is a wrapper to call client.Group.AddApplicationTargetToAdminRoleGivenToGroup to make it easier to match an interface for the okta client
+func (client *MockClient) AddApplicationTargetToAdminRoleGivenToGroup(ctx context.Context, groupID string, roleID string, appName string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddApplicationTargetToAdminRoleGivenToGroup", []interface{}{groupID, roleID, appName}, func() (*okta.Response, error) {
+		return client.Group.AddApplicationTargetToAdminRoleGivenToGroup(ctx, groupID, roleID, appName)
+	})
+}
+
+// AddApplicationInstanceTargetToAppAdminRoleGivenToGroup is a wrapper to call client.Group.AddApplicationInstanceTargetToAppAdminRoleGivenToGroup to make it easier to match an interface for the okta client
+func (client *MockClient) AddApplicationInstanceTargetToAppAdminRoleGivenToGroup(ctx context.Context, groupID string, roleID string, appName string, applicationID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddApplicationInstanceTargetToAppAdminRoleGivenToGroup", []interface{}{groupID, roleID, appName, applicationID}, func() (*okta.Response, error) {
+		return client.Group.AddApplicationInstanceTargetToAppAdminRoleGivenToGroup(ctx, groupID, roleID, appName, applicationID)
+	})
+}
+
+// ListApplicationTargetsForApplicationAdministratorRoleForGroup is a wrapper to call client.Group.ListApplicationTargetsForApplicationAdministratorRoleForGroup to make it easier to match an interface for the okta client
+func (client *MockClient) ListApplicationTargetsForApplicationAdministratorRoleForGroup(ctx context.Context, groupID string, roleID string, qp *query.Params) ([]*okta.CatalogApplication, *okta.Response, error) {
+	return intercept(ctx, client, "ListApplicationTargetsForApplicationAdministratorRoleForGroup", []interface{}{groupID, roleID, qp}, func() ([]*okta.CatalogApplication, *okta.Response, error) {
+		return client.Group.ListApplicationTargetsForApplicationAdministratorRoleForGroup(ctx, groupID, roleID, qp)
+	})
+}
+
+// RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup is a wrapper to call client.Group.RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup(ctx context.Context, groupID string, roleID string, appName string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup", []interface{}{groupID, roleID, appName}, func() (*okta.Response, error) {
+		return client.Group.RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup(ctx, groupID, roleID, appName)
+	})
+}
+
+// RemoveApplicationTargetFromAdministratorRoleGivenToGroup is a wrapper to call client.Group.RemoveApplicationTargetFromAdministratorRoleGivenToGroup to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveApplicationTargetFromAdministratorRoleGivenToGroup(ctx context.Context, groupID string, roleID string, appName string, applicationID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveApplicationTargetFromAdministratorRoleGivenToGroup", []interface{}{groupID, roleID, appName, applicationID}, func() (*okta.Response, error) {
+		return client.Group.RemoveApplicationTargetFromAdministratorRoleGivenToGroup(ctx, groupID, roleID, appName, applicationID)
+	})
+}
+
+// AddGroupTargetToRole is a wrapper to call client.User.AddGroupTargetToRole to make it easier to match an interface for the okta client
+func (client *MockClient) AddGroupTargetToRole(ctx context.Context, userID string, roleID string, groupID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddGroupTargetToRole", []interface{}{userID, roleID, groupID}, func() (*okta.Response, error) {
+		return client.User.AddGroupTargetToRole(ctx, userID, roleID, groupID)
+	})
+}
+
+// ListGroupTargetsForRole is a wrapper to call client.User.ListGroupTargetsForRole to make it easier to match an interface for the okta client
+func (client *MockClient) ListGroupTargetsForRole(ctx context.Context, userID string, roleID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	return intercept(ctx, client, "ListGroupTargetsForRole", []interface{}{userID, roleID, qp}, func() ([]*okta.Group, *okta.Response, error) {
+		return client.User.ListGroupTargetsForRole(ctx, userID, roleID, qp)
+	})
+}
+
+// RemoveGroupTargetFromRole is a wrapper to call client.User.RemoveGroupTargetFromRole to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveGroupTargetFromRole(ctx context.Context, userID string, roleID string, groupID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveGroupTargetFromRole", []interface{}{userID, roleID, groupID}, func() (*okta.Response, error) {
+		return client.User.RemoveGroupTargetFromRole(ctx, userID, roleID, groupID)
+	})
+}
+
+// AddApplicationTargetToAdminRoleForUser is a wrapper to call client.User.AddApplicationTargetToAdminRoleForUser to make it easier to match an interface for the okta client
+func (client *MockClient) AddApplicationTargetToAdminRoleForUser(ctx context.Context, userID string, roleID string, appName string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddApplicationTargetToAdminRoleForUser", []interface{}{userID, roleID, appName}, func() (*okta.Response, error) {
+		return client.User.AddApplicationTargetToAdminRoleForUser(ctx, userID, roleID, appName)
+	})
+}
+
+// AddApplicationTargetToAppAdminRoleForUser is a wrapper to call client.User.AddApplicationTargetToAppAdminRoleForUser to make it easier to match an interface for the okta client
+func (client *MockClient) AddApplicationTargetToAppAdminRoleForUser(ctx context.Context, userID string, roleID string, appName string, applicationID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddApplicationTargetToAppAdminRoleForUser", []interface{}{userID, roleID, appName, applicationID}, func() (*okta.Response, error) {
+		return client.User.AddApplicationTargetToAppAdminRoleForUser(ctx, userID, roleID, appName, applicationID)
+	})
+}
+
+// AddAllAppsAsTargetToRole is a wrapper to call client.User.AddAllAppsAsTargetToRole to make it easier to match an interface for the okta client
+func (client *MockClient) AddAllAppsAsTargetToRole(ctx context.Context, userID string, roleID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "AddAllAppsAsTargetToRole", []interface{}{userID, roleID}, func() (*okta.Response, error) {
+		return client.User.AddAllAppsAsTargetToRole(ctx, userID, roleID)
+	})
+}
+
+// ListApplicationTargetsForApplicationAdministratorRoleForUser is a wrapper to call client.User.ListApplicationTargetsForApplicationAdministratorRoleForUser to make it easier to match an interface for the okta client
+func (client *MockClient) ListApplicationTargetsForApplicationAdministratorRoleForUser(ctx context.Context, userID string, roleID string, qp *query.Params) ([]*okta.CatalogApplication, *okta.Response, error) {
+	return intercept(ctx, client, "ListApplicationTargetsForApplicationAdministratorRoleForUser", []interface{}{userID, roleID, qp}, func() ([]*okta.CatalogApplication, *okta.Response, error) {
+		return client.User.ListApplicationTargetsForApplicationAdministratorRoleForUser(ctx, userID, roleID, qp)
+	})
+}
+
+// RemoveApplicationTargetFromApplicationAdministratorRoleForUser is a wrapper to call client.User.RemoveApplicationTargetFromApplicationAdministratorRoleForUser to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveApplicationTargetFromApplicationAdministratorRoleForUser(ctx context.Context, userID string, roleID string, appName string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveApplicationTargetFromApplicationAdministratorRoleForUser", []interface{}{userID, roleID, appName}, func() (*okta.Response, error) {
+		return client.User.RemoveApplicationTargetFromApplicationAdministratorRoleForUser(ctx, userID, roleID, appName)
+	})
+}
+
+// RemoveApplicationTargetFromAdministratorRoleForUser is a wrapper to call client.User.RemoveApplicationTargetFromAdministratorRoleForUser to make it easier to match an interface for the okta client
+func (client *MockClient) RemoveApplicationTargetFromAdministratorRoleForUser(ctx context.Context, userID string, roleID string, appName string, applicationID string) (*okta.Response, error) {
+	return interceptResponse(ctx, client, "RemoveApplicationTargetFromAdministratorRoleForUser", []interface{}{userID, roleID, appName, applicationID}, func() (*okta.Response, error) {
+		return client.User.RemoveApplicationTargetFromAdministratorRoleForUser(ctx, userID, roleID, appName, applicationID)
+	})
+}
+
+// getGroupRole returns the role with the roleID assigned to the group with the groupID
+func (g *GroupResource) getGroupRole(groupID string, roleID string) (*okta.Role, error) {
+	group, err := g.getGroupByID(groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range g.GroupRoles[group.Id] {
+		if role.Id == roleID {
+			return role, nil
+		}
+	}
+	return nil, newNotFoundError("Role", roleID)
+}
+
+// getUserRole returns the role with the roleID assigned directly to the user with the userID
+func (u *UserResource) getUserRole(userID string, roleID string) (*okta.Role, error) {
+	user, err := u.getUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range u.UserRoles[user.Id] {
+		if role.Id == roleID {
+			return role, nil
+		}
+	}
+	return nil, newNotFoundError("Role", roleID)
+}
+
+// AddGroupTargetToGroupAdministratorRoleForGroup restricts the role assigned to the group to the target group
+func (g *GroupResource) AddGroupTargetToGroupAdministratorRoleForGroup(ctx context.Context, groupID string, roleID string, targetGroupID string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return g.Client.addGroupTarget(role, g.RoleTargets, targetGroupID)
+}
+
+// ListGroupTargetsForGroupRole lists the groups the role assigned to the group is restricted to
+func (g *GroupResource) ListGroupTargetsForGroupRole(ctx context.Context, groupID string, roleID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return g.Client.listGroupTargets(ctx, fmt.Sprintf("/api/v1/groups/%v/roles/%v/targets/groups", groupID, roleID), g.RoleTargets[role.Id], qp)
+}
+
+// RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup removes the target group from the role assigned to the group
+func (g *GroupResource) RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup(ctx context.Context, groupID string, roleID string, targetGroupID string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return removeGroupTarget(role, g.RoleTargets, targetGroupID)
+}
+
+// AddApplicationTargetToAdminRoleGivenToGroup restricts the role assigned to the group to every instance of the app with the appName
+func (g *GroupResource) AddApplicationTargetToAdminRoleGivenToGroup(ctx context.Context, groupID string, roleID string, appName string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return g.Client.addAppTarget(role, g.RoleTargets, AppTarget{Name: appName})
+}
+
+// AddApplicationInstanceTargetToAppAdminRoleGivenToGroup restricts the role assigned to the group to the app instance with the applicationID
+func (g *GroupResource) AddApplicationInstanceTargetToAppAdminRoleGivenToGroup(ctx context.Context, groupID string, roleID string, appName string, applicationID string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return g.Client.addAppTarget(role, g.RoleTargets, AppTarget{Name: appName, ID: applicationID})
+}
+
+// ListApplicationTargetsForApplicationAdministratorRoleForGroup lists the apps the role assigned to the group is restricted to
+func (g *GroupResource) ListApplicationTargetsForApplicationAdministratorRoleForGroup(ctx context.Context, groupID string, roleID string, qp *query.Params) ([]*okta.CatalogApplication, *okta.Response, error) {
+	g.Client.mu.RLock()
+	defer g.Client.mu.RUnlock()
+
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return g.Client.listAppTargets(ctx, fmt.Sprintf("/api/v1/groups/%v/roles/%v/targets/catalog/apps", groupID, roleID), g.RoleTargets[role.Id], qp)
+}
+
+// RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup removes the target app with the appName from the role assigned to the group
+func (g *GroupResource) RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup(ctx context.Context, groupID string, roleID string, appName string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return removeAppTarget(role, g.RoleTargets, AppTarget{Name: appName})
+}
+
+// RemoveApplicationTargetFromAdministratorRoleGivenToGroup removes the target app instance with the applicationID from the role assigned to the group
+func (g *GroupResource) RemoveApplicationTargetFromAdministratorRoleGivenToGroup(ctx context.Context, groupID string, roleID string, appName string, applicationID string) (*okta.Response, error) {
+	g.Client.mu.Lock()
+	defer g.Client.mu.Unlock()
+
+	if err := g.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := g.getGroupRole(groupID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return removeAppTarget(role, g.RoleTargets, AppTarget{Name: appName, ID: applicationID})
+}
+
+// AddGroupTargetToRole restricts the role assigned to the user to the target group
+func (u *UserResource) AddGroupTargetToRole(ctx context.Context, userID string, roleID string, groupID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return u.Client.addGroupTarget(role, u.RoleTargets, groupID)
+}
+
+// ListGroupTargetsForRole lists the groups the role assigned to the user is restricted to
+func (u *UserResource) ListGroupTargetsForRole(ctx context.Context, userID string, roleID string, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	u.Client.mu.RLock()
+	defer u.Client.mu.RUnlock()
+
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return u.Client.listGroupTargets(ctx, fmt.Sprintf("/api/v1/users/%v/roles/%v/targets/groups", userID, roleID), u.RoleTargets[role.Id], qp)
+}
+
+// RemoveGroupTargetFromRole removes the target group from the role assigned to the user
+func (u *UserResource) RemoveGroupTargetFromRole(ctx context.Context, userID string, roleID string, groupID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return removeGroupTarget(role, u.RoleTargets, groupID)
+}
+
+// AddApplicationTargetToAdminRoleForUser restricts the role assigned to the user to every instance of the app with the appName
+func (u *UserResource) AddApplicationTargetToAdminRoleForUser(ctx context.Context, userID string, roleID string, appName string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return u.Client.addAppTarget(role, u.RoleTargets, AppTarget{Name: appName})
+}
+
+// AddApplicationTargetToAppAdminRoleForUser restricts the role assigned to the user to the app instance with the applicationID
+func (u *UserResource) AddApplicationTargetToAppAdminRoleForUser(ctx context.Context, userID string, roleID string, appName string, applicationID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return u.Client.addAppTarget(role, u.RoleTargets, AppTarget{Name: appName, ID: applicationID})
+}
+
+// AddAllAppsAsTargetToRole removes the app targets of the role assigned to the user, so it applies to every app again
+func (u *UserResource) AddAllAppsAsTargetToRole(ctx context.Context, userID string, roleID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	if err := checkTargetSupported(role, appTargetRoles, "app"); err != nil {
+		return errorResponse(err), err
+	}
+	if targets, ok := u.RoleTargets[role.Id]; ok {
+		targets.Apps = nil
+	}
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// ListApplicationTargetsForApplicationAdministratorRoleForUser lists the apps the role assigned to the user is restricted to
+func (u *UserResource) ListApplicationTargetsForApplicationAdministratorRoleForUser(ctx context.Context, userID string, roleID string, qp *query.Params) ([]*okta.CatalogApplication, *okta.Response, error) {
+	u.Client.mu.RLock()
+	defer u.Client.mu.RUnlock()
+
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return nil, errorResponse(err), err
+	}
+	return u.Client.listAppTargets(ctx, fmt.Sprintf("/api/v1/users/%v/roles/%v/targets/catalog/apps", userID, roleID), u.RoleTargets[role.Id], qp)
+}
+
+// RemoveApplicationTargetFromApplicationAdministratorRoleForUser removes the target app with the appName from the role assigned to the user
+func (u *UserResource) RemoveApplicationTargetFromApplicationAdministratorRoleForUser(ctx context.Context, userID string, roleID string, appName string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return removeAppTarget(role, u.RoleTargets, AppTarget{Name: appName})
+}
+
+// RemoveApplicationTargetFromAdministratorRoleForUser removes the target app instance with the applicationID from the role assigned to the user
+func (u *UserResource) RemoveApplicationTargetFromAdministratorRoleForUser(ctx context.Context, userID string, roleID string, appName string, applicationID string) (*okta.Response, error) {
+	u.Client.mu.Lock()
+	defer u.Client.mu.Unlock()
+
+	if err := u.Client.authorize(permissionManageRoles); err != nil {
+		return errorResponse(err), err
+	}
+	role, err := u.getUserRole(userID, roleID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	return removeAppTarget(role, u.RoleTargets, AppTarget{Name: appName, ID: applicationID})
+}
+
+// checkTargetSupported returns an error if the role's type can't be restricted to targets of the kind
+func checkTargetSupported(role *okta.Role, roleTypes []string, kind string) *okta.Error {
+	if !SliceContainsString(roleTypes, role.Type) {
+		return NewError(ErrorCodeUnsupportedOperation, fmt.Sprintf("Role type %v does not support %v targets", role.Type, kind))
+	}
+	return nil
+}
+
+// roleTargets returns the targets of the role, adding an empty entry for it if it has none yet
+func roleTargets(role *okta.Role, targets map[string]*RoleTargets) *RoleTargets {
+	if _, ok := targets[role.Id]; !ok {
+		targets[role.Id] = &RoleTargets{}
+	}
+	return targets[role.Id]
+}
+
+func (client *MockClient) addGroupTarget(role *okta.Role, targets map[string]*RoleTargets, groupID string) (*okta.Response, error) {
+	if err := checkTargetSupported(role, groupTargetRoles, "group"); err != nil {
+		return errorResponse(err), err
+	}
+	group, err := client.Group.getGroupByID(groupID)
+	if err != nil {
+		return errorResponse(err), err
+	}
+	t := roleTargets(role, targets)
+	if !SliceContainsString(t.Groups, group.Id) {
+		t.Groups = append(t.Groups, group.Id)
+	}
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// removeGroupTarget removes the target group from the role. Like Okta it won't remove the last one,
+// as that would silently widen the role to every group
+func removeGroupTarget(role *okta.Role, targets map[string]*RoleTargets, groupID string) (*okta.Response, error) {
+	t, ok := targets[role.Id]
+	if !ok || !SliceContainsString(t.Groups, groupID) {
+		err := newNotFoundError("RoleTarget", groupID)
+		return errorResponse(err), err
+	}
+	if len(t.Groups) == 1 {
+		err := NewError(ErrorCodeUnsupportedOperation, "The last group target of a role cannot be removed, unassign the role instead")
+		return errorResponse(err), err
+	}
+	t.Groups = removeString(t.Groups, groupID)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+func (client *MockClient) listGroupTargets(ctx context.Context, path string, targets *RoleTargets, qp *query.Params) ([]*okta.Group, *okta.Response, error) {
+	groups := make([]*okta.Group, 0)
+	if targets != nil {
+		for _, groupID := range targets.Groups {
+			if group, err := client.Group.getGroupByID(groupID); err == nil {
+				groups = append(groups, group)
+			}
+		}
+	}
+	page, after := paginate(groups, func(group *okta.Group) string { return group.Id }, qp)
+	return page, client.listResponse(ctx, path, qp, after), nil
+}
+
+// addAppTarget restricts the role to the app target. Targeting every instance of an app replaces any
+// targets of its single instances
+func (client *MockClient) addAppTarget(role *okta.Role, targets map[string]*RoleTargets, target AppTarget) (*okta.Response, error) {
+	if err := checkTargetSupported(role, appTargetRoles, "app"); err != nil {
+		return errorResponse(err), err
+	}
+	if target.ID != "" {
+		_, fields, err := client.Application.getApplicationFields(target.ID)
+		if err != nil || *fields.name != target.Name {
+			err := newNotFoundError("AppInstance", target.ID)
+			return errorResponse(err), err
+		}
+	}
+	t := roleTargets(role, targets)
+	apps := make([]AppTarget, 0, len(t.Apps)+1)
+	for _, existing := range t.Apps {
+		if existing == target || (existing.Name == target.Name && existing.ID == "") {
+			return newResponse(http.StatusNoContent, nil), nil
+		}
+		if existing.Name != target.Name || target.ID != "" {
+			apps = append(apps, existing)
+		}
+	}
+	t.Apps = append(apps, target)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+// removeAppTarget removes the app target from the role, refusing to remove the last one like removeGroupTarget
+func removeAppTarget(role *okta.Role, targets map[string]*RoleTargets, target AppTarget) (*okta.Response, error) {
+	t, ok := targets[role.Id]
+	idx := -1
+	if ok {
+		for i, existing := range t.Apps {
+			if existing == target {
+				idx = i
+			}
+		}
+	}
+	if idx < 0 {
+		err := newNotFoundError("RoleTarget", target.Name)
+		return errorResponse(err), err
+	}
+	if len(t.Apps) == 1 {
+		err := NewError(ErrorCodeUnsupportedOperation, "The last app target of a role cannot be removed, unassign the role instead")
+		return errorResponse(err), err
+	}
+	t.Apps = append(t.Apps[:idx:idx], t.Apps[idx+1:]...)
+	return newResponse(http.StatusNoContent, nil), nil
+}
+
+func (client *MockClient) listAppTargets(ctx context.Context, path string, targets *RoleTargets, qp *query.Params) ([]*okta.CatalogApplication, *okta.Response, error) {
+	apps := make([]*okta.CatalogApplication, 0)
+	if targets != nil {
+		for _, target := range targets.Apps {
+			apps = append(apps, &okta.CatalogApplication{Id: target.ID, Name: target.Name, Status: ApplicationStatusActive})
+		}
+	}
+	page, after := paginate(apps, func(app *okta.CatalogApplication) string { return app.Name + app.Id }, qp)
+	return page, client.listResponse(ctx, path, qp, after), nil
+}
+
+// removeGroupFromRoleTargets removes a deleted group from the targets of every role
+func (client *MockClient) removeGroupFromRoleTargets(groupID string) {
+	client.removeFromRoleTargets(func(t *RoleTargets) bool {
+		groups := removeString(t.Groups, groupID)
+		removed := len(groups) < len(t.Groups)
+		t.Groups = groups
+		return removed
+	})
+}
+
+// removeApplicationFromRoleTargets removes a deleted app instance from the targets of every role
+func (client *MockClient) removeApplicationFromRoleTargets(appID string) {
+	client.removeFromRoleTargets(func(t *RoleTargets) bool {
+		apps := make([]AppTarget, 0, len(t.Apps))
+		for _, target := range t.Apps {
+			if target.ID != appID {
+				apps = append(apps, target)
+			}
+		}
+		removed := len(apps) < len(t.Apps)
+		t.Apps = apps
+		return removed
+	})
+}
+
+// removeFromRoleTargets removes a deleted target from the targets of every role with remove, which
+// returns whether the role had it. Like Okta, the roles it was the last target of are unassigned, as
+// a role without targets would manage the whole org
+func (client *MockClient) removeFromRoleTargets(remove func(t *RoleTargets) bool) {
+	for _, all := range []map[string]*RoleTargets{client.Group.RoleTargets, client.User.RoleTargets} {
+		for roleID, t := range all {
+			if remove(t) && len(t.Groups) == 0 && len(t.Apps) == 0 {
+				client.unassignRole(roleID)
+			}
+		}
+	}
+}
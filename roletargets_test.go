@@ -0,0 +1,259 @@
+package mockokta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func newGroupWithRole(t *testing.T, client *MockClient, name string, roleType string) (*okta.Group, *okta.Role) {
+	t.Helper()
+	group, _, _ := client.CreateGroup(context.TODO(), *NewGroup(name))
+	role, _, err := client.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest(roleType), nil)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return group, role
+}
+
+func TestGroupResource_GroupTargets(t *testing.T) {
+	t.Run("should add, list and remove group targets", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+		target1, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target1"))
+		target2, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target2"))
+
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, target1.Id)
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, target2.Id)
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, target2.Id)
+		got, _, _ := client.ListGroupTargetsForGroupRole(context.TODO(), admins.Id, role.Id, nil)
+		if len(got) != 2 || got[0] != target1 || got[1] != target2 {
+			t.Errorf("got %v want [%v %v]", got, target1, target2)
+		}
+
+		if _, err := client.RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup(context.TODO(), admins.Id, role.Id, target1.Id); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		got, _, _ = client.ListGroupTargetsForGroupRole(context.TODO(), admins.Id, role.Id, nil)
+		if len(got) != 1 || got[0] != target2 {
+			t.Errorf("got %v want [%v]", got, target2)
+		}
+	})
+
+	t.Run("should err removing the last group target", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "USER_ADMIN")
+		target, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target"))
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, target.Id)
+
+		_, err := client.RemoveGroupTargetFromGroupAdministratorRoleGivenToGroup(context.TODO(), admins.Id, role.Id, target.Id)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should err for roles that don't take group targets", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "APP_ADMIN")
+
+		resp, err := client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, admins.Id)
+
+		if err == nil || resp.StatusCode != 400 {
+			t.Errorf("got %v want an unsupported operation error", err)
+		}
+	})
+
+	t.Run("should err for unknown roles and groups", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+
+		_, roleErr := client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, "ra1_missing", admins.Id)
+		_, groupErr := client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, "00g_missing")
+
+		if roleErr == nil || groupErr == nil {
+			t.Errorf("got %v and %v want not found errors", roleErr, groupErr)
+		}
+	})
+
+	t.Run("should drop deleted groups from targets", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+		target1, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target1"))
+		target2, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target2"))
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, target1.Id)
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, target2.Id)
+
+		client.DeleteGroup(context.TODO(), target1.Id)
+		got, _, _ := client.ListGroupTargetsForGroupRole(context.TODO(), admins.Id, role.Id, nil)
+
+		if len(got) != 1 || got[0] != target2 {
+			t.Errorf("got %v want [%v]", got, target2)
+		}
+	})
+
+	t.Run("should unassign roles when their last target group is deleted", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+		target, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target"))
+		client.AddGroupTargetToGroupAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, target.Id)
+
+		client.DeleteGroup(context.TODO(), target.Id)
+		roles, _, _ := client.ListGroupAssignedRoles(context.TODO(), admins.Id, nil)
+
+		if len(roles) != 0 || client.Group.RoleTargets[role.Id] != nil {
+			t.Errorf("got roles %v want none", roles)
+		}
+	})
+}
+
+func TestGroupResource_AppTargets(t *testing.T) {
+	t.Run("should add app and app instance targets", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "APP_ADMIN")
+		app, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("Payroll"), nil)
+		saml := app.(*okta.SamlApplication)
+
+		client.AddApplicationTargetToAdminRoleGivenToGroup(context.TODO(), admins.Id, role.Id, "bookmark")
+		client.AddApplicationInstanceTargetToAppAdminRoleGivenToGroup(context.TODO(), admins.Id, role.Id, saml.Name, saml.Id)
+		got, _, _ := client.ListApplicationTargetsForApplicationAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, nil)
+
+		if len(got) != 2 || got[0].Name != "bookmark" || got[0].Id != "" || got[1].Name != saml.Name || got[1].Id != saml.Id {
+			t.Errorf("got %v want the bookmark app and the %v instance", got, saml.Id)
+		}
+	})
+
+	t.Run("should replace instance targets when targeting the whole app", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "APP_ADMIN")
+		app, _, _ := client.CreateApplication(context.TODO(), NewBookmarkApplication("Wiki", "https://wiki.example.com"), nil)
+		bookmark := app.(*okta.BookmarkApplication)
+		client.AddApplicationInstanceTargetToAppAdminRoleGivenToGroup(context.TODO(), admins.Id, role.Id, bookmark.Name, bookmark.Id)
+
+		client.AddApplicationTargetToAdminRoleGivenToGroup(context.TODO(), admins.Id, role.Id, bookmark.Name)
+		got, _, _ := client.ListApplicationTargetsForApplicationAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, nil)
+
+		if len(got) != 1 || got[0].Id != "" {
+			t.Errorf("got %v want only the bookmark app", got)
+		}
+	})
+
+	t.Run("should err for an instance that isn't of the app", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "APP_ADMIN")
+		app, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("Payroll"), nil)
+
+		resp, err := client.AddApplicationInstanceTargetToAppAdminRoleGivenToGroup(context.TODO(), admins.Id, role.Id, "bookmark", appID(app))
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+
+	t.Run("should err for roles that don't take app targets", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+
+		_, err := client.AddApplicationTargetToAdminRoleGivenToGroup(context.TODO(), admins.Id, role.Id, "bookmark")
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should remove app targets", func(t *testing.T) {
+		client := NewClient()
+		admins, role := newGroupWithRole(t, client, "Admins", "APP_ADMIN")
+		client.AddApplicationTargetToAdminRoleGivenToGroup(context.TODO(), admins.Id, role.Id, "bookmark")
+		client.AddApplicationTargetToAdminRoleGivenToGroup(context.TODO(), admins.Id, role.Id, "oidc_client")
+
+		_, err := client.RemoveApplicationTargetFromApplicationAdministratorRoleGivenToGroup(context.TODO(), admins.Id, role.Id, "bookmark")
+		got, _, _ := client.ListApplicationTargetsForApplicationAdministratorRoleForGroup(context.TODO(), admins.Id, role.Id, nil)
+
+		if err != nil || len(got) != 1 || got[0].Name != "oidc_client" {
+			t.Errorf("got %v (%v) want only oidc_client", got, err)
+		}
+	})
+}
+
+func TestUserResource_RoleTargets(t *testing.T) {
+	t.Run("should add and remove group targets of user roles", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		role, _, _ := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("HELP_DESK_ADMIN"), nil)
+		target1, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target1"))
+		target2, _, _ := client.CreateGroup(context.TODO(), *NewGroup("Target2"))
+
+		client.AddGroupTargetToRole(context.TODO(), user.Id, role.Id, target1.Id)
+		client.AddGroupTargetToRole(context.TODO(), user.Id, role.Id, target2.Id)
+		client.RemoveGroupTargetFromRole(context.TODO(), user.Id, role.Id, target1.Id)
+		got, _, _ := client.ListGroupTargetsForRole(context.TODO(), user.Id, role.Id, nil)
+
+		if len(got) != 1 || got[0] != target2 {
+			t.Errorf("got %v want [%v]", got, target2)
+		}
+	})
+
+	t.Run("should clear app targets when adding all apps", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		role, _, _ := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("APP_ADMIN"), nil)
+		client.AddApplicationTargetToAdminRoleForUser(context.TODO(), user.Id, role.Id, "bookmark")
+
+		client.AddAllAppsAsTargetToRole(context.TODO(), user.Id, role.Id)
+		got, _, _ := client.ListApplicationTargetsForApplicationAdministratorRoleForUser(context.TODO(), user.Id, role.Id, nil)
+
+		if len(got) != 0 {
+			t.Errorf("got %v want no targets", got)
+		}
+	})
+
+	t.Run("should drop deleted app instances from targets", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		role, _, _ := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("APP_ADMIN"), nil)
+		app, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("Payroll"), nil)
+		saml := app.(*okta.SamlApplication)
+		client.AddApplicationTargetToAdminRoleForUser(context.TODO(), user.Id, role.Id, "bookmark")
+		client.AddApplicationTargetToAppAdminRoleForUser(context.TODO(), user.Id, role.Id, saml.Name, saml.Id)
+
+		client.DeactivateApplication(context.TODO(), saml.Id)
+		client.DeleteApplication(context.TODO(), saml.Id)
+		got, _, _ := client.ListApplicationTargetsForApplicationAdministratorRoleForUser(context.TODO(), user.Id, role.Id, nil)
+
+		if len(got) != 1 || got[0].Name != "bookmark" {
+			t.Errorf("got %v want only bookmark", got)
+		}
+	})
+
+	t.Run("should unassign roles when their last target app instance is deleted", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		role, _, _ := client.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("APP_ADMIN"), nil)
+		app, _, _ := client.CreateApplication(context.TODO(), NewSamlApplication("Payroll"), nil)
+		saml := app.(*okta.SamlApplication)
+		client.AddApplicationTargetToAppAdminRoleForUser(context.TODO(), user.Id, role.Id, saml.Name, saml.Id)
+
+		client.DeactivateApplication(context.TODO(), saml.Id)
+		client.DeleteApplication(context.TODO(), saml.Id)
+		roles, _, _ := client.ListAssignedRolesForUser(context.TODO(), user.Id, nil)
+
+		if len(roles) != 0 || client.User.RoleTargets[role.Id] != nil {
+			t.Errorf("got roles %v want none", roles)
+		}
+	})
+
+	t.Run("should err for roles the user only has through a group", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		admins, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+		client.AddUserToGroup(context.TODO(), admins.Id, user.Id)
+
+		resp, err := client.AddGroupTargetToRole(context.TODO(), user.Id, role.Id, admins.Id)
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+}
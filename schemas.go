@@ -0,0 +1,366 @@
+package mockokta
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// DefaultSchemaID is the ID of the default user type's schema, the only user schema the mock has
+const DefaultSchemaID = "default"
+
+// SchemaResource contains the user and group schemas that user and group profiles are validated against
+type SchemaResource struct {
+	Client      *MockClient
+	UserSchema  *okta.UserSchema
+	GroupSchema *okta.GroupSchema
+}
+
+// GetUserSchema is a wrapper to call client.Schema.GetUserSchema to make it easier to match an interface for the okta client
+func (client *MockClient) GetUserSchema(ctx context.Context, schemaID string) (*okta.UserSchema, *okta.Response, error) {
+	return intercept(ctx, client, "GetUserSchema", []interface{}{schemaID}, func() (*okta.UserSchema, *okta.Response, error) {
+		return client.Schema.GetUserSchema(ctx, schemaID)
+	})
+}
+
+// UpdateUserProfile is a wrapper to call client.Schema.UpdateUserProfile to make it easier to match an interface for the okta client
+func (client *MockClient) UpdateUserProfile(ctx context.Context, schemaID string, body okta.UserSchema) (*okta.UserSchema, *okta.Response, error) {
+	return intercept(ctx, client, "UpdateUserProfile", []interface{}{schemaID, body}, func() (*okta.UserSchema, *okta.Response, error) {
+		return client.Schema.UpdateUserProfile(ctx, schemaID, body)
+	})
+}
+
+// GetGroupSchema is a wrapper to call client.Schema.GetGroupSchema to make it easier to match an interface for the okta client
+func (client *MockClient) GetGroupSchema(ctx context.Context) (*okta.GroupSchema, *okta.Response, error) {
+	return intercept(ctx, client, "GetGroupSchema", nil, func() (*okta.GroupSchema, *okta.Response, error) {
+		return client.Schema.GetGroupSchema(ctx)
+	})
+}
+
+// UpdateGroupSchema is a wrapper to call client.Schema.UpdateGroupSchema to make it easier to match an interface for the okta client
+func (client *MockClient) UpdateGroupSchema(ctx context.Context, body okta.GroupSchema) (*okta.GroupSchema, *okta.Response, error) {
+	return intercept(ctx, client, "UpdateGroupSchema", []interface{}{body}, func() (*okta.GroupSchema, *okta.Response, error) {
+		return client.Schema.UpdateGroupSchema(ctx, body)
+	})
+}
+
+// NewDefaultGroupSchema returns the group schema new clients validate group profiles against: a
+// required name and an optional description, and no custom attributes
+func NewDefaultGroupSchema() *okta.GroupSchema {
+	minName, maxName, maxDescription := int64(1), int64(255), int64(1024)
+	return &okta.GroupSchema{
+		Id:          "#base",
+		Name:        "group",
+		Title:       "Okta group",
+		Description: "Okta group profile template",
+		Type:        "object",
+		Definitions: &okta.GroupSchemaDefinitions{
+			Base: &okta.GroupSchemaBase{
+				Id:   "#base",
+				Type: "object",
+				Properties: map[string]*okta.GroupSchemaAttribute{
+					"name": {
+						Title:     "Name",
+						Type:      SchemaTypeString,
+						Required:  boolPtr(true),
+						MinLength: &minName,
+						MaxLength: &maxName,
+					},
+					"description": {Title: "Description", Type: SchemaTypeString, MaxLength: &maxDescription},
+				},
+				Required: []string{"name"},
+			},
+			Custom: &okta.GroupSchemaCustom{
+				Id:         "#custom",
+				Type:       "object",
+				Properties: make(map[string]*okta.GroupSchemaAttribute),
+				Required:   make([]string, 0),
+			},
+		},
+	}
+}
+
+// WithGroupSchema makes the client validate group profiles against the schema instead of the default one
+func WithGroupSchema(schema *okta.GroupSchema) ClientOption {
+	return func(c *MockClient) {
+		c.Schema.GroupSchema = schema
+	}
+}
+
+// GetUserSchema returns a copy of the user schema with the schemaID, which must be the default one
+func (s *SchemaResource) GetUserSchema(ctx context.Context, schemaID string) (*okta.UserSchema, *okta.Response, error) {
+	s.Client.mu.RLock()
+	defer s.Client.mu.RUnlock()
+
+	if schemaID != DefaultSchemaID {
+		err := newNotFoundError("UserSchema", schemaID)
+		return nil, errorResponse(err), err
+	}
+	schema := deepCopy(s.UserSchema)
+	return schema, newResponse(http.StatusOK, schema), nil
+}
+
+// UpdateUserProfile partially updates the user schema with the schemaID. Custom attributes in the body
+// are added or replaced, or removed if they are nil, and base attributes can be changed but not added,
+// removed or given another type. Users already created are not revalidated
+func (s *SchemaResource) UpdateUserProfile(ctx context.Context, schemaID string, body okta.UserSchema) (*okta.UserSchema, *okta.Response, error) {
+	s.Client.mu.Lock()
+	defer s.Client.mu.Unlock()
+
+	if schemaID != DefaultSchemaID {
+		err := newNotFoundError("UserSchema", schemaID)
+		return nil, errorResponse(err), err
+	}
+	if body.Definitions == nil {
+		schema := deepCopy(s.UserSchema)
+		return schema, newResponse(http.StatusOK, schema), nil
+	}
+	definitions := userSchemaDefinitions(s.UserSchema)
+	base, custom := make(map[string]*okta.UserSchemaAttribute), make(map[string]*okta.UserSchemaAttribute)
+	if body.Definitions.Base != nil {
+		base = body.Definitions.Base.Properties
+	}
+	if body.Definitions.Custom != nil {
+		custom = body.Definitions.Custom.Properties
+	}
+	if err := checkSchemaUpdate(base, custom, definitions.Base.Properties); err != nil {
+		return nil, errorResponse(err), err
+	}
+	for name, attribute := range base {
+		updated := *attribute
+		updated.Type = definitions.Base.Properties[name].Type
+		definitions.Base.Properties[name] = &updated
+	}
+	for name, attribute := range custom {
+		if attribute == nil {
+			delete(definitions.Custom.Properties, name)
+			continue
+		}
+		updated := *attribute
+		definitions.Custom.Properties[name] = &updated
+	}
+	definitions.Base.Required = requiredAttributes(definitions.Base.Properties)
+	definitions.Custom.Required = requiredAttributes(definitions.Custom.Properties)
+	s.UserSchema.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	schema := deepCopy(s.UserSchema)
+	return schema, newResponse(http.StatusOK, schema), nil
+}
+
+// GetGroupSchema returns a copy of the group schema
+func (s *SchemaResource) GetGroupSchema(ctx context.Context) (*okta.GroupSchema, *okta.Response, error) {
+	s.Client.mu.RLock()
+	defer s.Client.mu.RUnlock()
+
+	schema := deepCopy(s.GroupSchema)
+	return schema, newResponse(http.StatusOK, schema), nil
+}
+
+// UpdateGroupSchema partially updates the group schema the same way UpdateUserProfile updates the
+// user schema. Groups already created are not revalidated
+func (s *SchemaResource) UpdateGroupSchema(ctx context.Context, body okta.GroupSchema) (*okta.GroupSchema, *okta.Response, error) {
+	s.Client.mu.Lock()
+	defer s.Client.mu.Unlock()
+
+	if body.Definitions == nil {
+		schema := deepCopy(s.GroupSchema)
+		return schema, newResponse(http.StatusOK, schema), nil
+	}
+	definitions := groupSchemaDefinitions(s.GroupSchema)
+	base, custom := make(map[string]*okta.UserSchemaAttribute), make(map[string]*okta.UserSchemaAttribute)
+	if body.Definitions.Base != nil {
+		for name, attribute := range body.Definitions.Base.Properties {
+			base[name] = userAttribute(attribute)
+		}
+	}
+	if body.Definitions.Custom != nil {
+		for name, attribute := range body.Definitions.Custom.Properties {
+			custom[name] = userAttribute(attribute)
+		}
+	}
+	existing := make(map[string]*okta.UserSchemaAttribute)
+	for name, attribute := range definitions.Base.Properties {
+		existing[name] = userAttribute(attribute)
+	}
+	if err := checkSchemaUpdate(base, custom, existing); err != nil {
+		return nil, errorResponse(err), err
+	}
+	if body.Definitions.Base != nil {
+		for name, attribute := range body.Definitions.Base.Properties {
+			updated := *attribute
+			updated.Type = definitions.Base.Properties[name].Type
+			definitions.Base.Properties[name] = &updated
+		}
+	}
+	if body.Definitions.Custom != nil {
+		for name, attribute := range body.Definitions.Custom.Properties {
+			if attribute == nil {
+				delete(definitions.Custom.Properties, name)
+				continue
+			}
+			updated := *attribute
+			definitions.Custom.Properties[name] = &updated
+		}
+	}
+	definitions.Base.Required = requiredGroupAttributes(definitions.Base.Properties)
+	definitions.Custom.Required = requiredGroupAttributes(definitions.Custom.Properties)
+	s.GroupSchema.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	schema := deepCopy(s.GroupSchema)
+	return schema, newResponse(http.StatusOK, schema), nil
+}
+
+// userSchemaDefinitions returns the definitions of the schema, adding any that are missing so they can be updated
+func userSchemaDefinitions(schema *okta.UserSchema) *okta.UserSchemaDefinitions {
+	if schema.Definitions == nil {
+		schema.Definitions = &okta.UserSchemaDefinitions{}
+	}
+	if schema.Definitions.Base == nil {
+		schema.Definitions.Base = &okta.UserSchemaBase{Id: "#base", Type: "object"}
+	}
+	if schema.Definitions.Base.Properties == nil {
+		schema.Definitions.Base.Properties = make(map[string]*okta.UserSchemaAttribute)
+	}
+	if schema.Definitions.Custom == nil {
+		schema.Definitions.Custom = &okta.UserSchemaPublic{Id: "#custom", Type: "object"}
+	}
+	if schema.Definitions.Custom.Properties == nil {
+		schema.Definitions.Custom.Properties = make(map[string]*okta.UserSchemaAttribute)
+	}
+	return schema.Definitions
+}
+
+// groupSchemaDefinitions returns the definitions of the schema, adding any that are missing so they can be updated
+func groupSchemaDefinitions(schema *okta.GroupSchema) *okta.GroupSchemaDefinitions {
+	if schema.Definitions == nil {
+		schema.Definitions = &okta.GroupSchemaDefinitions{}
+	}
+	if schema.Definitions.Base == nil {
+		schema.Definitions.Base = &okta.GroupSchemaBase{Id: "#base", Type: "object"}
+	}
+	if schema.Definitions.Base.Properties == nil {
+		schema.Definitions.Base.Properties = make(map[string]*okta.GroupSchemaAttribute)
+	}
+	if schema.Definitions.Custom == nil {
+		schema.Definitions.Custom = &okta.GroupSchemaCustom{Id: "#custom", Type: "object"}
+	}
+	if schema.Definitions.Custom.Properties == nil {
+		schema.Definitions.Custom.Properties = make(map[string]*okta.GroupSchemaAttribute)
+	}
+	return schema.Definitions
+}
+
+// checkSchemaUpdate returns an error if the base attribute changes would add, remove or retype a base
+// attribute, or the custom attribute changes would shadow a base attribute or use an unknown type
+func checkSchemaUpdate(base map[string]*okta.UserSchemaAttribute, custom map[string]*okta.UserSchemaAttribute, existing map[string]*okta.UserSchemaAttribute) *okta.Error {
+	for _, name := range sortedAttributeNames(base) {
+		current, ok := existing[name]
+		if !ok {
+			return newValidationError(name, "Cannot add a base attribute")
+		}
+		if base[name] == nil {
+			return newValidationError(name, "Cannot remove a base attribute")
+		}
+		if base[name].Type != "" && base[name].Type != current.Type {
+			return newValidationError(name, "Cannot change the type of a base attribute")
+		}
+	}
+	for _, name := range sortedAttributeNames(custom) {
+		attribute := custom[name]
+		if _, ok := existing[name]; ok {
+			return newValidationError(name, "An object with this field already exists in the current organization")
+		}
+		if attribute == nil {
+			continue
+		}
+		if !SliceContainsString([]string{SchemaTypeString, SchemaTypeBoolean, SchemaTypeNumber, SchemaTypeInteger, SchemaTypeArray}, attribute.Type) {
+			return newValidationError(name, fmt.Sprintf("Invalid attribute type: '%s'", attribute.Type))
+		}
+	}
+	return nil
+}
+
+// validateGroupProfile checks a group profile's name, description and custom attributes against the group schema
+func (s *SchemaResource) validateGroupProfile(profile okta.GroupProfile) *okta.Error {
+	attributes := make(map[string]*okta.UserSchemaAttribute)
+	required := make([]string, 0)
+	if s.GroupSchema != nil && s.GroupSchema.Definitions != nil {
+		definitions := s.GroupSchema.Definitions
+		for _, properties := range []map[string]*okta.GroupSchemaAttribute{baseGroupProperties(definitions), customGroupProperties(definitions)} {
+			for name, attribute := range properties {
+				attributes[name] = userAttribute(attribute)
+				if attribute != nil && attribute.Required != nil && *attribute.Required {
+					required = append(required, name)
+				}
+			}
+		}
+	}
+	values := make(map[string]interface{})
+	for name, value := range profile.GroupProfileMap {
+		values[name] = value
+	}
+	values["name"] = profile.Name
+	if profile.Description != "" {
+		values["description"] = profile.Description
+	}
+	return validateAttributes(attributes, required, values)
+}
+
+func baseGroupProperties(definitions *okta.GroupSchemaDefinitions) map[string]*okta.GroupSchemaAttribute {
+	if definitions.Base == nil {
+		return nil
+	}
+	return definitions.Base.Properties
+}
+
+func customGroupProperties(definitions *okta.GroupSchemaDefinitions) map[string]*okta.GroupSchemaAttribute {
+	if definitions.Custom == nil {
+		return nil
+	}
+	return definitions.Custom.Properties
+}
+
+// userAttribute converts a group schema attribute to a user schema one, so they can be validated the same way
+func userAttribute(attribute *okta.GroupSchemaAttribute) *okta.UserSchemaAttribute {
+	if attribute == nil {
+		return nil
+	}
+	return &okta.UserSchemaAttribute{
+		Description: attribute.Description,
+		Enum:        attribute.Enum,
+		Items:       attribute.Items,
+		MaxLength:   attribute.MaxLength,
+		MinLength:   attribute.MinLength,
+		Required:    attribute.Required,
+		Title:       attribute.Title,
+		Type:        attribute.Type,
+		Unique:      attribute.Unique,
+	}
+}
+
+func requiredAttributes(properties map[string]*okta.UserSchemaAttribute) []string {
+	required := make([]string, 0)
+	for _, name := range sortedAttributeNames(properties) {
+		if attribute := properties[name]; attribute.Required != nil && *attribute.Required {
+			required = append(required, name)
+		}
+	}
+	return required
+}
+
+func requiredGroupAttributes(properties map[string]*okta.GroupSchemaAttribute) []string {
+	converted := make(map[string]*okta.UserSchemaAttribute)
+	for name, attribute := range properties {
+		converted[name] = userAttribute(attribute)
+	}
+	return requiredAttributes(converted)
+}
+
+func sortedAttributeNames(properties map[string]*okta.UserSchemaAttribute) []string {
+	names := make(map[string]interface{})
+	for name := range properties {
+		names[name] = nil
+	}
+	return sortedKeys(names)
+}
@@ -0,0 +1,201 @@
+package mockokta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func customUserAttributes(attributes map[string]*okta.UserSchemaAttribute) okta.UserSchema {
+	return okta.UserSchema{Definitions: &okta.UserSchemaDefinitions{Custom: &okta.UserSchemaPublic{Properties: attributes}}}
+}
+
+func customGroupAttributes(attributes map[string]*okta.GroupSchemaAttribute) okta.GroupSchema {
+	return okta.GroupSchema{Definitions: &okta.GroupSchemaDefinitions{Custom: &okta.GroupSchemaCustom{Properties: attributes}}}
+}
+
+func TestSchemaResource_UpdateUserProfile(t *testing.T) {
+	t.Run("should let users be created with a new custom attribute", func(t *testing.T) {
+		client := NewClient()
+		request := NewCreateUserRequest("TestUser@test.com")
+		(*request.Profile)["employeeId"] = "1234"
+		if _, _, err := client.User.CreateUser(context.TODO(), request, nil); err == nil {
+			t.Fatalf("expected error for an undefined attribute but didn't get one")
+		}
+
+		client.UpdateUserProfile(context.TODO(), DefaultSchemaID, customUserAttributes(map[string]*okta.UserSchemaAttribute{
+			"employeeId": {Title: "Employee ID", Type: SchemaTypeString},
+		}))
+		_, _, err := client.User.CreateUser(context.TODO(), request, nil)
+
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should enforce required custom attributes", func(t *testing.T) {
+		client := NewClient()
+		schema, _, _ := client.UpdateUserProfile(context.TODO(), DefaultSchemaID, customUserAttributes(map[string]*okta.UserSchemaAttribute{
+			"employeeId": {Type: SchemaTypeString, Required: boolPtr(true)},
+		}))
+
+		_, _, err := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+		if got := schema.Definitions.Custom.Required; len(got) != 1 || got[0] != "employeeId" {
+			t.Errorf("got required %v want [employeeId]", got)
+		}
+	})
+
+	t.Run("should remove custom attributes set to nil", func(t *testing.T) {
+		client := NewClient()
+		client.UpdateUserProfile(context.TODO(), DefaultSchemaID, customUserAttributes(map[string]*okta.UserSchemaAttribute{
+			"employeeId": {Type: SchemaTypeString},
+		}))
+
+		schema, _, _ := client.UpdateUserProfile(context.TODO(), DefaultSchemaID, customUserAttributes(map[string]*okta.UserSchemaAttribute{
+			"employeeId": nil,
+		}))
+
+		if _, ok := schema.Definitions.Custom.Properties["employeeId"]; ok {
+			t.Errorf("expected employeeId to be removed from %v", schema.Definitions.Custom.Properties)
+		}
+	})
+
+	t.Run("should update base attributes but keep their type", func(t *testing.T) {
+		client := NewClient()
+
+		client.UpdateUserProfile(context.TODO(), DefaultSchemaID, okta.UserSchema{Definitions: &okta.UserSchemaDefinitions{
+			Base: &okta.UserSchemaBase{Properties: map[string]*okta.UserSchemaAttribute{
+				"firstName": {Title: "First name", Required: boolPtr(true)},
+			}},
+		}})
+		schema, _, _ := client.GetUserSchema(context.TODO(), DefaultSchemaID)
+		_, _, err := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		if schema.Definitions.Base.Properties["firstName"].Type != SchemaTypeString {
+			t.Errorf("got type %v want %v", schema.Definitions.Base.Properties["firstName"].Type, SchemaTypeString)
+		}
+		if err == nil {
+			t.Errorf("expected error for missing firstName but didn't get one")
+		}
+	})
+
+	tests := []struct {
+		name string
+		body okta.UserSchema
+	}{
+		{"should err adding a base attribute", okta.UserSchema{Definitions: &okta.UserSchemaDefinitions{
+			Base: &okta.UserSchemaBase{Properties: map[string]*okta.UserSchemaAttribute{"employeeId": {Type: SchemaTypeString}}},
+		}}},
+		{"should err removing a base attribute", okta.UserSchema{Definitions: &okta.UserSchemaDefinitions{
+			Base: &okta.UserSchemaBase{Properties: map[string]*okta.UserSchemaAttribute{"firstName": nil}},
+		}}},
+		{"should err changing the type of a base attribute", okta.UserSchema{Definitions: &okta.UserSchemaDefinitions{
+			Base: &okta.UserSchemaBase{Properties: map[string]*okta.UserSchemaAttribute{"firstName": {Type: SchemaTypeBoolean}}},
+		}}},
+		{"should err adding a custom attribute with a base attribute's name", customUserAttributes(map[string]*okta.UserSchemaAttribute{
+			"department": {Type: SchemaTypeString},
+		})},
+		{"should err adding a custom attribute with an unknown type", customUserAttributes(map[string]*okta.UserSchemaAttribute{
+			"employeeId": {Type: "date"},
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient()
+
+			_, resp, err := client.UpdateUserProfile(context.TODO(), DefaultSchemaID, tt.body)
+
+			if err == nil || resp.StatusCode != 400 {
+				t.Errorf("got %v want a validation error", err)
+			}
+		})
+	}
+
+	t.Run("should err for an unknown schema", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.GetUserSchema(context.TODO(), "oty_unknown")
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+}
+
+func TestSchemaResource_UpdateGroupSchema(t *testing.T) {
+	t.Run("should validate groups against custom attributes", func(t *testing.T) {
+		client := NewClient()
+		client.UpdateGroupSchema(context.TODO(), customGroupAttributes(map[string]*okta.GroupSchemaAttribute{
+			"costCenter": {Type: SchemaTypeString, Required: boolPtr(true)},
+		}))
+		group := NewGroup("TestGroup")
+
+		if _, _, err := client.CreateGroup(context.TODO(), *group); err == nil {
+			t.Errorf("expected error for missing costCenter but didn't get one")
+		}
+		group.Profile.GroupProfileMap = okta.GroupProfileMap{"costCenter": "CC-1"}
+		if _, _, err := client.CreateGroup(context.TODO(), *group); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should err updating a group with an undefined attribute", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		update := *NewGroup("TestGroup")
+		update.Profile.GroupProfileMap = okta.GroupProfileMap{"costCenter": "CC-1"}
+
+		_, _, err := client.UpdateGroup(context.TODO(), group.Id, update)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should err changing the type of the name", func(t *testing.T) {
+		client := NewClient()
+
+		_, _, err := client.UpdateGroupSchema(context.TODO(), okta.GroupSchema{Definitions: &okta.GroupSchemaDefinitions{
+			Base: &okta.GroupSchemaBase{Properties: map[string]*okta.GroupSchemaAttribute{"name": {Type: SchemaTypeInteger}}},
+		}})
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should use a group schema passed to the client", func(t *testing.T) {
+		schema := NewDefaultGroupSchema()
+		maxName := int64(5)
+		schema.Definitions.Base.Properties["name"].MaxLength = &maxName
+		client := NewClient(WithGroupSchema(schema))
+
+		_, _, err := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		got, _, _ := client.GetGroupSchema(context.TODO())
+
+		if err == nil {
+			t.Errorf("expected error for a name longer than 5 but didn't get one")
+		}
+		if got := got.Definitions.Base.Properties["name"].MaxLength; got == nil || *got != maxName {
+			t.Errorf("got max length %v want %v", got, maxName)
+		}
+	})
+
+	t.Run("should return copies of the schema", func(t *testing.T) {
+		client := NewClient()
+		schema, _, _ := client.GetGroupSchema(context.TODO())
+		maxName := int64(5)
+		schema.Definitions.Base.Properties["name"].MaxLength = &maxName
+
+		_, _, err := client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+}
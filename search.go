@@ -0,0 +1,497 @@
+package mockokta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+// attributeLookup resolves an attribute path such as "profile.name" to its value on a resource
+type attributeLookup func(attribute string) (interface{}, bool)
+
+// expression is a parsed search or filter expression that can be evaluated against a resource
+type expression interface {
+	evaluate(lookup attributeLookup) bool
+}
+
+type logicalExpression struct {
+	operator string
+	left     expression
+	right    expression
+}
+
+func (e logicalExpression) evaluate(lookup attributeLookup) bool {
+	if e.operator == "and" {
+		return e.left.evaluate(lookup) && e.right.evaluate(lookup)
+	}
+	return e.left.evaluate(lookup) || e.right.evaluate(lookup)
+}
+
+type notExpression struct {
+	inner expression
+}
+
+func (e notExpression) evaluate(lookup attributeLookup) bool {
+	return !e.inner.evaluate(lookup)
+}
+
+type comparisonExpression struct {
+	attribute string
+	operator  string
+	value     interface{}
+}
+
+func (e comparisonExpression) evaluate(lookup attributeLookup) bool {
+	actual, ok := lookup(e.attribute)
+	if t, isTime := actual.(*time.Time); isTime && t == nil {
+		actual = nil
+	}
+	if e.operator == "pr" {
+		return ok && actual != nil && actual != ""
+	}
+	if !ok {
+		return false
+	}
+	return compareValues(actual, e.operator, e.value)
+}
+
+var comparisonOperators = []string{"eq", "ne", "gt", "ge", "lt", "le", "sw", "co", "ew", "pr"}
+
+// compareValues applies a comparison operator to the attribute value and the literal from the expression
+func compareValues(actual interface{}, operator string, literal interface{}) bool {
+	switch a := actual.(type) {
+	case *time.Time:
+		return compareValues(*a, operator, literal)
+	case time.Time:
+		s, ok := literal.(string)
+		if !ok {
+			return false
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return false
+		}
+		switch {
+		case a.Before(t):
+			return compareOrdered(-1, operator)
+		case a.After(t):
+			return compareOrdered(1, operator)
+		}
+		return compareOrdered(0, operator)
+	case string:
+		s, ok := literal.(string)
+		if !ok {
+			return operator == "ne"
+		}
+		la, ls := strings.ToLower(a), strings.ToLower(s)
+		switch operator {
+		case "sw":
+			return strings.HasPrefix(la, ls)
+		case "ew":
+			return strings.HasSuffix(la, ls)
+		case "co":
+			return strings.Contains(la, ls)
+		}
+		return compareOrdered(strings.Compare(la, ls), operator)
+	case bool:
+		b, ok := literal.(bool)
+		if !ok {
+			return operator == "ne"
+		}
+		if operator == "eq" {
+			return a == b
+		}
+		return operator == "ne" && a != b
+	case nil:
+		if operator == "eq" {
+			return literal == nil
+		}
+		return operator == "ne" && literal != nil
+	}
+	if a, ok := toFloat(actual); ok {
+		f, ok := literal.(float64)
+		if !ok {
+			return operator == "ne"
+		}
+		switch {
+		case a < f:
+			return compareOrdered(-1, operator)
+		case a > f:
+			return compareOrdered(1, operator)
+		}
+		return compareOrdered(0, operator)
+	}
+	return false
+}
+
+// compareOrdered converts the result of a three-way comparison into the result of the operator
+func compareOrdered(cmp int, operator string) bool {
+	switch operator {
+	case "eq":
+		return cmp == 0
+	case "ne":
+		return cmp != 0
+	case "gt":
+		return cmp > 0
+	case "ge":
+		return cmp >= 0
+	case "lt":
+		return cmp < 0
+	case "le":
+		return cmp <= 0
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenOpenParen
+	tokenCloseParen
+	tokenComma
+	tokenOperator
+	tokenEnd
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// tokenize splits a search or filter expression into words, quoted strings, numbers and parentheses
+func tokenize(expr string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpenParen, value: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenCloseParen, value: ")"})
+			i++
+		case r == '"':
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string in expression %q", expr)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: sb.String()})
+		case r == '-' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[start:i])})
+		default:
+			return nil, fmt.Errorf("unexpected character %q in expression %q", r, expr)
+		}
+	}
+	return append(tokens, token{kind: tokenEnd}), nil
+}
+
+type expressionParser struct {
+	tokens []token
+	pos    int
+}
+
+// parseExpression parses a SCIM style search or filter expression such as
+// `profile.name sw "eng-" and type eq "OKTA_GROUP"`
+func parseExpression(expr string) (expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &expressionParser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected %q in expression", p.peek().value)
+	}
+	return e, nil
+}
+
+func (p *expressionParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+func (p *expressionParser) peekKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenWord && strings.EqualFold(t.value, keyword)
+}
+
+func (p *expressionParser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpression{operator: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpression{operator: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseUnary() (expression, error) {
+	if p.peekKeyword("not") {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{inner: inner}, nil
+	}
+	if p.peek().kind == tokenOpenParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenCloseParen {
+			return nil, fmt.Errorf("missing closing parenthesis in expression")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *expressionParser) parseComparison() (expression, error) {
+	attribute := p.next()
+	if attribute.kind != tokenWord {
+		return nil, fmt.Errorf("expected attribute but found %q", attribute.value)
+	}
+	operator := p.next()
+	op := strings.ToLower(operator.value)
+	if operator.kind != tokenWord || !SliceContainsString(comparisonOperators, op) {
+		return nil, fmt.Errorf("invalid operator %q for attribute %v", operator.value, attribute.value)
+	}
+	if op == "pr" {
+		return comparisonExpression{attribute: attribute.value, operator: op}, nil
+	}
+	literal := p.next()
+	var value interface{}
+	switch literal.kind {
+	case tokenString:
+		value = literal.value
+	case tokenNumber:
+		f, err := strconv.ParseFloat(literal.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", literal.value)
+		}
+		value = f
+	case tokenWord:
+		switch strings.ToLower(literal.value) {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			return nil, fmt.Errorf("invalid value %q for attribute %v", literal.value, attribute.value)
+		}
+	default:
+		return nil, fmt.Errorf("missing value for attribute %v", attribute.value)
+	}
+	return comparisonExpression{attribute: attribute.value, operator: op, value: value}, nil
+}
+
+// groupAttributes returns an attributeLookup for the searchable attributes of a group
+func groupAttributes(group *okta.Group) attributeLookup {
+	return func(attribute string) (interface{}, bool) {
+		switch attribute {
+		case "id":
+			return group.Id, true
+		case "type":
+			return group.Type, true
+		case "created":
+			return group.Created, true
+		case "lastUpdated":
+			return group.LastUpdated, true
+		case "lastMembershipUpdated":
+			return group.LastMembershipUpdated, true
+		}
+		if group.Profile == nil || !strings.HasPrefix(attribute, "profile.") {
+			return nil, false
+		}
+		name := strings.TrimPrefix(attribute, "profile.")
+		switch name {
+		case "name":
+			return group.Profile.Name, true
+		case "description":
+			return group.Profile.Description, true
+		}
+		value, ok := group.Profile.GroupProfileMap[name]
+		return value, ok
+	}
+}
+
+// matchGroups returns the groups matching the q, search and filter fields of the query params
+func matchGroups(groups []*okta.Group, qp *query.Params) ([]*okta.Group, error) {
+	matched := make([]*okta.Group, 0)
+	if qp == nil {
+		return append(matched, groups...), nil
+	}
+	expressions := make([]expression, 0)
+	for _, expr := range []string{qp.Search, qp.Filter} {
+		if expr == "" {
+			continue
+		}
+		e, err := parseExpression(expr)
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, e)
+	}
+	for _, group := range groups {
+		if qp.Q != "" && (group.Profile == nil || !strings.HasPrefix(strings.ToLower(group.Profile.Name), strings.ToLower(qp.Q))) {
+			continue
+		}
+		if matchesAll(expressions, groupAttributes(group)) {
+			matched = append(matched, group)
+		}
+	}
+	return matched, nil
+}
+
+func matchesAll(expressions []expression, lookup attributeLookup) bool {
+	for _, e := range expressions {
+		if !e.evaluate(lookup) {
+			return false
+		}
+	}
+	return true
+}
+
+// applicationAttributes returns an attributeLookup for the filterable attributes of an app's fields
+func applicationAttributes(fields appFields) attributeLookup {
+	return func(attribute string) (interface{}, bool) {
+		switch attribute {
+		case "id":
+			return *fields.id, true
+		case "name":
+			return *fields.name, true
+		case "label":
+			return *fields.label, true
+		case "status":
+			return *fields.status, true
+		case "signOnMode":
+			return *fields.signOnMode, true
+		case "created":
+			return *fields.created, true
+		case "lastUpdated":
+			return *fields.lastUpdated, true
+		}
+		return nil, false
+	}
+}
+
+// matchApplications returns the apps matching the q and filter fields of the query params, leaving
+// out apps of types fieldsOf doesn't support
+func matchApplications(apps []okta.App, qp *query.Params) ([]okta.App, error) {
+	matched := make([]okta.App, 0)
+	expressions := make([]expression, 0)
+	q := ""
+	if qp != nil && qp.Filter != "" {
+		e, err := parseExpression(qp.Filter)
+		if err != nil {
+			return nil, err
+		}
+		expressions = append(expressions, e)
+	}
+	if qp != nil {
+		q = strings.ToLower(qp.Q)
+	}
+	for _, app := range apps {
+		fields, ok := fieldsOf(app)
+		if !ok {
+			continue
+		}
+		if q != "" && !strings.HasPrefix(strings.ToLower(*fields.name), q) && !strings.HasPrefix(strings.ToLower(*fields.label), q) {
+			continue
+		}
+		if matchesAll(expressions, applicationAttributes(fields)) {
+			matched = append(matched, app)
+		}
+	}
+	return matched, nil
+}
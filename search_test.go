@@ -0,0 +1,80 @@
+package mockokta
+
+import (
+	"testing"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func TestParseExpression(t *testing.T) {
+	lastUpdated := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	group := &okta.Group{
+		Id:          "00g1",
+		Type:        "OKTA_GROUP",
+		LastUpdated: &lastUpdated,
+		Profile: &okta.GroupProfile{
+			Name:        "eng-backend",
+			Description: "Backend engineers",
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`profile.name eq "eng-backend"`, true},
+		{`profile.name eq "ENG-BACKEND"`, true},
+		{`profile.name eq "eng"`, false},
+		{`profile.name sw "eng-"`, true},
+		{`profile.description co "end eng"`, true},
+		{`profile.name ew "backend"`, true},
+		{`type eq "OKTA_GROUP"`, true},
+		{`type eq "APP_GROUP"`, false},
+		{`type ne "APP_GROUP"`, true},
+		{`lastUpdated gt "2022-01-01T00:00:00.000Z"`, true},
+		{`lastUpdated lt "2022-01-01T00:00:00.000Z"`, false},
+		{`lastUpdated ge "2022-06-01T00:00:00Z"`, true},
+		{`lastMembershipUpdated pr`, false},
+		{`profile.description pr`, true},
+		{`type eq "APP_GROUP" or profile.name sw "eng"`, true},
+		{`type eq "APP_GROUP" and profile.name sw "eng"`, false},
+		{`type eq "OKTA_GROUP" and (profile.name eq "x" or id eq "00g1")`, true},
+		{`not (type eq "OKTA_GROUP")`, false},
+		{`profile.missing eq "x"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := parseExpression(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			got := e.evaluate(groupAttributes(group))
+
+			if got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpression_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		`profile.name`,
+		`profile.name equals "x"`,
+		`profile.name eq`,
+		`profile.name eq "x`,
+		`(type eq "OKTA_GROUP"`,
+		`type eq "OKTA_GROUP" and`,
+		`type eq "OKTA_GROUP" "x"`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := parseExpression(expr)
+
+			if err == nil {
+				t.Errorf("expected error but didn't get one")
+			}
+		})
+	}
+}
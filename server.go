@@ -0,0 +1,41 @@
+package mockokta
+
+import (
+	"context"
+	"net/http/httptest"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// Server is a local HTTPS server exposing a MockClient as a fake Okta org, so anything that talks to
+// the Okta API, including a genuine *okta.Client, can be tested against the mock's state
+type Server struct {
+	*httptest.Server
+	Client *MockClient
+}
+
+// NewServer starts a Server backed by a new MockClient. Callers should Close it when finished
+func NewServer() *Server {
+	return NewServerForClient(NewClient())
+}
+
+// NewServerForClient starts a Server serving the state of an existing MockClient, so changes made
+// through either the server or the client are visible to both
+func NewServerForClient(client *MockClient) *Server {
+	return &Server{
+		Server: httptest.NewTLSServer(client.handler()),
+		Client: client,
+	}
+}
+
+// OktaClient creates a genuine *okta.Client configured to send its requests to the server
+func (s *Server) OktaClient(ctx context.Context, conf ...okta.ConfigSetter) (context.Context, *okta.Client, error) {
+	conf = append([]okta.ConfigSetter{
+		okta.WithOrgUrl(s.URL),
+		okta.WithToken("mockokta"),
+		okta.WithAuthorizationMode("SSWS"),
+		okta.WithCache(false),
+		okta.WithHttpClientPtr(s.Server.Client()),
+	}, conf...)
+	return okta.NewClient(ctx, conf...)
+}
@@ -0,0 +1,434 @@
+package mockokta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+	"github.com/okta/okta-sdk-golang/v2/okta/query"
+)
+
+func newTestServer(t *testing.T) (*Server, *okta.Client) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	_, oktaClient, err := server.OktaClient(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return server, oktaClient
+}
+
+func TestServer_Groups(t *testing.T) {
+	t.Run("should manage groups and memberships with the okta client", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+
+		group, _, err := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		user, _, err := oktaClient.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if _, err := oktaClient.Group.AddUserToGroup(context.TODO(), group.Id, user.Id); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		users, _, err := oktaClient.Group.ListGroupUsers(context.TODO(), group.Id, nil)
+
+		if err != nil || len(users) != 1 || users[0].Id != user.Id {
+			t.Errorf("got %v (%v) want user %v", users, err, user.Id)
+		}
+		mockGroup, _ := server.Client.Group.GetGroupByID(group.Id)
+		if !server.Client.Group.GroupContainsUser(*mockGroup, "TestUser@test.com") {
+			t.Errorf("expected mock client to see membership added through the server")
+		}
+	})
+
+	t.Run("should assign and list roles", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		group, _, _ := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		role, _, err := oktaClient.Group.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		roles, _, _ := oktaClient.Group.ListGroupAssignedRoles(context.TODO(), group.Id, nil)
+
+		if len(roles) != 1 || roles[0].Id != role.Id || roles[0].Type != "USER_ADMIN" {
+			t.Errorf("got %v want %v", roles, role)
+		}
+	})
+
+	t.Run("should get and remove group roles", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		group, _, _ := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		role, _, _ := oktaClient.Group.AssignRoleToGroup(context.TODO(), group.Id, NewAssignRoleRequest("USER_ADMIN"), nil)
+
+		got, _, err := oktaClient.Group.GetRole(context.TODO(), group.Id, role.Id)
+		if err != nil || got.Id != role.Id {
+			t.Errorf("got %v (%v) want %v", got, err, role)
+		}
+		if _, err := oktaClient.Group.RemoveRoleFromGroup(context.TODO(), group.Id, role.Id); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		_, resp, err := oktaClient.Group.GetRole(context.TODO(), group.Id, role.Id)
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
+
+	t.Run("should see groups created on the mock client", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		group, _, _ := server.Client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		got, _, err := oktaClient.Group.GetGroup(context.TODO(), group.Id)
+
+		if err != nil || got.Profile.Name != "TestGroup" {
+			t.Errorf("got %v (%v) want %v", got, err, group)
+		}
+	})
+
+	t.Run("should update groups", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		group, _, _ := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		got, _, err := oktaClient.Group.UpdateGroup(context.TODO(), group.Id, *NewGroup("RenamedGroup"))
+
+		if err != nil || got.Profile.Name != "RenamedGroup" {
+			t.Errorf("got %v (%v) want renamed group", got, err)
+		}
+		if _, err := server.Client.Group.GetGroupByName("RenamedGroup"); err != nil {
+			t.Errorf("expected mock client to see group renamed through the server")
+		}
+	})
+
+	t.Run("should manage group rules", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		group, _, _ := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		request := NewCreateUserRequest("TestUser@test.com")
+		(*request.Profile)["department"] = "Engineering"
+		oktaClient.User.CreateUser(context.TODO(), request, nil)
+
+		rule, _, err := oktaClient.Group.CreateGroupRule(context.TODO(), NewGroupRule("TestRule", `user.department == "Engineering"`, group.Id))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if _, err := oktaClient.Group.ActivateGroupRule(context.TODO(), rule.Id); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		rules, _, _ := oktaClient.Group.ListGroupRules(context.TODO(), nil)
+
+		if len(rules) != 1 || rules[0].Status != GroupRuleStatusActive {
+			t.Errorf("got %v want an active rule", rules)
+		}
+		if !server.Client.Group.GroupContainsUser(*group, "TestUser@test.com") {
+			t.Errorf("expected rule to assign the user")
+		}
+		if _, err := oktaClient.Group.DeleteGroupRule(context.TODO(), rule.Id, query.NewQueryParams(query.WithRemoveUsers(true))); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should delete groups", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		group, _, _ := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		resp, err := oktaClient.Group.DeleteGroup(context.TODO(), group.Id)
+
+		if err != nil || resp.StatusCode != http.StatusNoContent || len(server.Client.Group.Groups) != 1 {
+			t.Errorf("expected group to be deleted but got %v (%v)", resp.StatusCode, err)
+		}
+	})
+
+	t.Run("should paginate with absolute links to the server", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		for i := 0; i < 2; i++ {
+			server.Client.CreateGroup(context.TODO(), *NewGroup(fmt.Sprintf("TestGroup%d", i)))
+		}
+
+		groups, resp, _ := oktaClient.Group.ListGroups(context.TODO(), query.NewQueryParams(query.WithLimit(2)))
+		var next []*okta.Group
+		_, err := resp.Next(context.TODO(), &next)
+
+		if err != nil || len(groups) != 2 || len(next) != 1 {
+			t.Errorf("got %v then %v (%v) want pages of 2 and 1", groups, next, err)
+		}
+		for _, link := range resp.Header.Values("Link") {
+			if !strings.Contains(link, server.URL) {
+				t.Errorf("expected link %v to point at %v", link, server.URL)
+			}
+		}
+	})
+}
+
+func TestServer_Users(t *testing.T) {
+	t.Run("should run the user lifecycle", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		request := NewCreateUserRequest("TestUser@test.com")
+		request.Credentials = &okta.UserCredentials{Password: &okta.PasswordCredential{Value: "Password1!"}}
+		user, _, _ := oktaClient.User.CreateUser(context.TODO(), request, query.NewQueryParams(query.WithActivate(true)))
+
+		if _, err := oktaClient.User.SuspendUser(context.TODO(), user.Id); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		got, _, _ := oktaClient.User.GetUser(context.TODO(), "TestUser@test.com")
+
+		if got.Status != UserStatusSuspended {
+			t.Errorf("got status %v want %v", got.Status, UserStatusSuspended)
+		}
+	})
+
+	t.Run("should update user profiles", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		user, _, _ := oktaClient.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		if _, _, err := oktaClient.User.PartialUpdateUser(context.TODO(), user.Id, okta.User{Profile: &okta.UserProfile{"department": "Engineering"}}, nil); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		got, _, _ := oktaClient.User.GetUser(context.TODO(), user.Id)
+
+		if (*got.Profile)["department"] != "Engineering" {
+			t.Errorf("got profile %v want department Engineering", *got.Profile)
+		}
+
+		_, resp, err := oktaClient.User.UpdateUser(context.TODO(), user.Id, okta.User{Profile: &okta.UserProfile{"department": "Sales"}}, nil)
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+	})
+
+	t.Run("should add and remove custom attributes with the schema API", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		request := NewCreateUserRequest("TestUser@test.com")
+		(*request.Profile)["employeeId"] = "1234"
+
+		if _, _, err := oktaClient.UserSchema.UpdateUserProfile(context.TODO(), DefaultSchemaID, customUserAttributes(map[string]*okta.UserSchemaAttribute{
+			"employeeId": {Title: "Employee ID", Type: SchemaTypeString},
+		})); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if _, _, err := oktaClient.User.CreateUser(context.TODO(), request, nil); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		schema, _, _ := oktaClient.UserSchema.UpdateUserProfile(context.TODO(), DefaultSchemaID, customUserAttributes(map[string]*okta.UserSchemaAttribute{
+			"employeeId": nil,
+		}))
+
+		if _, ok := schema.Definitions.Custom.Properties["employeeId"]; ok {
+			t.Errorf("expected employeeId to be removed from %v", schema.Definitions.Custom.Properties)
+		}
+		(*request.Profile)["email"], (*request.Profile)["login"] = "TestUser2@test.com", "TestUser2@test.com"
+		_, resp, err := oktaClient.User.CreateUser(context.TODO(), request, nil)
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+	})
+
+	t.Run("should manage role targets", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		user, _, _ := oktaClient.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		group, _, _ := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("Target"))
+		role, _, _ := oktaClient.User.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("GROUP_ADMIN"), nil)
+
+		if _, err := oktaClient.User.AddGroupTargetToRole(context.TODO(), user.Id, role.Id, group.Id); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		groups, _, _ := oktaClient.User.ListGroupTargetsForRole(context.TODO(), user.Id, role.Id, nil)
+		if len(groups) != 1 || groups[0].Id != group.Id {
+			t.Errorf("got %v want [%v]", groups, group)
+		}
+
+		resp, err := oktaClient.User.AddApplicationTargetToAdminRoleForUser(context.TODO(), user.Id, role.Id, "bookmark")
+
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+	})
+
+	t.Run("should assign and remove user roles", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		user, _, _ := oktaClient.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		role, _, err := oktaClient.User.AssignRoleToUser(context.TODO(), user.Id, NewAssignRoleRequest("HELP_DESK_ADMIN"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		roles, _, _ := oktaClient.User.ListAssignedRolesForUser(context.TODO(), user.Id, nil)
+		if len(roles) != 1 || roles[0].Id != role.Id {
+			t.Errorf("got %v want %v", roles, role)
+		}
+		if _, err := oktaClient.User.RemoveRoleFromUser(context.TODO(), user.Id, role.Id); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		resp, err := oktaClient.User.RemoveRoleFromUser(context.TODO(), user.Id, role.Id)
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
+
+	t.Run("should return okta errors", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		user, _, _ := oktaClient.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), query.NewQueryParams(query.WithActivate(false)))
+
+		resp, err := oktaClient.User.SuspendUser(context.TODO(), user.Id)
+
+		assertOktaError(t, resp, err, ErrorCodeValidation)
+	})
+
+	t.Run("should return injected faults", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		group, _, _ := server.Client.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		server.Client.InjectFault("GetGroup", WithArgs(group.Id), Fault{Err: NewError(ErrorCodeInternalError)}, 1)
+
+		_, resp, err := oktaClient.Group.GetGroup(context.TODO(), group.Id)
+		_, _, retryErr := oktaClient.Group.GetGroup(context.TODO(), group.Id)
+
+		assertOktaError(t, resp, err, ErrorCodeInternalError)
+		if retryErr != nil {
+			t.Errorf("unexpected error %v", retryErr)
+		}
+	})
+
+	t.Run("should return rate limit errors and headers", func(t *testing.T) {
+		server := NewServer()
+		t.Cleanup(server.Close)
+		_, oktaClient, err := server.OktaClient(context.TODO(), okta.WithRateLimitMaxRetries(0))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		server.Client.SetRateLimits(RateLimit{Path: "/api/v1/users", Limit: 1, Window: time.Minute})
+
+		_, first, firstErr := oktaClient.User.ListUsers(context.TODO(), nil)
+		_, _, err = oktaClient.User.ListUsers(context.TODO(), nil)
+		resp, httpErr := server.Server.Client().Get(server.URL + "/api/v1/users")
+		if httpErr != nil {
+			t.Fatalf("unexpected error %v", httpErr)
+		}
+		defer resp.Body.Close()
+		var oktaErr okta.Error
+		json.NewDecoder(resp.Body).Decode(&oktaErr)
+
+		if firstErr != nil || first.Header.Get("X-Rate-Limit-Remaining") != "0" {
+			t.Errorf("got %v (%v) want 0 requests remaining", first.Header, firstErr)
+		}
+		if err == nil {
+			t.Errorf("got no error want the okta client to give up")
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || oktaErr.ErrorCode != ErrorCodeRateLimited || resp.Header.Get("X-Rate-Limit-Reset") == "" {
+			t.Errorf("got %v %v (%v) want a 429", resp.StatusCode, oktaErr.ErrorCode, resp.Header)
+		}
+	})
+
+	t.Run("should return 404 for unknown users", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+
+		_, resp, err := oktaClient.User.GetUser(context.TODO(), "NonExistentId")
+
+		assertOktaError(t, resp, err, ErrorCodeNotFound)
+	})
+
+	t.Run("should return 404 for unknown paths", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+
+		_, resp, err := oktaClient.NetworkZone.ListNetworkZones(context.TODO(), nil)
+
+		assertOktaError(t, resp, err, ErrorCodePathNotFound)
+	})
+}
+
+func TestServer_Applications(t *testing.T) {
+	t.Run("should manage apps and assignments with the okta client", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		app, _, err := oktaClient.Application.CreateApplication(context.TODO(), NewOpenIDConnectApplication("My OIDC App"), nil)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		id := app.(*okta.OpenIdConnectApplication).Id
+		group, _, _ := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		user, _, _ := oktaClient.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		oktaClient.Group.AddUserToGroup(context.TODO(), group.Id, user.Id)
+
+		if _, _, err := oktaClient.Application.CreateApplicationGroupAssignment(context.TODO(), id, group.Id, okta.ApplicationGroupAssignment{}); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		appUsers, _, err := oktaClient.Application.ListApplicationUsers(context.TODO(), id, nil)
+
+		if err != nil || len(appUsers) != 1 || appUsers[0].Id != user.Id || appUsers[0].Scope != AppUserScopeGroup {
+			t.Errorf("got %v (%v) want %v assigned through the group", appUsers, err, user.Id)
+		}
+		if _, err := oktaClient.Application.DeactivateApplication(context.TODO(), id); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		apps, _, _ := oktaClient.Application.ListApplications(context.TODO(), query.NewQueryParams(query.WithFilter(`status eq "INACTIVE"`)))
+		if len(apps) != 1 || apps[0].(*okta.Application).Id != id {
+			t.Errorf("got %v want the deactivated app", apps)
+		}
+		if _, err := server.Client.DeleteApplication(context.TODO(), id); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should return 403 deleting an active app", func(t *testing.T) {
+		_, oktaClient := newTestServer(t)
+		app, _, _ := oktaClient.Application.CreateApplication(context.TODO(), NewSamlApplication("App"), nil)
+
+		resp, err := oktaClient.Application.DeleteApplication(context.TODO(), app.(*okta.SamlApplication).Id)
+
+		assertOktaError(t, resp, err, ErrorCodeDeleteAppForbidden)
+	})
+}
+
+func TestServer_IAM(t *testing.T) {
+	t.Run("should assign custom roles through the role assignment API", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		group, _, _ := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		do := func(method string, path string, body interface{}, v interface{}) (*okta.Response, error) {
+			re := oktaClient.CloneRequestExecutor()
+			req, err := re.WithAccept("application/json").WithContentType("application/json").NewRequest(method, path, body)
+			if err != nil {
+				return nil, err
+			}
+			return re.Do(context.TODO(), req, v)
+		}
+
+		var role CustomRole
+		if _, err := do(http.MethodPost, "/api/v1/iam/roles", NewCustomRole("UserManager", "okta.users.manage"), &role); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		var resourceSet ResourceSet
+		if _, err := do(http.MethodPost, "/api/v1/iam/resource-sets", NewCreateResourceSetRequest("EngineeringUsers", ResourceHref("groups/"+group.Id+"/users")), &resourceSet); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		var assigned okta.Role
+		if _, err := do(http.MethodPost, fmt.Sprintf("/api/v1/groups/%v/roles", group.Id), NewCustomRoleAssignRequest(role.Id, resourceSet.Id), &assigned); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		roles, _, _ := oktaClient.Group.ListGroupAssignedRoles(context.TODO(), group.Id, nil)
+		if len(roles) != 1 || roles[0].Type != CustomRoleType || roles[0].Id != assigned.Id {
+			t.Errorf("got %v want the CUSTOM role assignment", roles)
+		}
+		if got := server.Client.IAM.Assignments[assigned.Id]; got.Role != role.Id || got.ResourceSet != resourceSet.Id {
+			t.Errorf("got assignment %v want %v over %v", got, role.Id, resourceSet.Id)
+		}
+
+		resp, err := do(http.MethodDelete, "/api/v1/iam/roles/"+role.Id, nil, nil)
+
+		assertOktaError(t, resp, err, ErrorCodeUnsupportedOperation)
+	})
+}
+
+func TestServer_Principal(t *testing.T) {
+	t.Run("should return forbidden errors for calls the principal can't make", func(t *testing.T) {
+		server, oktaClient := newTestServer(t)
+		oktaClient.User.CreateUser(context.TODO(), NewCreateUserRequest("svc@test.com"), nil)
+		server.Client.SetPrincipal("svc@test.com")
+
+		_, resp, err := oktaClient.Group.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+
+		assertOktaError(t, resp, err, ErrorCodeForbidden)
+	})
+}
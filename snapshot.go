@@ -0,0 +1,138 @@
+package mockokta
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// Snapshot is a copy of the state of a MockClient: its groups, users, memberships, roles and their
+// targets, group rules, apps, schemas and custom roles. Its fields are unexported so it can't change
+// after it is taken, and restoring it copies it again, so it can be restored any number of times
+type Snapshot struct {
+	group       GroupResource
+	user        UserResource
+	schema      SchemaResource
+	application ApplicationResource
+	iam         IAMResource
+}
+
+// Snapshot returns a copy of the current state of the client, so tests can seed an org once and
+// Restore it between subtests instead of seeding it again
+func (client *MockClient) Snapshot() *Snapshot {
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+
+	return &Snapshot{
+		group:       client.Group.copy(),
+		user:        client.User.copy(),
+		schema:      client.Schema.copy(),
+		application: client.Application.copy(),
+		iam:         client.IAM.copy(),
+	}
+}
+
+// Restore rolls the state of the client back to the snapshot, which may have been taken from another
+// client. The resources keep their identity, so references to client.Group and the others stay
+// valid. The principal is not part of the state and is left unchanged, and IDs generated after the
+// restore are still never ones the client generated before
+func (client *MockClient) Restore(snapshot *Snapshot) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	*client.Group = snapshot.group.copy()
+	*client.User = snapshot.user.copy()
+	*client.Schema = snapshot.schema.copy()
+	*client.Application = snapshot.application.copy()
+	*client.IAM = snapshot.iam.copy()
+	client.Group.Client = client
+	client.User.Client = client
+	client.Schema.Client = client
+	client.Application.Client = client
+	client.IAM.Client = client
+	client.reserveIDs()
+}
+
+// copy returns a copy of the state of the resource that shares nothing with it and has no client
+func (g *GroupResource) copy() GroupResource {
+	return GroupResource{
+		Groups:         deepCopy(g.Groups),
+		GroupRoles:     deepCopy(g.GroupRoles),
+		RoleTargets:    deepCopy(g.RoleTargets),
+		GroupUsers:     deepCopy(g.GroupUsers),
+		GroupRules:     deepCopy(g.GroupRules),
+		GroupRuleUsers: deepCopy(g.GroupRuleUsers),
+	}
+}
+
+// copy returns a copy of the state of the resource that shares nothing with it and has no client
+func (u *UserResource) copy() UserResource {
+	return UserResource{
+		Users:       deepCopy(u.Users),
+		UserRoles:   deepCopy(u.UserRoles),
+		RoleTargets: deepCopy(u.RoleTargets),
+	}
+}
+
+// copy returns a copy of the state of the resource that shares nothing with it and has no client
+func (s *SchemaResource) copy() SchemaResource {
+	return SchemaResource{
+		UserSchema:  deepCopy(s.UserSchema),
+		GroupSchema: deepCopy(s.GroupSchema),
+	}
+}
+
+// copy returns a copy of the state of the resource that shares nothing with it and has no client
+func (a *ApplicationResource) copy() ApplicationResource {
+	apps := make([]okta.App, 0, len(a.Applications))
+	for _, app := range a.Applications {
+		if app != nil {
+			apps = append(apps, copyAppOfType(app))
+		}
+	}
+	return ApplicationResource{
+		Applications: apps,
+		AppUsers:     deepCopy(a.AppUsers),
+		AppGroups:    deepCopy(a.AppGroups),
+	}
+}
+
+// copy returns a copy of the state of the resource that shares nothing with it and has no client
+func (i *IAMResource) copy() IAMResource {
+	return IAMResource{
+		Roles:        deepCopy(i.Roles),
+		ResourceSets: deepCopy(i.ResourceSets),
+		Resources:    deepCopy(i.Resources),
+		Assignments:  deepCopy(i.Assignments),
+	}
+}
+
+// copyAppOfType returns a copy of the app with the same type. Unlike copyApp it copies apps of any
+// type, like the ones with sign on modes the mock doesn't support that were added to Applications directly
+func copyAppOfType(app okta.App) okta.App {
+	appCopy := reflect.New(reflect.TypeOf(app).Elem()).Interface().(okta.App)
+	data, err := json.Marshal(app)
+	if err == nil {
+		err = json.Unmarshal(data, appCopy)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("mockokta: copying %T: %v", app, err))
+	}
+	return appCopy
+}
+
+// deepCopy returns a copy of v through JSON, like copyAppOfType. The state of the mock is always made of
+// the SDK's JSON types, so failing to copy it is a bug in the mock
+func deepCopy[T any](v T) T {
+	var c T
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("mockokta: copying %T: %v", v, err))
+	}
+	return c
+}
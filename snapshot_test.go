@@ -0,0 +1,106 @@
+package mockokta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func TestMockClient_Snapshot(t *testing.T) {
+	t.Run("should restore the org as it was when the snapshot was taken", func(t *testing.T) {
+		client := NewClient()
+		group, role := newGroupWithRole(t, client, "Admins", "GROUP_ADMIN")
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.AddUserToGroup(context.TODO(), group.Id, user.Id)
+		snapshot := client.Snapshot()
+
+		client.DeleteGroup(context.TODO(), group.Id)
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+		client.DeactivateOrDeleteUser(context.TODO(), user.Id, nil)
+		client.CreateGroup(context.TODO(), *NewGroup("Other"))
+		client.Restore(snapshot)
+
+		groups, _, _ := client.ListGroups(context.TODO(), nil)
+		users, _, _ := client.ListGroupUsers(context.TODO(), group.Id, nil)
+		got, _, err := client.GetRole(context.TODO(), group.Id, role.Id)
+		if len(groups) != 2 || len(users) != 1 || users[0].Id != user.Id {
+			t.Errorf("got groups %v and members %v want the snapshot's", groups, users)
+		}
+		if err != nil || got.Type != "GROUP_ADMIN" {
+			t.Errorf("got role %v (%v) want %v", got, err, role)
+		}
+	})
+
+	t.Run("should not change when the client changes", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		snapshot := client.Snapshot()
+
+		client.User.Users[0].Profile = nil
+		client.Restore(snapshot)
+		client.DeactivateUser(context.TODO(), user.Id, nil)
+		client.Restore(snapshot)
+
+		got, _, err := client.GetUser(context.TODO(), user.Id)
+		if err != nil || got.Status != user.Status || (*got.Profile)["login"] != "TestUser@test.com" {
+			t.Errorf("got %v (%v) want %v", got, err, user)
+		}
+	})
+
+	t.Run("should restore apps and custom roles", func(t *testing.T) {
+		client := NewClient()
+		app, _, _ := client.CreateApplication(context.TODO(), NewBookmarkApplication("Wiki", "https://wiki.test.com"), nil)
+		customRole, resourceSet, _ := newTestBinding(t, client)
+		snapshot := client.Snapshot()
+
+		client.Application.Applications = nil
+		client.IAM.Roles = nil
+		client.IAM.ResourceSets = nil
+		client.Restore(snapshot)
+
+		apps, _, _ := client.ListApplications(context.TODO(), nil)
+		_, _, roleErr := client.GetCustomRole(context.TODO(), customRole.Id)
+		_, _, setErr := client.GetResourceSet(context.TODO(), resourceSet.Id)
+		if len(apps) != 1 || appID(apps[0]) != appID(app) {
+			t.Errorf("got apps %v want %v", apps, app)
+		}
+		if roleErr != nil || setErr != nil {
+			t.Errorf("got %v and %v want no errors", roleErr, setErr)
+		}
+	})
+
+	t.Run("should keep apps of unsupported types", func(t *testing.T) {
+		client := NewClient()
+		legacy := &okta.AutoLoginApplication{Id: "0oaautologin", Label: "Legacy"}
+		wsfed := &okta.Application{Id: "0oawsfed", Label: "WS-Fed", SignOnMode: "WS_FEDERATION"}
+		client.Application.Applications = append(client.Application.Applications, legacy, wsfed)
+
+		snapshot := client.Snapshot()
+		client.Application.Applications = nil
+		client.Restore(snapshot)
+
+		apps := client.Application.Applications
+		if len(apps) != 2 {
+			t.Fatalf("got %v want %v and %v", apps, legacy, wsfed)
+		}
+		legacyCopy, legacyOK := apps[0].(*okta.AutoLoginApplication)
+		wsfedCopy, wsfedOK := apps[1].(*okta.Application)
+		if !legacyOK || !wsfedOK || legacyCopy.Id != legacy.Id || wsfedCopy.SignOnMode != wsfed.SignOnMode {
+			t.Errorf("got %v want %v and %v", apps, legacy, wsfed)
+		}
+	})
+
+	t.Run("should not generate the IDs of restored objects", func(t *testing.T) {
+		seeded := NewClient(WithIDSeed(1))
+		group, _, _ := seeded.CreateGroup(context.TODO(), *NewGroup("TestGroup"))
+		client := NewClient(WithIDSeed(1))
+
+		client.Restore(seeded.Snapshot())
+		created, _, _ := client.CreateGroup(context.TODO(), *NewGroup("OtherGroup"))
+
+		if created.Id == group.Id {
+			t.Errorf("got the restored ID %v again", created.Id)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package mockokta
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+// Okta user schema attribute types and uniqueness, see https://developer.okta.com/docs/reference/api/schemas/#user-profile-schema-property-object
+const (
+	SchemaTypeString  = "string"
+	SchemaTypeBoolean = "boolean"
+	SchemaTypeNumber  = "number"
+	SchemaTypeInteger = "integer"
+	SchemaTypeArray   = "array"
+
+	SchemaUniqueValidated = "UNIQUE_VALIDATED"
+)
+
+// baseUserAttributes are the string attributes of Okta's base user profile other than login and email
+var baseUserAttributes = []string{
+	"firstName", "lastName", "middleName", "honorificPrefix", "honorificSuffix", "title", "displayName",
+	"nickName", "profileUrl", "secondEmail", "mobilePhone", "primaryPhone", "streetAddress", "city", "state",
+	"zipCode", "countryCode", "postalAddress", "preferredLanguage", "locale", "timezone", "userType",
+	"employeeNumber", "costCenter", "organization", "division", "department", "managerId", "manager",
+}
+
+// NewDefaultUserSchema returns the user schema new clients validate profiles against: Okta's base
+// profile with login and email required, and no custom attributes. Unlike a new Okta org firstName
+// and lastName are optional, so users can be created from just an email
+func NewDefaultUserSchema() *okta.UserSchema {
+	minLogin, maxLogin := int64(5), int64(100)
+	properties := map[string]*okta.UserSchemaAttribute{
+		"login": {
+			Title:     "Username",
+			Type:      SchemaTypeString,
+			Required:  boolPtr(true),
+			MinLength: &minLogin,
+			MaxLength: &maxLogin,
+			Unique:    SchemaUniqueValidated,
+		},
+		"email": {Title: "Primary email", Type: SchemaTypeString, Required: boolPtr(true)},
+	}
+	for _, name := range baseUserAttributes {
+		properties[name] = &okta.UserSchemaAttribute{Title: name, Type: SchemaTypeString}
+	}
+	return &okta.UserSchema{
+		Id:    "#base",
+		Name:  "user",
+		Title: "User",
+		Type:  "object",
+		Definitions: &okta.UserSchemaDefinitions{
+			Base: &okta.UserSchemaBase{
+				Id:         "#base",
+				Type:       "object",
+				Properties: properties,
+				Required:   []string{"login", "email"},
+			},
+			Custom: &okta.UserSchemaPublic{
+				Id:         "#custom",
+				Type:       "object",
+				Properties: make(map[string]*okta.UserSchemaAttribute),
+				Required:   make([]string, 0),
+			},
+		},
+	}
+}
+
+// WithUserSchema makes the client validate user profiles against the schema instead of the default one
+func WithUserSchema(schema *okta.UserSchema) ClientOption {
+	return func(c *MockClient) {
+		c.Schema.UserSchema = schema
+	}
+}
+
+// schemaAttributes returns the base and custom attributes of the schema, and the names of the required ones
+func schemaAttributes(schema *okta.UserSchema) (map[string]*okta.UserSchemaAttribute, []string) {
+	attributes := make(map[string]*okta.UserSchemaAttribute)
+	required := make([]string, 0)
+	if schema == nil || schema.Definitions == nil {
+		return attributes, required
+	}
+	if base := schema.Definitions.Base; base != nil {
+		for name, attribute := range base.Properties {
+			attributes[name] = attribute
+		}
+		required = append(required, base.Required...)
+	}
+	if custom := schema.Definitions.Custom; custom != nil {
+		for name, attribute := range custom.Properties {
+			attributes[name] = attribute
+		}
+		required = append(required, custom.Required...)
+	}
+	for name, attribute := range attributes {
+		if attribute != nil && attribute.Required != nil && *attribute.Required && !SliceContainsString(required, name) {
+			required = append(required, name)
+		}
+	}
+	return attributes, required
+}
+
+// validateProfile checks a profile for the user with the userID, or a new user if it is empty, against
+// the user schema, returning the first attribute that is missing, undefined, invalid or not unique
+func (u *UserResource) validateProfile(userID string, profile okta.UserProfile) *okta.Error {
+	attributes, required := schemaAttributes(u.Client.Schema.UserSchema)
+	if err := validateAttributes(attributes, required, profile); err != nil {
+		return err
+	}
+	for _, name := range sortedKeys(profile) {
+		value := profile[name]
+		if value == nil || attributes[name].Unique != SchemaUniqueValidated {
+			continue
+		}
+		for _, user := range u.Users {
+			if user.Id != userID && user.Profile != nil && attributeValuesEqual((*user.Profile)[name], value) {
+				return newValidationError(name, "An object with this field already exists in the current organization")
+			}
+		}
+	}
+	return nil
+}
+
+// validateAttributes checks the profile has every required attribute and that every attribute in it
+// is defined and has a valid value, returning the first one that isn't
+func validateAttributes(attributes map[string]*okta.UserSchemaAttribute, required []string, profile map[string]interface{}) *okta.Error {
+	for _, name := range required {
+		if value, ok := profile[name]; !ok || value == nil || value == "" {
+			return newValidationError(name, "The field cannot be left blank")
+		}
+	}
+	for _, name := range sortedKeys(profile) {
+		value := profile[name]
+		attribute, ok := attributes[name]
+		if !ok || attribute == nil {
+			return newValidationError(name, fmt.Sprintf("Property name '%s' is not defined in profile", name))
+		}
+		if value == nil {
+			continue
+		}
+		if cause := validateAttributeValue(attribute, value); cause != "" {
+			return newValidationError(name, cause)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateAttributeValue returns why the value is not valid for the attribute, or an empty string if it is
+func validateAttributeValue(attribute *okta.UserSchemaAttribute, value interface{}) string {
+	switch attribute.Type {
+	case SchemaTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return "Does not match required type: string"
+		}
+		length := int64(len([]rune(s)))
+		if (attribute.MinLength != nil && length < *attribute.MinLength) || (attribute.MaxLength != nil && length > *attribute.MaxLength) {
+			return fmt.Sprintf("size must be between %d and %d", valueOr(attribute.MinLength, 0), valueOr(attribute.MaxLength, math.MaxInt32))
+		}
+		if attribute.Pattern != nil {
+			if re, err := regexp.Compile("^(?:" + *attribute.Pattern + ")$"); err == nil && !re.MatchString(s) {
+				return "Does not match required pattern"
+			}
+		}
+	case SchemaTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return "Does not match required type: boolean"
+		}
+	case SchemaTypeNumber:
+		if _, ok := toFloat(value); !ok {
+			return "Does not match required type: number"
+		}
+	case SchemaTypeInteger:
+		if f, ok := toFloat(value); !ok || f != math.Trunc(f) {
+			return "Does not match required type: integer"
+		}
+	case SchemaTypeArray:
+		items := toSlice(value)
+		if items == nil {
+			if _, ok := value.([]interface{}); !ok {
+				return "Does not match required type: array"
+			}
+		}
+		if attribute.Items != nil && attribute.Items.Type != "" {
+			for _, item := range items {
+				if validateAttributeValue(&okta.UserSchemaAttribute{Type: attribute.Items.Type}, item) != "" {
+					return fmt.Sprintf("Does not match required type: array of %s", attribute.Items.Type)
+				}
+			}
+		}
+	}
+	if len(attribute.Enum) > 0 {
+		for _, allowed := range attribute.Enum {
+			if attributeValuesEqual(allowed, value) {
+				return ""
+			}
+		}
+		return "Does not match required enumeration values"
+	}
+	return ""
+}
+
+// attributeValuesEqual compares profile values like Okta, ignoring case for strings and the Go type of numbers
+func attributeValuesEqual(a interface{}, b interface{}) bool {
+	if sa, ok := a.(string); ok {
+		sb, ok := b.(string)
+		return ok && strings.EqualFold(sa, sb)
+	}
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func valueOr(p *int64, fallback int64) int64 {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
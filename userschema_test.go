@@ -0,0 +1,216 @@
+package mockokta
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/okta/okta-sdk-golang/v2/okta"
+)
+
+func newTestUserSchema() *okta.UserSchema {
+	schema := NewDefaultUserSchema()
+	pattern := "[0-9]{4}"
+	schema.Definitions.Base.Properties["firstName"].Required = boolPtr(true)
+	schema.Definitions.Custom.Properties["employeeId"] = &okta.UserSchemaAttribute{
+		Type:    SchemaTypeString,
+		Pattern: &pattern,
+		Unique:  SchemaUniqueValidated,
+	}
+	schema.Definitions.Custom.Properties["isContractor"] = &okta.UserSchemaAttribute{Type: SchemaTypeBoolean}
+	schema.Definitions.Custom.Properties["level"] = &okta.UserSchemaAttribute{Type: SchemaTypeInteger}
+	schema.Definitions.Custom.Properties["region"] = &okta.UserSchemaAttribute{
+		Type: SchemaTypeString,
+		Enum: []interface{}{"EMEA", "AMER", "APAC"},
+	}
+	schema.Definitions.Custom.Properties["teams"] = &okta.UserSchemaAttribute{
+		Type:  SchemaTypeArray,
+		Items: &okta.UserSchemaAttributeItems{Type: SchemaTypeString},
+	}
+	return schema
+}
+
+func newTestUserRequest(email string, attributes map[string]interface{}) okta.CreateUserRequest {
+	request := NewCreateUserRequest(email)
+	(*request.Profile)["firstName"] = "Test"
+	for name, value := range attributes {
+		(*request.Profile)[name] = value
+	}
+	return request
+}
+
+func TestUserResource_Schema(t *testing.T) {
+	t.Run("should create users with base and custom attributes", func(t *testing.T) {
+		client := NewClient(WithUserSchema(newTestUserSchema()))
+
+		user, _, err := client.User.CreateUser(context.TODO(), newTestUserRequest("TestUser@test.com", map[string]interface{}{
+			"lastName":     "User",
+			"department":   "Engineering",
+			"employeeId":   "1234",
+			"isContractor": false,
+			"level":        float64(3),
+			"region":       "EMEA",
+			"teams":        []interface{}{"platform", "security"},
+		}), nil)
+
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if (*user.Profile)["department"] != "Engineering" {
+			t.Errorf("got profile %v want department Engineering", *user.Profile)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		field      string
+	}{
+		{"should err if a required attribute is missing", map[string]interface{}{"firstName": ""}, "firstName"},
+		{"should err if an attribute is not in the schema", map[string]interface{}{"favouriteColour": "blue"}, "favouriteColour"},
+		{"should err if a string attribute has another type", map[string]interface{}{"department": 42}, "department"},
+		{"should err if a boolean attribute has another type", map[string]interface{}{"isContractor": "no"}, "isContractor"},
+		{"should err if an integer attribute is a fraction", map[string]interface{}{"level": 1.5}, "level"},
+		{"should err if a value doesn't match the pattern", map[string]interface{}{"employeeId": "12345"}, "employeeId"},
+		{"should err if a value isn't one of the enum values", map[string]interface{}{"region": "MARS"}, "region"},
+		{"should err if an array item has another type", map[string]interface{}{"teams": []interface{}{"platform", 1}}, "teams"},
+		{"should err if the login is too short", map[string]interface{}{"login": "a@b"}, "login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(WithUserSchema(newTestUserSchema()))
+
+			_, resp, err := client.User.CreateUser(context.TODO(), newTestUserRequest("TestUser@test.com", tt.attributes), nil)
+
+			oktaErr, ok := err.(*okta.Error)
+			if !ok || oktaErr.ErrorCode != ErrorCodeValidation || resp.StatusCode != 400 {
+				t.Fatalf("got %v want an %v error", err, ErrorCodeValidation)
+			}
+			if !strings.HasSuffix(oktaErr.ErrorSummary, ": "+tt.field) {
+				t.Errorf("got summary %v want it to name %v", oktaErr.ErrorSummary, tt.field)
+			}
+		})
+	}
+
+	t.Run("should err if a unique attribute is taken", func(t *testing.T) {
+		client := NewClient(WithUserSchema(newTestUserSchema()))
+		client.User.CreateUser(context.TODO(), newTestUserRequest("TestUser1@test.com", map[string]interface{}{"employeeId": "1234"}), nil)
+
+		_, _, err := client.User.CreateUser(context.TODO(), newTestUserRequest("TestUser2@test.com", map[string]interface{}{"employeeId": "1234"}), nil)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should err if the login is taken ignoring case", func(t *testing.T) {
+		client := NewClient()
+		client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		request := NewCreateUserRequest("other@test.com")
+		(*request.Profile)["login"] = "testuser@TEST.com"
+		_, _, err := client.User.CreateUser(context.TODO(), request, nil)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+}
+
+func TestUserResource_UpdateUser(t *testing.T) {
+	t.Run("should replace the profile", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		client.User.PartialUpdateUser(context.TODO(), user.Id, okta.User{Profile: &okta.UserProfile{"title": "Engineer"}}, nil)
+
+		got, _, err := client.UpdateUser(context.TODO(), user.Id, okta.User{Profile: &okta.UserProfile{
+			"login":      "TestUser@test.com",
+			"email":      "TestUser@test.com",
+			"department": "Sales",
+		}}, nil)
+
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if (*got.Profile)["department"] != "Sales" || (*got.Profile)["title"] != nil {
+			t.Errorf("got profile %v want only department Sales set", *got.Profile)
+		}
+	})
+
+	t.Run("should err if the profile is missing a required attribute", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		_, _, err := client.UpdateUser(context.TODO(), user.Id, okta.User{Profile: &okta.UserProfile{"login": "TestUser@test.com"}}, nil)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+		if (*user.Profile)["email"] != "TestUser@test.com" {
+			t.Errorf("expected the profile to be unchanged but got %v", *user.Profile)
+		}
+	})
+
+	t.Run("should err if the user doesn't exist", func(t *testing.T) {
+		client := NewClient()
+
+		_, resp, err := client.UpdateUser(context.TODO(), "00u_missing", okta.User{Profile: &okta.UserProfile{}}, nil)
+
+		if err == nil || resp.StatusCode != 404 {
+			t.Errorf("got %v want a not found error", err)
+		}
+	})
+}
+
+func TestUserResource_PartialUpdateUser(t *testing.T) {
+	t.Run("should only set the attributes passed in", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		got, _, err := client.PartialUpdateUser(context.TODO(), "testuser@test.com", okta.User{Profile: &okta.UserProfile{"department": "Engineering"}}, nil)
+
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if got.Id != user.Id || (*got.Profile)["department"] != "Engineering" || (*got.Profile)["email"] != "TestUser@test.com" {
+			t.Errorf("got profile %v want department set and email kept", *got.Profile)
+		}
+	})
+
+	t.Run("should allow keeping the user's own login", func(t *testing.T) {
+		client := NewClient()
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+
+		_, _, err := client.PartialUpdateUser(context.TODO(), user.Id, okta.User{Profile: &okta.UserProfile{"login": "testuser@test.com"}}, nil)
+
+		if err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("should err if the login is taken by another user", func(t *testing.T) {
+		client := NewClient()
+		client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser1@test.com"), nil)
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser2@test.com"), nil)
+
+		_, _, err := client.PartialUpdateUser(context.TODO(), user.Id, okta.User{Profile: &okta.UserProfile{"login": "TestUser1@test.com"}}, nil)
+
+		if err == nil {
+			t.Errorf("expected error but didn't get one")
+		}
+	})
+
+	t.Run("should reapply group rules when attributes change", func(t *testing.T) {
+		client := NewClient()
+		group, _, _ := client.Group.CreateGroup(context.TODO(), *NewGroup("Engineering"))
+		user, _, _ := client.User.CreateUser(context.TODO(), NewCreateUserRequest("TestUser@test.com"), nil)
+		rule, _, _ := client.CreateGroupRule(context.TODO(), NewGroupRule("Engineers", `user.department == "Engineering"`, group.Id))
+		client.ActivateGroupRule(context.TODO(), rule.Id)
+
+		client.PartialUpdateUser(context.TODO(), user.Id, okta.User{Profile: &okta.UserProfile{"department": "Engineering"}}, nil)
+
+		if !client.Group.GroupContainsUser(*group, "TestUser@test.com") {
+			t.Errorf("expected the rule to add the user to the group")
+		}
+	})
+}